@@ -0,0 +1,219 @@
+package unifi
+
+import (
+	"context"
+	"time"
+)
+
+// The interfaces below group the Client api by domain, depend on the smallest one you need
+// so it can be replaced by a mock from the unifimock package in tests.
+//
+//go:generate go run ./unifimock/internal/mockgen -source api.go -out unifimock/mocks.go
+
+// Session manages the authentication with the controller.
+type Session interface {
+	Login(ctx context.Context, username string, password string, remember bool) error
+	LoginWithProvider(ctx context.Context, provider CredentialProvider, remember bool) error
+	Logout(ctx context.Context) error
+	Self(ctx context.Context, opts ...RequestOption) (*SelfResponse, error)
+	ExportSession() (*SessionState, error)
+	ImportSession(state *SessionState) error
+}
+
+// SiteReader reads the controller and site status and configuration.
+type SiteReader interface {
+	ControllerStatus(ctx context.Context, opts ...RequestOption) (*ControllerStatus, error)
+	AvailableSites(ctx context.Context, opts ...RequestOption) (*SitesResponse, error)
+	AvailableSitesVerbose(ctx context.Context, opts ...RequestOption) (*SitesVerboseResponse, error)
+	SiteAdmins(ctx context.Context, opts ...RequestOption) (*SiteAdminsResponse, error)
+	SiteHealth(ctx context.Context, site string, opts ...RequestOption) (*SiteHealthResponse, error)
+	SiteSysInfo(ctx context.Context, site string, opts ...RequestOption) (*SiteSysInfoResponse, error)
+	SiteCountryCodes(ctx context.Context, site string, opts ...RequestOption) (*SiteCountryCodesResponse, error)
+	SiteCurrentChannels(ctx context.Context, site string, opts ...RequestOption) (*SiteCurrentChannelsResponse, error)
+	SiteDetailedSettings(ctx context.Context, site string, opts ...RequestOption) (*SiteDetailedSettingsResponse, error)
+	SiteActiveRoutes(ctx context.Context, site string, opts ...RequestOption) (*SiteActiveRoutesResponse, error)
+	SiteUserDefinedRoutes(ctx context.Context, site string, opts ...RequestOption) (*SiteUserDefinedRoutesResponse, error)
+	SiteTaggedMACs(ctx context.Context, site string, opts ...RequestOption) (*SiteTaggedMACResponse, error)
+	SiteWLANConfigs(ctx context.Context, site string, opts ...RequestOption) (*SiteWLANConfigResponse, error)
+	SiteWLANGroups(ctx context.Context, site string, opts ...RequestOption) (*SiteWLANGroupResponse, error)
+	SiteRougeAccessPoints(ctx context.Context, site string, seenWithinHours int, opts ...RequestOption) (*SiteRougeAccessPointResponse, error)
+	SiteRougeKnownAccessPoints(ctx context.Context, site string, opts ...RequestOption) (*SiteRougeAccessPointResponse, error)
+	ListDashboardMetrics(ctx context.Context, site string, scale5Min bool, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// SiteManager manages sites, their settings, admins and backups.
+type SiteManager interface {
+	AddSite(ctx context.Context, site string, name string, description string, opts ...RequestOption) (*GenericResponse, error)
+	UpdateSite(ctx context.Context, site string, description string, opts ...RequestOption) (*GenericResponse, error)
+	DeleteSite(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteCountry(ctx context.Context, site string, siteID string, configID string, country SiteCountryCode, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteTimezone(ctx context.Context, site string, siteID string, configID string, timezone string, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteSNMP(ctx context.Context, site string, siteID string, configID string, community string, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteManagementConfig(ctx context.Context, site string, siteID string, configID string, config SiteManagementConfig, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteGuestAccessConfig(ctx context.Context, site string, siteID string, configID string, config SiteGuestAccessConfig, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteNTPConfig(ctx context.Context, site string, siteID string, configID string, config SiteNTPConfig, opts ...RequestOption) (*GenericResponse, error)
+	SetSiteConnectivityConfig(ctx context.Context, site string, siteID string, configID string, uplinkType string, opts ...RequestOption) (*GenericResponse, error)
+	GetSiteAdmins(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	InviteSiteAdmin(ctx context.Context, site string, name string, email string, disableSSO bool, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...RequestOption) (*GenericResponse, error)
+	AssignExistingSiteAdmin(ctx context.Context, site string, adminID string, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...RequestOption) (*GenericResponse, error)
+	RevokeSiteAdmin(ctx context.Context, site string, adminID string, opts ...RequestOption) (*GenericResponse, error)
+	ResetDPICounters(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	ListBackups(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	CreateBackup(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	DeleteBackup(ctx context.Context, site string, filename string, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// DeviceManager lists and manages the network devices of a site.
+type DeviceManager interface {
+	SiteDevicesBasic(ctx context.Context, site string, typeFilter string, opts ...RequestOption) (*SiteDeviceBasicResponse, error)
+	SiteDevicesDetailed(ctx context.Context, site string, filterMACs ...string) (*SiteDeviceDetailedResponse, error)
+	SiteDevicesDetailedWithOptions(ctx context.Context, site string, filterMACs []string, opts ...RequestOption) (*SiteDeviceDetailedResponse, error)
+	AdoptDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	RestartDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	ForceProvisionDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	PowerCycleDevice(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error)
+	StartSpeedTest(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	SpeedTestStatus(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	SetLocateDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	UnsetLocateDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	UpgradeDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	UpgradeExternalDevice(ctx context.Context, site string, mac string, firmwareURL string, opts ...RequestOption) (*GenericResponse, error)
+	SpectrumScanDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	MoveDevice(ctx context.Context, site string, mac string, newSiteID string, opts ...RequestOption) (*GenericResponse, error)
+	DeleteDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// ClientManager lists and manages the clients of a site, their user groups and sessions.
+type ClientManager interface {
+	SiteActiveClients(ctx context.Context, site string, filterMac string, opts ...RequestOption) (*SiteActiveClientsResponse, error)
+	ClientDetails(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	UpdateClientFixedIP(ctx context.Context, site string, clientID string, useFixedIP bool, networkID *string, fixedIP *string, opts ...RequestOption) (*GenericResponse, error)
+	CreateNewUserClientDevice(ctx context.Context, site string, mac string, userGroupID string, name string, note string, opts ...RequestOption) (*GenericResponse, error)
+	SetUserClientDeviceNote(ctx context.Context, site string, userID string, note string, opts ...RequestOption) (*GenericResponse, error)
+	SetUserClientDeviceName(ctx context.Context, site string, userID string, name string, opts ...RequestOption) (*GenericResponse, error)
+	BlockSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	UnblockSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	KickSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	ForgetSTA(ctx context.Context, site string, macs ...string) (*GenericResponse, error)
+	ForgetSTAWithOptions(ctx context.Context, site string, macs []string, opts ...RequestOption) (*GenericResponse, error)
+	ListUserGroups(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	CreateUserGroup(ctx context.Context, site string, siteID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...RequestOption) (*GenericResponse, error)
+	UpdateUserGroup(ctx context.Context, site string, siteID string, groupID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...RequestOption) (*GenericResponse, error)
+	DeleteUserGroup(ctx context.Context, site string, groupID string, opts ...RequestOption) (*GenericResponse, error)
+	AssignClientUserGroup(ctx context.Context, site string, clientID string, groupID string, opts ...RequestOption) (*GenericResponse, error)
+	ListLoginSessions(ctx context.Context, site string, sessionType SessionType, startTime time.Time, endTime time.Time, mac string, opts ...RequestOption) (*GenericResponse, error)
+	ListLatestSessions(ctx context.Context, site string, mac string, order SiteSessionOrder, offset int, limit int, opts ...RequestOption) (*GenericResponse, error)
+	ListAuthorizations(ctx context.Context, site string, startTime time.Time, endTime time.Time, opts ...RequestOption) (*GenericResponse, error)
+	ListAllUsers(ctx context.Context, site string, withinHours int, offset int, limit int, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// GuestManager manages the hotspot guests, vouchers and operators of a site.
+type GuestManager interface {
+	AuthorizeWiFiGuest(ctx context.Context, site string, mac string, duration time.Duration, wifiGuestConfig *WifiGuestConfig, opts ...RequestOption) (*GenericResponse, error)
+	UnAuthorizeWiFiGuest(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error)
+	ListWiFiGuests(ctx context.Context, site string, withinHours int, opts ...RequestOption) (*GenericResponse, error)
+	ListWiFiGuestVouchers(ctx context.Context, site string, createTime time.Time, opts ...RequestOption) (*GenericResponse, error)
+	ListWiFiGuestPayments(ctx context.Context, site string, withinHours int, opts ...RequestOption) (*GenericResponse, error)
+	CreateWifiGuestOperator(ctx context.Context, site string, name string, password string, note string, opts ...RequestOption) (*GenericResponse, error)
+	ListWiFiGuestOperators(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error)
+	CreateWifiGuestVoucher(ctx context.Context, site string, cfg VoucherConfig, opts ...RequestOption) (*GenericResponse, error)
+	RevokeWifiGuestVoucher(ctx context.Context, site string, voucherID string, opts ...RequestOption) (*GenericResponse, error)
+	ExtendWifiGuestValidity(ctx context.Context, site string, guestID string, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// FirewallManager manages the firewall rules and groups of a site.
+type FirewallManager interface {
+	SiteFirewallRules(ctx context.Context, site string, opts ...RequestOption) (*SiteFirewallRuleResponse, error)
+	ListFirewallRules(ctx context.Context, site string, opts ...RequestOption) (*FirewallRuleResponse, error)
+	GetFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRule, error)
+	CreateFirewallRule(ctx context.Context, site string, rule FirewallRule, opts ...RequestOption) (*FirewallRuleResponse, error)
+	UpdateFirewallRule(ctx context.Context, site string, rule FirewallRule, opts ...RequestOption) (*FirewallRuleResponse, error)
+	DeleteFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*GenericResponse, error)
+	EnableFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRuleResponse, error)
+	DisableFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRuleResponse, error)
+	ReorderFirewallRules(ctx context.Context, site string, ruleset FirewallRuleset, ruleIDs []string, opts ...RequestOption) error
+	SiteFirewallGroups(ctx context.Context, site string, groupID string, opts ...RequestOption) (*SiteFirewallGroupResponse, error)
+	CreateFirewallGroup(ctx context.Context, site string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers, opts ...RequestOption) (*GenericResponse, error)
+	UpdateFirewallGroup(ctx context.Context, site string, siteID string, groupID string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers, opts ...RequestOption) (*GenericResponse, error)
+	DeleteFirewallGroup(ctx context.Context, site string, groupID string, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// NetworkManager manages the networks of a site.
+type NetworkManager interface {
+	ListNetworks(ctx context.Context, site string, opts ...RequestOption) (*NetworkConfResponse, error)
+	GetNetwork(ctx context.Context, site string, networkID string, opts ...RequestOption) (*NetworkConf, error)
+	CreateNetwork(ctx context.Context, site string, network NetworkConf, opts ...RequestOption) (*NetworkConfResponse, error)
+	UpdateNetwork(ctx context.Context, site string, network NetworkConf, opts ...RequestOption) (*NetworkConfResponse, error)
+	DeleteNetwork(ctx context.Context, site string, networkID string, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// PortForwardManager manages the port forwards of a site.
+type PortForwardManager interface {
+	ListPortForwards(ctx context.Context, site string, opts ...RequestOption) (*PortForwardResponse, error)
+	GetPortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForward, error)
+	CreatePortForward(ctx context.Context, site string, forward PortForward, opts ...RequestOption) (*PortForwardResponse, error)
+	UpdatePortForward(ctx context.Context, site string, forward PortForward, opts ...RequestOption) (*PortForwardResponse, error)
+	DeletePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*GenericResponse, error)
+	EnablePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForwardResponse, error)
+	DisablePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForwardResponse, error)
+}
+
+// SwitchPortManager manages the switch port profiles and the per port overrides of switches.
+type SwitchPortManager interface {
+	ListPortProfiles(ctx context.Context, site string, opts ...RequestOption) (*PortProfileResponse, error)
+	GetPortProfile(ctx context.Context, site string, profileID string, opts ...RequestOption) (*PortProfile, error)
+	CreatePortProfile(ctx context.Context, site string, profile PortProfile, opts ...RequestOption) (*PortProfileResponse, error)
+	UpdatePortProfile(ctx context.Context, site string, profile PortProfile, opts ...RequestOption) (*PortProfileResponse, error)
+	DeletePortProfile(ctx context.Context, site string, profileID string, opts ...RequestOption) (*GenericResponse, error)
+	SetPortOverride(ctx context.Context, site string, mac string, override PortOverride, opts ...RequestOption) (*GenericResponse, error)
+	ClearPortOverride(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error)
+	SetPortProfile(ctx context.Context, site string, mac string, portIdx int, profileID string, opts ...RequestOption) (*GenericResponse, error)
+	SetPortPoEMode(ctx context.Context, site string, mac string, portIdx int, mode PoEMode, opts ...RequestOption) (*GenericResponse, error)
+	DisablePort(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error)
+	EnablePort(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error)
+}
+
+// WLANManager manages the wireless networks of a site.
+type WLANManager interface {
+	ListWLANs(ctx context.Context, site string, opts ...RequestOption) (*WLANConfResponse, error)
+	GetWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConf, error)
+	CreateWLAN(ctx context.Context, site string, wlan WLANConf, opts ...RequestOption) (*WLANConfResponse, error)
+	UpdateWLAN(ctx context.Context, site string, wlan WLANConf, opts ...RequestOption) (*WLANConfResponse, error)
+	DeleteWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*GenericResponse, error)
+	EnableWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConfResponse, error)
+	DisableWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConfResponse, error)
+}
+
+// Reporter reads the events, alarms and reports of a site.
+type Reporter interface {
+	SiteEvents(ctx context.Context, site string, historyHours int, offset int, limit int, order EventSortOrder, opts ...RequestOption) (*SiteEventsResponse, error)
+	SiteIPSEvents(ctx context.Context, site string, startTime time.Time, endTime time.Time, offset int, limit int, order EventSortOrder, opts ...RequestOption) (*SiteEventsResponse, error)
+	SiteAlarms(ctx context.Context, site string, historyHours int, offset int, limit int, order EventSortOrder, archived bool, opts ...RequestOption) (*SiteAlarmsResponse, error)
+	SiteAlarmsCount(ctx context.Context, site string, historyHours int, archived bool, opts ...RequestOption) (*SiteAlarmsCountResponse, error)
+	ArchiveAllAlarms(ctx context.Context, site string, opts ...RequestOption) error
+	SubscribeEvents(ctx context.Context, site string) (<-chan StreamEvent, error)
+	SiteReport(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval ReportInterval, reportType ReportType, attributes []ReportAttribute, filterMacs ...string) (*SiteReportsResponse, error)
+	SiteReportWithOptions(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval ReportInterval, reportType ReportType, attributes []ReportAttribute, filterMacs []string, opts ...RequestOption) (*SiteReportsResponse, error)
+	EventsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder) *EventsIterator
+	IPSEventsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder) *EventsIterator
+	AlarmsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder, archived bool) *AlarmsIterator
+	SessionsIterator(ctx context.Context, site string, mac string, since time.Time, order SiteSessionOrder) *SessionsIterator
+}
+
+// API is the complete controller api implemented by Client.
+type API interface {
+	Session
+	SiteReader
+	SiteManager
+	DeviceManager
+	ClientManager
+	GuestManager
+	FirewallManager
+	NetworkManager
+	PortForwardManager
+	SwitchPortManager
+	WLANManager
+	Reporter
+}
+
+var _ API = (*Client)(nil)
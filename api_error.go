@@ -0,0 +1,49 @@
+package unifi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the controller answers a request with a non-ok response code or an http error status.
+// Use errors.Is with ErrNoPermission, ErrInvalid, ErrLoginRequired, ErrNotFound or ErrControllerUnavailable
+// to branch on the kind of failure, or errors.As to inspect the details.
+type APIError struct {
+	StatusCode   int          // http status code of the response
+	ResponseCode ResponseCode // meta.rc of the response, empty if the response had no meta
+	Message      string       // meta.msg of the response, like `api.err.NoPermission`
+	Method       string       // http method of the request
+	Endpoint     string       // path of the request
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf("non-ok response code: %q", e.ResponseCode)
+		if e.ResponseCode == "" {
+			msg = http.StatusText(e.StatusCode)
+		}
+	}
+	return fmt.Sprintf("%s %s: %s (http %d)", e.Method, e.Endpoint, msg, e.StatusCode)
+}
+
+// Is allows matching the error against the sentinel errors with errors.Is
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNoPermission:
+		return e.Message == APINoPermissionError || e.StatusCode == http.StatusForbidden
+	case ErrInvalid:
+		return strings.HasPrefix(e.Message, APIInvalidError) || e.StatusCode == http.StatusBadRequest
+	case ErrLoginRequired:
+		return e.Message == APILoginRequiredError || e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return strings.HasSuffix(e.Message, "NotFound") || e.StatusCode == http.StatusNotFound
+	case ErrControllerUnavailable:
+		return e.StatusCode == http.StatusBadGateway ||
+			e.StatusCode == http.StatusServiceUnavailable ||
+			e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
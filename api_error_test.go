@@ -0,0 +1,101 @@
+package unifi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/platinummonkey/unifi"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{
+		unifi.ErrNoPermission,
+		unifi.ErrInvalid,
+		unifi.ErrLoginRequired,
+		unifi.ErrNotFound,
+		unifi.ErrControllerUnavailable,
+	}
+	tests := []struct {
+		name string
+		err  unifi.APIError
+		want []error
+	}{
+		{name: "no permission message", err: unifi.APIError{StatusCode: http.StatusOK, Message: "api.err.NoPermission"},
+			want: []error{unifi.ErrNoPermission}},
+		{name: "forbidden status", err: unifi.APIError{StatusCode: http.StatusForbidden},
+			want: []error{unifi.ErrNoPermission}},
+		{name: "invalid message", err: unifi.APIError{StatusCode: http.StatusOK, Message: "api.err.Invalid"},
+			want: []error{unifi.ErrInvalid}},
+		{name: "invalid message variant", err: unifi.APIError{StatusCode: http.StatusOK, Message: "api.err.InvalidPayload"},
+			want: []error{unifi.ErrInvalid}},
+		{name: "bad request status", err: unifi.APIError{StatusCode: http.StatusBadRequest, Message: "api.err.IdInvalid"},
+			want: []error{unifi.ErrInvalid}},
+		{name: "login required message", err: unifi.APIError{StatusCode: http.StatusOK, Message: "api.err.LoginRequired"},
+			want: []error{unifi.ErrLoginRequired}},
+		{name: "unauthorized status", err: unifi.APIError{StatusCode: http.StatusUnauthorized},
+			want: []error{unifi.ErrLoginRequired}},
+		{name: "not found message", err: unifi.APIError{StatusCode: http.StatusOK, Message: "api.err.ObjectNotFound"},
+			want: []error{unifi.ErrNotFound}},
+		{name: "not found status", err: unifi.APIError{StatusCode: http.StatusNotFound},
+			want: []error{unifi.ErrNotFound}},
+		{name: "bad gateway", err: unifi.APIError{StatusCode: http.StatusBadGateway},
+			want: []error{unifi.ErrControllerUnavailable}},
+		{name: "service unavailable", err: unifi.APIError{StatusCode: http.StatusServiceUnavailable},
+			want: []error{unifi.ErrControllerUnavailable}},
+		{name: "gateway timeout", err: unifi.APIError{StatusCode: http.StatusGatewayTimeout},
+			want: []error{unifi.ErrControllerUnavailable}},
+		{name: "message and status", err: unifi.APIError{StatusCode: http.StatusBadRequest, Message: "api.err.NoPermission"},
+			want: []error{unifi.ErrNoPermission, unifi.ErrInvalid}},
+		{name: "unknown message", err: unifi.APIError{StatusCode: http.StatusOK, ResponseCode: unifi.ResponseCodeError, Message: "api.err.Busy"}},
+		{name: "internal server error", err: unifi.APIError{StatusCode: http.StatusInternalServerError}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := tt.err
+			// callers get the error wrapped by the client methods
+			err := pkgerrors.Wrap(pkgerrors.Wrapf(&apiErr, "list devices"), "reporter")
+			for _, sentinel := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					want = want || w == sentinel
+				}
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", err, sentinel, got, want)
+				}
+			}
+
+			var target *unifi.APIError
+			if !errors.As(err, &target) || *target != tt.err {
+				t.Fatalf("errors.As did not return the APIError, got %+v", target)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  unifi.APIError
+		want string
+	}{
+		{
+			err:  unifi.APIError{StatusCode: http.StatusBadRequest, ResponseCode: unifi.ResponseCodeError, Message: "api.err.Invalid", Method: http.MethodPost, Endpoint: "/api/login"},
+			want: "POST /api/login: api.err.Invalid (http 400)",
+		},
+		{
+			err:  unifi.APIError{StatusCode: http.StatusOK, ResponseCode: unifi.ResponseCodeError, Method: http.MethodGet, Endpoint: "/api/self"},
+			want: `GET /api/self: non-ok response code: "error" (http 200)`,
+		},
+		{
+			err:  unifi.APIError{StatusCode: http.StatusBadGateway, Method: http.MethodGet, Endpoint: "/api/self"},
+			want: "GET /api/self: Bad Gateway (http 502)",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -2,10 +2,16 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 // LoginResponse is the login response
@@ -14,61 +20,240 @@ type LoginResponse struct {
 	Data []map[string]interface{} `json:"data"`
 }
 
+// CredentialProvider supplies the credentials used to (re-)authenticate against the controller.
+// The provider is consulted again whenever the controller invalidates the current session.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (username string, password string, err error)
+}
+
+// StaticCredentials is a CredentialProvider that always returns the same username and password.
+type StaticCredentials struct {
+	Username string
+	Password string
+}
+
+// Credentials implements CredentialProvider
+func (s StaticCredentials) Credentials(ctx context.Context) (string, string, error) {
+	return s.Username, s.Password, nil
+}
+
+// SetCredentialProvider sets the credentials used to transparently log in again when the session expires.
+// remember - if true request long-running sessions on re-login
+func (c *Client) SetCredentialProvider(provider CredentialProvider, remember bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.credentials = provider
+	c.longRunningSession = remember
+}
+
+// hasCredentials returns true when the client is able to log in again on its own
+func (c *Client) hasCredentials() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.credentials != nil
+}
+
 // Login will login the user for making queries
 // if remember=true for long-running sessions.
 // the API will return HTTP200 for success and a cookie that is your session,
-// this method will store this for future commands automatically. Though it is not thread-safe.
-func (c *Client) Login(username string, password string, remember bool) error {
-	// we do this one manually to acquire cookies
-	rememberStr := "false"
-	if remember {
-		rememberStr = "true"
+// this method will store this for future commands automatically.
+// The credentials are kept so the session can be re-established if the controller expires it.
+func (c *Client) Login(ctx context.Context, username string, password string, remember bool) error {
+	return c.LoginWithProvider(ctx, StaticCredentials{Username: username, Password: password}, remember)
+}
+
+// LoginWithProvider will login using the credentials from the provider, which is also used for any later re-login.
+func (c *Client) LoginWithProvider(ctx context.Context, provider CredentialProvider, remember bool) error {
+	username, password, err := provider.Credentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to acquire credentials")
 	}
-	u := c.WithPathAndQueryParams("/api/login", "remember", rememberStr)
 
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	err = c.login(ctx, username, password, remember)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.credentials = provider
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) login(ctx context.Context, username string, password string, remember bool) error {
+	flavor, err := c.ensureControllerFlavor(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to detect controller flavor")
+	}
+
+	// we do this one manually to acquire cookies
+	var u *url.URL
 	auth := map[string]interface{}{
 		"username": username,
 		"password": password,
 	}
+	var token string
+	c.mu.RLock()
+	totp := c.totp
+	c.mu.RUnlock()
+	if totp != nil {
+		token, err = totp.TOTP(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to acquire two-factor token")
+		}
+	}
+	if flavor == ControllerFlavorUniFiOS {
+		u = c.WithPathAndQueryParams(unifiOSLoginPath)
+		auth["remember"] = remember
+		if token != "" {
+			auth["token"] = token
+		}
+	} else {
+		if token != "" {
+			auth["ubic_2fa_token"] = token
+		}
+		rememberStr := "false"
+		if remember {
+			rememberStr = "true"
+		}
+		u = c.WithPathAndQueryParams("/api/login", "remember", rememberStr)
+	}
 	data, _ := json.Marshal(auth)
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
 	c.SetHeaders(req)
 
+	// logins count against the rate limit too, a burst of them is what locks out the account
+	err = c.rateLimiter.wait(ctx, "")
+	if err != nil {
+		return err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		return ErrInvalidResponseBody
 	}
 
-	var loginResponse LoginResponse
-	err = json.Unmarshal(body, &loginResponse)
-	if err != nil {
-		return ErrJSONDecode
-	}
+	if flavor == ControllerFlavorUniFiOS {
+		// UniFi OS replies with the user object instead of the usual meta envelope
+		if resp.StatusCode == unifiOSTwoFactorStatusCode {
+			return ErrTwoFactorRequired
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{StatusCode: resp.StatusCode, Method: http.MethodPost, Endpoint: u.Path}
+		}
+	} else {
+		var loginResponse LoginResponse
+		err = json.Unmarshal(body, &loginResponse)
+		if err != nil {
+			return ErrJSONDecode
+		}
 
-	if !loginResponse.Meta.ResponseCode.Equal(ResponseCodeOK) {
-		return fmt.Errorf("unable to login, response code: %s", loginResponse.Meta.ResponseCode)
+		if !loginResponse.Meta.ResponseCode.Equal(ResponseCodeOK) {
+			if loginResponse.Meta.ResponseCodeMessage == APITwoFactorRequiredError {
+				return ErrTwoFactorRequired
+			}
+			return &APIError{
+				StatusCode:   resp.StatusCode,
+				ResponseCode: loginResponse.Meta.ResponseCode,
+				Message:      loginResponse.Meta.ResponseCodeMessage,
+				Method:       http.MethodPost,
+				Endpoint:     u.Path,
+			}
+		}
 	}
-	c.authCookies = resp.Cookies()
+	c.storeSession(resp)
+	c.mu.Lock()
 	c.longRunningSession = remember
+	c.mu.Unlock()
+	atomic.AddUint64(&c.sessionGeneration, 1)
 	return nil
 }
 
-// Logout destroys the sever side session id which will make future attempts with that cookie fail
-func (c *Client) Logout() error {
-	if !c.longRunningSession {
-		// nothing to do, this will be invalid
+// reauthenticate logs in again with the stored credentials.
+// Concurrent callers that observed the same expired session share a single login,
+// the ones arriving after a newer session was established return immediately.
+func (c *Client) reauthenticate(ctx context.Context, staleGeneration uint64) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if atomic.LoadUint64(&c.sessionGeneration) != staleGeneration {
+		// someone else already logged in again
 		return nil
 	}
-	return c.doRequest(http.MethodGet, "/api/logout", nil, &LoginResponse{})
+	c.mu.RLock()
+	credentials := c.credentials
+	remember := c.longRunningSession
+	totp := c.totp
+	c.mu.RUnlock()
+	if credentials == nil {
+		return ErrNoCredentials
+	}
+	if _, static := totp.(StaticTOTP); static {
+		// the code entered for the first login has expired by now
+		return errors.Wrap(ErrTwoFactorRequired, "a static two-factor code can not be reused, use a TOTPGenerator to re-authenticate")
+	}
+	username, password, err := credentials.Credentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to acquire credentials")
+	}
+	return c.login(ctx, username, password, remember)
+}
+
+// Logout destroys the sever side session id which will make future attempts with that cookie fail
+// The stored credentials are forgotten so the session is not re-established automatically.
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	c.credentials = nil
+	remember := c.longRunningSession
+	c.mu.Unlock()
+
+	var err error
+	if c.ControllerFlavor() == ControllerFlavorUniFiOS {
+		err = c.logoutUniFiOS(ctx)
+	} else if remember {
+		err = c.doRequest(ctx, http.MethodGet, "/api/logout", nil, &LoginResponse{})
+	}
+	// otherwise nothing to do, the short-lived session will expire
+
+	c.clearSession()
+	return err
+}
+
+// clearSession drops the session cookies and CSRF token
+func (c *Client) clearSession() {
+	cookies, _ := cookiejar.New(nil)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cookies = cookies
+	c.csrfToken = ""
+	c.sessionExpires = time.Time{}
+}
+
+// logoutUniFiOS ends the UniFi OS session, which also requires the CSRF token.
+func (c *Client) logoutUniFiOS(ctx context.Context) error {
+	u := c.WithPathAndQueryParams(unifiOSLogoutPath)
+	req := &APIRequest{Method: http.MethodPost, Path: u.Path, Query: u.Query(), Header: make(http.Header)}
+	resp, err := c.execute(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Method: req.Method, Endpoint: req.Path}
+	}
+	return nil
 }
 
 // SelfResponseData is the self response data structure
@@ -96,8 +281,8 @@ type SelfResponse struct {
 }
 
 // Self returns the logged in user.
-func (c *Client) Self() (*SelfResponse, error) {
+func (c *Client) Self(ctx context.Context, opts ...RequestOption) (*SelfResponse, error) {
 	var selfResponse SelfResponse
-	err := c.doRequest(http.MethodGet, "/api/self", nil, &selfResponse)
+	err := c.doRequest(ctx, http.MethodGet, "/api/self", nil, &selfResponse, opts...)
 	return &selfResponse, err
 }
@@ -0,0 +1,123 @@
+package unifi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// requestsSince returns the requests received by the fake controller after the first n
+func requestsSince(srv *unifitest.Server, n int) []unifitest.RecordedRequest {
+	return srv.Requests()[n:]
+}
+
+func TestReauthenticateReplaysOnce(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.AddEvent("default", unifitest.Object{"key": "EVT_AP_Connected", "time": 1})
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := len(srv.Requests())
+	srv.ExpireSessions()
+
+	events, err := client.SiteEvents(ctx, "default", 24, 0, 10, unifi.EventSortOrderTimeDescending)
+	if err != nil {
+		t.Fatalf("request after the session expired failed: %v", err)
+	}
+	if len(events.Data) != 1 {
+		t.Fatalf("expected the replayed request to return the event, got %+v", events.Data)
+	}
+
+	reqs := requestsSince(srv, before)
+	var paths []string
+	for _, req := range reqs {
+		paths = append(paths, req.Method+" "+req.Path)
+	}
+	want := []string{
+		"GET /api/s/default/stat/event",
+		"POST /api/login",
+		"GET /api/s/default/stat/event",
+	}
+	if strings.Join(paths, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("unexpected requests\ngot  %v\nwant %v", paths, want)
+	}
+	if !bytes.Equal(reqs[0].Body, reqs[2].Body) {
+		t.Fatalf("the replayed body differs: %s != %s", reqs[2].Body, reqs[0].Body)
+	}
+
+	// the new session is used from now on
+	before = len(srv.Requests())
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := requestsSince(srv, before); len(reqs) != 1 {
+		t.Fatalf("expected a single request with the new session, got %+v", reqs)
+	}
+}
+
+func TestReauthenticateFailure(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := len(srv.Requests())
+	srv.ExpireSessions()
+	srv.InjectFault(unifitest.Fault{Path: "/api/login", StatusCode: http.StatusBadRequest, Message: "api.err.Invalid", Times: 1})
+
+	_, err = client.SiteHealth(ctx, "default")
+	if err == nil {
+		t.Fatal("expected the failed re-login to be surfaced")
+	}
+	if !strings.Contains(err.Error(), "re-authentication failed") || !errors.Is(err, unifi.ErrInvalid) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var apiErr *unifi.APIError
+	if !errors.As(err, &apiErr) || apiErr.Endpoint != "/api/login" {
+		t.Fatalf("expected the login APIError, got %v", err)
+	}
+	if reqs := requestsSince(srv, before); len(reqs) != 2 || reqs[1].Path != "/api/login" {
+		t.Fatalf("expected the request and a single login without a replay, got %+v", reqs)
+	}
+
+	// the next request logs in again once the controller accepts the credentials
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatalf("request after a successful re-login failed: %v", err)
+	}
+}
+
+func TestReauthenticateWithoutCredentials(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	before := len(srv.Requests())
+
+	_, err = client.SiteHealth(ctx, "default")
+	if !errors.Is(err, unifi.ErrLoginRequired) {
+		t.Fatalf("expected login required after logout, got %v", err)
+	}
+	if reqs := requestsSince(srv, before); len(reqs) != 1 {
+		t.Fatalf("expected no login attempt after logout, got %+v", reqs)
+	}
+}
@@ -0,0 +1,287 @@
+package unifi
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrCertificateMismatch indicates the controller presented a certificate that does not match the pinned or known fingerprint
+var ErrCertificateMismatch = fmt.Errorf("certificate fingerprint mismatch")
+
+// CertificateMismatchError is returned when the controller certificate does not match the pins or the known hosts file,
+// it matches ErrCertificateMismatch with errors.Is
+type CertificateMismatchError struct {
+	Host        string // host:port of the controller
+	Fingerprint string // SHA-256 fingerprint of the presented leaf certificate
+	Expected    string // the known fingerprint, empty when checking pins
+}
+
+// Error implements error
+func (e *CertificateMismatchError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("%v for %s: got %s, known %s", ErrCertificateMismatch, e.Host, e.Fingerprint, e.Expected)
+	}
+	return fmt.Sprintf("%v for %s: %s is not pinned", ErrCertificateMismatch, e.Host, e.Fingerprint)
+}
+
+// Is allows errors.Is(err, ErrCertificateMismatch)
+func (e *CertificateMismatchError) Is(target error) bool {
+	return target == ErrCertificateMismatch
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of the DER encoded certificate, as colon separated hex
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+// SPKIFingerprint returns the SHA-256 fingerprint of the public key of the certificate, as colon separated hex.
+// It survives certificate renewals that keep the key.
+func SPKIFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return formatFingerprint(sum[:])
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// normalizeFingerprint accepts hex fingerprints with or without separators, in any case
+func normalizeFingerprint(fingerprint string) (string, error) {
+	fingerprint = strings.TrimPrefix(strings.TrimSpace(fingerprint), "sha256:")
+	fingerprint = strings.NewReplacer(":", "", " ", "", "-", "").Replace(fingerprint)
+	sum, err := hex.DecodeString(fingerprint)
+	if err != nil || len(sum) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 fingerprint %q", fingerprint)
+	}
+	return formatFingerprint(sum), nil
+}
+
+// hostPort returns the host:port of the url, with the default port of the scheme if none is set
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" || u.Scheme == "ws" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// KnownHosts is a known hosts file of controller certificate fingerprints, one `host:port fingerprint` per line.
+// It is safe for concurrent use.
+type KnownHosts struct {
+	path string
+
+	mu    sync.Mutex
+	hosts map[string]string
+}
+
+// LoadKnownHosts reads the known hosts file, a missing file is treated as empty and created on the first Set
+func LoadKnownHosts(path string) (*KnownHosts, error) {
+	k := &KnownHosts{path: path, hosts: make(map[string]string)}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected `host:port fingerprint`", path, line)
+		}
+		fingerprint, err := normalizeFingerprint(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		k.hosts[fields[0]] = fingerprint
+	}
+	return k, scanner.Err()
+}
+
+// Lookup returns the known fingerprint of host:port
+func (k *KnownHosts) Lookup(host string) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	fingerprint, ok := k.hosts[host]
+	return fingerprint, ok
+}
+
+// Set stores the fingerprint of host:port, replacing a previous one, and rewrites the file
+func (k *KnownHosts) Set(host string, fingerprint string) error {
+	fingerprint, err := normalizeFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.hosts[host] = fingerprint
+	return k.write()
+}
+
+// write stores the hosts sorted, callers must hold mu
+func (k *KnownHosts) write() error {
+	hosts := make([]string, 0, len(k.hosts))
+	for host := range k.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var b strings.Builder
+	b.WriteString("# controller certificate SHA-256 fingerprints, managed by the unifi client\n")
+	for _, host := range hosts {
+		fmt.Fprintf(&b, "%s %s\n", host, k.hosts[host])
+	}
+
+	err := os.MkdirAll(filepath.Dir(k.path), 0700)
+	if err != nil {
+		return err
+	}
+	tmp := k.path + ".tmp"
+	err = ioutil.WriteFile(tmp, []byte(b.String()), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, k.path)
+}
+
+// fingerprintVerifier replaces the certificate chain verification with the pinned fingerprints,
+// or trust on first use against a known hosts file
+type fingerprintVerifier struct {
+	pins       map[string]bool
+	knownHosts *KnownHosts
+	client     *Client // the host is looked up on verification since SetBaseURL may change it
+}
+
+// newFingerprintVerifier returns nil if the config neither pins fingerprints nor sets a known hosts file
+func newFingerprintVerifier(certConfig *CertificationConfig) (*fingerprintVerifier, error) {
+	if len(certConfig.PinnedFingerprints) == 0 && certConfig.KnownHostsFile == "" {
+		return nil, nil
+	}
+	v := &fingerprintVerifier{pins: make(map[string]bool)}
+	for _, pin := range certConfig.PinnedFingerprints {
+		fingerprint, err := normalizeFingerprint(pin)
+		if err != nil {
+			return nil, err
+		}
+		v.pins[fingerprint] = true
+	}
+	if len(v.pins) == 0 {
+		knownHosts, err := LoadKnownHosts(certConfig.KnownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+		v.knownHosts = knownHosts
+	}
+	return v, nil
+}
+
+// verify implements tls.Config.VerifyPeerCertificate for the handshakes the transport performs itself,
+// which are only the tunnels to the controller through a proxy and the events websocket.
+// The direct connections are checked against the dialed address by the tlsDialer, and the https proxy handshake never is.
+func (v *fingerprintVerifier) verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	v.client.mu.RLock()
+	host := hostPort(v.client.baseURL)
+	v.client.mu.RUnlock()
+	return v.verifyHost(host, rawCerts)
+}
+
+// verifyHost checks the certificate presented by the controller at host:port
+func (v *fingerprintVerifier) verifyHost(host string, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("controller presented no certificate")
+	}
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+	fingerprint := CertificateFingerprint(leaf)
+
+	if len(v.pins) > 0 {
+		if v.pins[fingerprint] || v.pins[SPKIFingerprint(leaf)] {
+			return nil
+		}
+		return &CertificateMismatchError{Host: host, Fingerprint: fingerprint}
+	}
+
+	known, ok := v.knownHosts.Lookup(host)
+	if !ok {
+		// first use, trust and remember the certificate
+		return v.knownHosts.Set(host, fingerprint)
+	}
+	if known != fingerprint {
+		return &CertificateMismatchError{Host: host, Fingerprint: fingerprint, Expected: known}
+	}
+	return nil
+}
+
+// FetchCertificate connects to the controller without verifying its certificate and returns the leaf certificate,
+// use it to show the fingerprints before pinning or accepting them.
+// The connection goes through the transport options like WithProxy, WithSOCKS5Proxy and WithDialContext,
+// the certificate is read from the response to a request for the root page.
+// baseURL - base url of the controller
+// opts - the transport options the client uses to reach the controller
+func FetchCertificate(ctx context.Context, baseURL string, opts ...ClientOption) (*x509.Certificate, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	// only the certificate is inspected, nothing but the request for the root page is sent
+	c, err := NewClient(baseURL, &CertificationConfig{DisableCertCheck: true}, 0, opts...)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := *c.HTTPClient
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("controller presented no certificate")
+	}
+	return resp.TLS.PeerCertificates[0], nil
+}
+
+// KnownHostKey returns the key identifying the controller in a known hosts file
+// baseURL - base url of the controller
+func KnownHostKey(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	return hostPort(u), nil
+}
@@ -0,0 +1,324 @@
+package unifi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+// newCertificate returns a self-signed certificate for 127.0.0.1, like the one a controller ships with
+func newCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "unifi"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// newController starts a fake controller answering /status over TLS with its own self-signed certificate
+func newController(t *testing.T, cert tls.Certificate) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"rc":"ok","up":true,"server_version":"6.5.55"},"data":[]}`))
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	return srv
+}
+
+// connectProxy is an http CONNECT proxy counting the tunnels it opened
+type connectProxy struct {
+	tunnels int32
+}
+
+// ServeHTTP implements http.Handler
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	target, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		target.Close()
+		return
+	}
+	atomic.AddInt32(&p.tunnels, 1)
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	pipe := func(dst net.Conn, src net.Conn) {
+		defer wg.Done()
+		_, _ = io.Copy(dst, src)
+		dst.Close()
+	}
+	go pipe(target, conn)
+	go pipe(conn, target)
+	wg.Wait()
+}
+
+// newHTTPSProxy starts an https CONNECT proxy and returns the options to use it, trusting its test certificate
+func newHTTPSProxy(t *testing.T) (*httptest.Server, *connectProxy, []unifi.ClientOption) {
+	t.Helper()
+	proxy := &connectProxy{}
+	srv := httptest.NewTLSServer(proxy)
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	return srv, proxy, []unifi.ClientOption{
+		unifi.WithProxy(srv.URL),
+		unifi.WithProxyTLSConfig(&tls.Config{RootCAs: roots}),
+	}
+}
+
+// tempKnownHosts returns the path of a known hosts file in a temporary directory, and a func removing it
+func tempKnownHosts(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "unifi-known-hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "known_hosts"), func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPinnedFingerprints(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		pin     string
+		wantErr bool
+	}{
+		{name: "certificate", pin: unifi.CertificateFingerprint(cert.Leaf)},
+		{name: "public key", pin: unifi.SPKIFingerprint(cert.Leaf)},
+		{name: "lower case without colons", pin: strings.ToLower(strings.Replace(unifi.CertificateFingerprint(cert.Leaf), ":", "", -1))},
+		{name: "other certificate", pin: unifi.CertificateFingerprint(newCertificate(t).Leaf), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := unifi.NewClient(srv.URL, &unifi.CertificationConfig{PinnedFingerprints: []string{tt.pin}}, 5*time.Second,
+				unifi.WithRetryPolicy(unifi.NoRetryPolicy()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.ControllerStatus(context.Background())
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var mismatch *unifi.CertificateMismatchError
+			if !errors.Is(err, unifi.ErrCertificateMismatch) || !errors.As(err, &mismatch) {
+				t.Fatalf("expected a certificate mismatch, got %v", err)
+			}
+			if mismatch.Fingerprint != unifi.CertificateFingerprint(cert.Leaf) {
+				t.Fatalf("expected the presented fingerprint in the error, got %s", mismatch.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestKnownHostsTrustOnFirstUse(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+	file, cleanup := tempKnownHosts(t)
+	defer cleanup()
+
+	host, err := unifi.KnownHostKey(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func() error {
+		client, err := unifi.NewClient(srv.URL, &unifi.CertificationConfig{KnownHostsFile: file}, 5*time.Second,
+			unifi.WithRetryPolicy(unifi.NoRetryPolicy()))
+		if err != nil {
+			return err
+		}
+		_, err = client.ControllerStatus(context.Background())
+		return err
+	}
+
+	// the first run trusts and stores the certificate
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+	knownHosts, err := unifi.LoadKnownHosts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if known, ok := knownHosts.Lookup(host); !ok || known != unifi.CertificateFingerprint(cert.Leaf) {
+		t.Fatalf("expected the certificate to be stored for %s, got %q", host, known)
+	}
+
+	// a later run with the same certificate succeeds
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the controller certificate changed since it was stored
+	other := unifi.CertificateFingerprint(newCertificate(t).Leaf)
+	if err := knownHosts.Set(host, other); err != nil {
+		t.Fatal(err)
+	}
+	err = run()
+	var mismatch *unifi.CertificateMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Expected != other || mismatch.Host != host {
+		t.Fatalf("expected a mismatch with the known fingerprint, got %v", err)
+	}
+}
+
+func TestPinningThroughHTTPSProxy(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+	proxySrv, proxy, proxyOpts := newHTTPSProxy(t)
+	defer proxySrv.Close()
+	file, cleanup := tempKnownHosts(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		certConfig *unifi.CertificationConfig
+	}{
+		{name: "pinned", certConfig: &unifi.CertificationConfig{PinnedFingerprints: []string{unifi.CertificateFingerprint(cert.Leaf)}}},
+		{name: "trust on first use", certConfig: &unifi.CertificationConfig{KnownHostsFile: file}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the proxy presents a certificate that is neither pinned nor known, only the controller is checked
+			tunnels := atomic.LoadInt32(&proxy.tunnels)
+			client, err := unifi.NewClient(srv.URL, tt.certConfig, 5*time.Second,
+				append([]unifi.ClientOption{unifi.WithRetryPolicy(unifi.NoRetryPolicy())}, proxyOpts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.ControllerStatus(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if atomic.LoadInt32(&proxy.tunnels) == tunnels {
+				t.Fatal("the request did not go through the proxy")
+			}
+		})
+	}
+
+	knownHosts, err := unifi.LoadKnownHosts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyHost, _ := unifi.KnownHostKey(proxySrv.URL)
+	if _, ok := knownHosts.Lookup(proxyHost); ok {
+		t.Fatal("the proxy certificate was stored as a controller certificate")
+	}
+	host, _ := unifi.KnownHostKey(srv.URL)
+	if known, _ := knownHosts.Lookup(host); known != unifi.CertificateFingerprint(cert.Leaf) {
+		t.Fatalf("expected the controller certificate to be stored, got %q", known)
+	}
+}
+
+func TestFetchCertificate(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+	proxySrv, proxy, proxyOpts := newHTTPSProxy(t)
+	defer proxySrv.Close()
+
+	var dials int32
+	dialer := &net.Dialer{}
+	tests := []struct {
+		name  string
+		opts  []unifi.ClientOption
+		check func(t *testing.T)
+	}{
+		{name: "direct"},
+		{
+			name: "https proxy",
+			opts: proxyOpts,
+			check: func(t *testing.T) {
+				if atomic.LoadInt32(&proxy.tunnels) == 0 {
+					t.Fatal("the certificate was not fetched through the proxy")
+				}
+			},
+		},
+		{
+			name: "dialer",
+			opts: []unifi.ClientOption{unifi.WithDialContext(func(ctx context.Context, network string, addr string) (net.Conn, error) {
+				atomic.AddInt32(&dials, 1)
+				return dialer.DialContext(ctx, network, addr)
+			})},
+			check: func(t *testing.T) {
+				if atomic.LoadInt32(&dials) == 0 {
+					t.Fatal("the certificate was not fetched with the dialer")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			got, err := unifi.FetchCertificate(ctx, srv.URL, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if unifi.CertificateFingerprint(got) != unifi.CertificateFingerprint(cert.Leaf) {
+				t.Fatal("fetched the wrong certificate")
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+}
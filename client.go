@@ -1,6 +1,8 @@
 package unifi
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -8,9 +10,12 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"path"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,16 +23,41 @@ import (
 
 // Client is the object that handles talking to the Unifi Controller API. This maintains
 // state information for a particular application connection.
+// Every API call takes a context.Context as its first argument, cancelling it aborts the in-flight request.
+// A Client is safe for concurrent use by multiple goroutines.
 type Client struct {
-	baseURLStr string
-	baseURL    *url.URL
 	certConfig *CertificationConfig
+	verifier   *fingerprintVerifier
+	tlsDial    *tlsDialer
 
 	HTTPClient   *http.Client
 	RetryTimeout time.Duration
 
-	authCookies        []*http.Cookie
+	// mu guards the base url and the session state below
+	mu                 sync.RWMutex
+	baseURLStr         string
+	baseURL            *url.URL
+	cookies            http.CookieJar
+	csrfToken          string
+	sessionExpires     time.Time
 	longRunningSession bool
+	credentials        CredentialProvider
+	totp               TOTPProvider
+	retryPolicy        RetryPolicy
+	interceptors       []Interceptor
+	rateLimiter        *RateLimiter
+
+	loginMu           sync.Mutex
+	sessionGeneration uint64
+
+	// flavorMu guards the flavor, detecting it stores the UniFi OS session.
+	// The locks are always taken in the order loginMu, flavorMu, mu, never hold mu while taking flavorMu.
+	flavorMu sync.Mutex
+	flavor   ControllerFlavor
+
+	apiKey           string
+	integrationMu    sync.Mutex
+	integrationSites map[string]string
 }
 
 // CertificationConfig overrides the default HTTP client behavior with certificates.
@@ -35,26 +65,46 @@ type Client struct {
 // This setting allows you to override with your cert validation so that you are not running without certificate checks.
 // The options provided are mutually exclusive to each other.
 // 1. If DisableCertCheck is true, then all certificate checkin is disabled.
-// 2. If PEMCert is provided it will use that certificate.
-// 3. If Certificates are provided, then it will use those provided certificates.
-// 4. The default behavior if nothing is configured or a `nil` CertificationConfig is passed, then the default
+// 2. If PinnedFingerprints are provided, the leaf certificate or its public key must match one of them.
+// 3. If KnownHostsFile is provided, the first certificate seen is trusted and stored, later changes are rejected.
+// 4. If PEMCert is provided it will use that certificate.
+// 5. If Certificates are provided, then it will use those provided certificates.
+// 6. The default behavior if nothing is configured or a `nil` CertificationConfig is passed, then the default
 //    go http certificate checks are used.
 type CertificationConfig struct {
 	DisableCertCheck bool                // set to true to disable all certificate checks
 	PEMCert          string              // path to custom cert for self-signed certs
 	Certificates     []*x509.Certificate // custom certificates to add
+	// SHA-256 fingerprints of the leaf certificate or its public key (SPKI), hex with or without colons
+	PinnedFingerprints []string
+	KnownHostsFile     string // trust on first use, file of `host:port fingerprint` lines
 }
 
+// ClientOption configures optional client behavior in NewClient.
+type ClientOption func(c *Client) error
+
 // NewClient will create a new UniFi http(s) client.
-func NewClient(baseURL string, certConfig *CertificationConfig, timeout time.Duration) (*Client, error) {
-	httpClient := http.DefaultClient
+func NewClient(baseURL string, certConfig *CertificationConfig, timeout time.Duration, opts ...ClientOption) (*Client, error) {
+	httpClient := &http.Client{}
+	var verifier *fingerprintVerifier
 	if certConfig != nil {
 		defaultTransport := http.DefaultTransport.(*http.Transport)
 		var tlsConfig *tls.Config
+		var err error
+		verifier, err = newFingerprintVerifier(certConfig)
+		if err != nil {
+			return nil, err
+		}
 		if certConfig.DisableCertCheck {
 			tlsConfig = &tls.Config{
 				InsecureSkipVerify: true,
 			}
+		} else if verifier != nil {
+			// the chain of self-signed certificates can not be verified, the fingerprint is checked instead
+			tlsConfig = &tls.Config{
+				InsecureSkipVerify:    true,
+				VerifyPeerCertificate: verifier.verify,
+			}
 		} else if certConfig.PEMCert != "" {
 			cert, err := ioutil.ReadFile(certConfig.PEMCert)
 			if err != nil {
@@ -77,15 +127,8 @@ func NewClient(baseURL string, certConfig *CertificationConfig, timeout time.Dur
 			}
 		}
 
-		tr := &http.Transport{
-			Proxy:                 defaultTransport.Proxy,
-			DialContext:           defaultTransport.DialContext,
-			MaxIdleConns:          defaultTransport.MaxIdleConns,
-			IdleConnTimeout:       defaultTransport.IdleConnTimeout,
-			ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
-			TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
-			TLSClientConfig:       tlsConfig,
-		}
+		tr := defaultTransport.Clone()
+		tr.TLSClientConfig = tlsConfig
 		httpClient = &http.Client{Transport: tr}
 	}
 	httpClient.Timeout = timeout
@@ -95,13 +138,31 @@ func NewClient(baseURL string, certConfig *CertificationConfig, timeout time.Dur
 		return nil, err
 	}
 
-	return &Client{
+	cookies, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
 		baseURLStr:   baseURL,
 		baseURL:      u,
 		certConfig:   certConfig,
 		HTTPClient:   httpClient,
 		RetryTimeout: timeout,
-	}, nil
+		cookies:      cookies,
+		retryPolicy:  DefaultRetryPolicy(),
+	}
+	if verifier != nil {
+		verifier.client = c
+		c.verifier = verifier
+		c.tlsDialer(httpClient.Transport.(*http.Transport))
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
 // SetBaseURL changes the value of baseURL.
@@ -110,6 +171,8 @@ func (c *Client) SetBaseURL(baseURL string) error {
 	if err != nil {
 		return err
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.baseURLStr = baseURL
 	c.baseURL = u
 	return nil
@@ -117,6 +180,8 @@ func (c *Client) SetBaseURL(baseURL string) error {
 
 // GetBaseURL returns the baseURL.
 func (c *Client) GetBaseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.baseURLStr
 }
 
@@ -127,19 +192,30 @@ func (c *Client) SetHeaders(r *http.Request) {
 	r.Header.Set("Cache-Control", "no-cache")
 	r.Header.Set("Accept-Charset", "utf-8")
 	r.Header.Set("User-Agent", UserAgentHeader)
-	if c.authCookies != nil {
-		for _, cookie := range c.authCookies {
-			r.AddCookie(cookie)
-		}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, cookie := range c.cookies.Cookies(r.URL) {
+		r.AddCookie(cookie)
+	}
+	if c.csrfToken != "" {
+		r.Header.Set(csrfTokenHeader, c.csrfToken)
+	}
+	if c.apiKey != "" {
+		r.Header.Set(apiKeyHeader, c.apiKey)
 	}
 }
 
 // WithPathAndQueryParams will return a normalized url with the baseURL included.
 func (c *Client) WithPathAndQueryParams(extPath string, queryParamsPairs ...string) *url.URL {
-	newPath := path.Join(c.baseURL.Path, extPath)
+	c.mu.RLock()
+	baseURL := c.baseURL
+	c.mu.RUnlock()
+
+	newPath := path.Join(baseURL.Path, extPath)
 	u := &url.URL{
-		Scheme: c.baseURL.Scheme,
-		Host:   c.baseURL.Host,
+		Scheme: baseURL.Scheme,
+		Host:   baseURL.Host,
 		Path:   newPath,
 	}
 	q := u.Query()
@@ -162,58 +238,172 @@ type ResponseMessageTrait interface {
 	GetResponseMessage() string
 }
 
-func (c *Client) doRequest(method string, extPath string, sendBody io.Reader, ret interface{}, queryParamsPairs ...string) error {
-	u := c.WithPathAndQueryParams(extPath)
+func (c *Client) doRequest(ctx context.Context, method string, extPath string, sendBody io.Reader, ret interface{}, opts ...RequestOption) error {
+	return c.doAPIRequest(ctx, "", method, extPath, sendBody, ret, opts...)
+}
+
+func (c *Client) doSiteRequest(ctx context.Context, method string, site string, extPath string, sendBody io.Reader, ret interface{}, opts ...RequestOption) error {
+	return c.doAPIRequest(ctx, site, method, fmt.Sprintf("/api/s/%s/%s", site, extPath), sendBody, ret, opts...)
+}
+
+func (c *Client) doAPIRequest(ctx context.Context, site string, method string, extPath string, sendBody io.Reader, ret interface{}, opts ...RequestOption) error {
+	flavor, err := c.ensureControllerFlavor(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to detect controller flavor")
+	}
+	u := c.WithPathAndQueryParams(c.networkPath(flavor, extPath))
 
 	rv := reflect.ValueOf(ret)
-	if !rv.IsNil() && rv.Kind() != reflect.Ptr {
-		return fmt.Errorf("non nil-response handlers should be a pointer: kind:%v nil:%t", rv.Kind(), rv.IsNil())
+	if ret != nil && rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("non nil-response handlers should be a pointer: kind:%v", rv.Kind())
+	}
+
+	// buffer the body so the request can be replayed after re-authenticating
+	var sendData []byte
+	if sendBody != nil {
+		sendData, err = ioutil.ReadAll(sendBody)
+		if err != nil {
+			return err
+		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), sendBody)
+	req := &APIRequest{
+		Method: method,
+		Site:   site,
+		Path:   u.Path,
+		Query:  u.Query(),
+		Header: make(http.Header),
+		Body:   sendData,
+	}
+	err = newRequestOptions(opts).apply(req)
 	if err != nil {
 		return err
 	}
-	c.SetHeaders(req)
-
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.execute(ctx, req)
 	if err != nil {
 		return err
 	}
 
-	if !rv.IsNil() {
-		body, err := ioutil.ReadAll(resp.Body)
+	if ret != nil {
+		err = json.Unmarshal(resp.Body, ret)
 		if err != nil {
-			return errors.Wrap(err, ErrInvalidResponseBody.Error())
+			if resp.StatusCode >= http.StatusBadRequest {
+				// error pages are not always json, the status code is all there is
+				return &APIError{StatusCode: resp.StatusCode, Method: req.Method, Endpoint: req.Path}
+			}
+			return errors.Wrap(err, ErrJSONDecode.Error())
+		}
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || (resp.ResponseCode != "" && !resp.ResponseCode.Equal(ResponseCodeOK)) {
+		return &APIError{
+			StatusCode:   resp.StatusCode,
+			ResponseCode: resp.ResponseCode,
+			Message:      resp.Message,
+			Method:       req.Method,
+			Endpoint:     req.Path,
 		}
+	}
+	return nil
+}
+
+// send is the innermost Handler of the interceptor chain,
+// it performs the request including retries and re-authentication when the session expired.
+func (c *Client) send(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	start := time.Now()
+	release, err := c.rateLimiter.acquire(ctx, req.Site)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-		err = json.Unmarshal(body, ret)
+	generation := atomic.LoadUint64(&c.sessionGeneration)
+	statusCode, body, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if c.hasCredentials() && isLoginRequired(statusCode, body) {
+		err = c.reauthenticate(ctx, generation)
 		if err != nil {
-			return errors.Wrap(err, ErrJSONDecode.Error())
+			return nil, errors.Wrap(err, "session expired and re-authentication failed")
 		}
-		if rv.Kind() == reflect.Struct {
-			metaField := rv.FieldByName("Meta")
-
-			if metaField.IsValid() {
-				if retRespCodeTrait, ok := metaField.Interface().(ResponseCodeTrait); ok {
-					rc := retRespCodeTrait.GetResponseCode()
-					if !rc.Equal(ResponseCodeOK) {
-						if retRespCodeMsgTrait, ok := metaField.Interface().(ResponseMessageTrait); ok {
-							msg := retRespCodeMsgTrait.GetResponseMessage()
-							if msg != "" {
-								return fmt.Errorf(msg)
-							}
-						}
-						return fmt.Errorf("non-ok status code: %v - %v", rc, ret)
-					}
-				}
-			}
+		statusCode, body, err = c.roundTrip(ctx, req)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	resp := &APIResponse{
+		StatusCode: statusCode,
+		Body:       body,
+		Latency:    time.Since(start),
+	}
+	resp.ResponseCode, resp.Message = decodeMeta(body)
+	return resp, nil
+}
+
+// decodeMeta returns the response code and message of the common meta envelope, empty if there is none.
+func decodeMeta(body []byte) (ResponseCode, string) {
+	var probe struct {
+		Meta CommonMeta `json:"meta"`
+	}
+	if json.Unmarshal(body, &probe) != nil {
+		return "", ""
+	}
+	return probe.Meta.ResponseCode, probe.Meta.ResponseCodeMessage
+}
+
+// roundTripOnce performs a single http request and returns the status code and the fully read body.
+func (c *Client) roundTripOnce(ctx context.Context, apiReq *APIRequest) (int, []byte, error) {
+	var sendBody io.Reader
+	if apiReq.Body != nil {
+		sendBody = bytes.NewReader(apiReq.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, apiReq.Method, c.requestURL(apiReq).String(), sendBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	c.SetHeaders(req)
+	for k, v := range apiReq.Header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	c.storeSession(resp)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		// the transport aborts the body read when the context is done, surface that instead
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp.StatusCode, nil, ctxErr
+		}
+		return resp.StatusCode, nil, errors.Wrap(err, ErrInvalidResponseBody.Error())
+	}
+	return resp.StatusCode, body, nil
 }
 
-func (c *Client) doSiteRequest(method string, site string, extPath string, sendBody io.Reader, ret interface{}, queryParamsPairs ...string) error {
-	return c.doRequest(method, fmt.Sprintf("/api/s/%s/%s", site, extPath), sendBody, ret, queryParamsPairs...)
+// requestURL builds the url of the request from the base url, the path and the query
+func (c *Client) requestURL(apiReq *APIRequest) *url.URL {
+	c.mu.RLock()
+	baseURL := c.baseURL
+	c.mu.RUnlock()
+	return &url.URL{
+		Scheme:   baseURL.Scheme,
+		Host:     baseURL.Host,
+		Path:     apiReq.Path,
+		RawQuery: apiReq.Query.Encode(),
+	}
+}
+
+// isLoginRequired returns true if the response indicates the session is no longer valid.
+func isLoginRequired(statusCode int, body []byte) bool {
+	if statusCode == http.StatusUnauthorized {
+		return true
+	}
+	rc, msg := decodeMeta(body)
+	return !rc.Equal(ResponseCodeOK) && msg == APILoginRequiredError
 }
@@ -0,0 +1,89 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// TestClientConcurrentRelogin hammers the client from several goroutines while the sessions keep expiring,
+// run it with -race to check the session state is properly guarded.
+func TestClientConcurrentRelogin(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.AddEvent("default", unifitest.Object{"key": "EVT_AP_Connected", "time": 1})
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 8
+	const iterations = 25
+	done := make(chan struct{})
+	var expirer sync.WaitGroup
+	expirer.Add(1)
+	go func() {
+		defer expirer.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				srv.ExpireSessions()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*iterations*2)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				if _, err := client.SiteHealth(ctx, "default"); err != nil {
+					errs <- err
+				}
+				if _, err := client.SiteEvents(ctx, "default", 1, 0, 10, unifi.EventSortOrderTimeDescending); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	expirer.Wait()
+	close(errs)
+
+	for err := range errs {
+		// a request may race a second expiry right after its re-login, the replay is only attempted once
+		if !errors.Is(err, unifi.ErrLoginRequired) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	logins := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/api/login" {
+			logins++
+		}
+	}
+	if logins < 2 {
+		t.Fatalf("expected the client to re-login while the sessions expired, got %d logins", logins)
+	}
+
+	// once the sessions stop expiring every call must succeed again
+	srv.ExpireSessions()
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatalf("request after re-login failed: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// fingerprintCmd represents the fingerprint command
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint",
+	Short: "Show and accept the certificate fingerprint of the controller",
+	Long: `Show the SHA-256 fingerprints of the controller certificate and its public key,
+to pin them with --pin or to accept them into the known hosts file used with --tofu.
+
+Accepting replaces the known fingerprint, use it after verifying a certificate rotation.`,
+	Run: runFingerprint,
+}
+
+func init() {
+	rootCmd.AddCommand(fingerprintCmd)
+	fingerprintCmd.Flags().Bool("accept", false, "Store the fingerprint in the known hosts file, replacing a previous one")
+}
+
+// knownHostsFile returns the known hosts file, known_hosts in the state directory unless configured
+func knownHostsFile() (string, error) {
+	if file := viper.GetString("known_hosts"); file != "" {
+		return file, nil
+	}
+	stateDir, err := stateDirectory()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(stateDir, "known_hosts"), nil
+}
+
+func runFingerprint(cmd *cobra.Command, args []string) {
+	baseURL := viper.GetString("baseurl")
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+	defer cancel()
+	cert, err := unifi.FetchCertificate(ctx, baseURL, transportOptions()...)
+	if err != nil {
+		logger.Error("unable to fetch the controller certificate", zap.String("baseurl", baseURL), zap.Error(err))
+		os.Exit(1)
+	}
+	host, _ := unifi.KnownHostKey(baseURL)
+	fingerprint := unifi.CertificateFingerprint(cert)
+
+	fmt.Printf("controller:  %s\n", host)
+	fmt.Printf("subject:     %s\n", cert.Subject)
+	fmt.Printf("issuer:      %s\n", cert.Issuer)
+	fmt.Printf("valid:       %s - %s\n", cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+	fmt.Printf("certificate: %s\n", fingerprint)
+	fmt.Printf("public key:  %s\n", unifi.SPKIFingerprint(cert))
+
+	file, err := knownHostsFile()
+	if err != nil {
+		logger.Error("unable to locate known hosts file", zap.Error(err))
+		os.Exit(1)
+	}
+	knownHosts, err := unifi.LoadKnownHosts(file)
+	if err != nil {
+		logger.Error("unable to read known hosts file", zap.String("file", file), zap.Error(err))
+		os.Exit(1)
+	}
+	known, ok := knownHosts.Lookup(host)
+	switch {
+	case ok && known == fingerprint:
+		fmt.Println("known:       yes")
+		return
+	case ok:
+		fmt.Printf("known:       CHANGED, previously %s\n", known)
+	default:
+		fmt.Println("known:       no")
+	}
+
+	accept, _ := cmd.Flags().GetBool("accept")
+	if !accept {
+		return
+	}
+	err = knownHosts.Set(host, fingerprint)
+	if err != nil {
+		logger.Error("unable to update known hosts file", zap.String("file", file), zap.Error(err))
+		os.Exit(1)
+	}
+	fmt.Printf("accepted into %s\n", file)
+}
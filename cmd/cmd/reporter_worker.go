@@ -1,8 +1,8 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"math"
 	"sync"
 	"time"
 
@@ -18,43 +18,46 @@ type reporterWorker struct {
 
 func (w *reporterWorker) ReportAlarmStats() {
 	logger.Debug("collecting alarm stats", zap.String("site", w.site.Name))
+	ctx := context.Background()
 	// load last alarm state
 	alarmLastTimestamp := db.LastAlarmTimestamp(w.site.ID)
 
-	alarmsResp, err := client.SiteAlarmsCount(w.site.ID, 24, false)
+	alarmsResp, err := client.SiteAlarmsCount(ctx, w.site.ID, 24, false)
 	if err == nil {
 		w.reporters.ReportMetric(reporters.CountMetricType, "alarm.count", float64(alarmsResp.Meta.Count), "archived:false")
 	}
-	alarmsResp, err = client.SiteAlarmsCount(w.site.ID, 24, true)
+	alarmsResp, err = client.SiteAlarmsCount(ctx, w.site.ID, 24, true)
 	if err == nil {
 		w.reporters.ReportMetric(reporters.CountMetricType, "alarm.count", float64(alarmsResp.Meta.Count), "archived:true")
 	}
-	offset := 0
-	limit := 1000
-
-	now := time.Now().UTC()
 	// max 1 week (768 hours)
-	historyHours := math.Min(math.Ceil((-time.Duration(now.Unix() - alarmLastTimestamp) * time.Second).Hours()), 768)
-	newAlarmsData, err := client.SiteAlarms(w.site.ID, int(historyHours), offset, limit, unifi.EventSortOrderTimeDescending, false)
-	if err == nil {
-		newCount := 0
-		for _, alarm := range newAlarmsData.Data {
-			if alarm.Time > alarmLastTimestamp {
-				alarmLastTimestamp = alarm.Time
-			}
-			newCount++
-			w.reporters.ReportEvent(
-				fmt.Sprintf("Alert: Site=%s[%s] ID=%s Action=%s Category=%s", w.site.Name, w.site.ID, alarm.ID, alarm.InnerAlertAction, alarm.InnerAlertCategory),
-				fmt.Sprintf("%s\nTimestamp: %s", alarm.Message, alarm.DatetimeStr),  // TODO: add more info
-				fmt.Sprintf("severity:%d", alarm.InnerAlertSeverity),
-				fmt.Sprintf("signature_id:%d", alarm.InnerAlertSignatureID),
-				fmt.Sprintf("revision:%d", alarm.InnerAlertRevision),
-				fmt.Sprintf("gid:%d", alarm.InnerAlertGID),
-			)
+	since := time.Now().UTC().Add(-768 * time.Hour)
+	if last := time.Unix(0, alarmLastTimestamp*int64(time.Millisecond)); last.After(since) {
+		since = last
+	}
+	alarms := client.AlarmsIterator(ctx, w.site.ID, since, unifi.EventSortOrderTimeDescending, false)
+	newCount := 0
+	for alarms.Next() {
+		alarm := alarms.Alarm()
+		if alarm.Time > alarmLastTimestamp {
+			alarmLastTimestamp = alarm.Time
 		}
-		w.reporters.ReportMetric(reporters.CountMetricType,"alarm.new.count", float64(newCount))
-		db.PersistAlarmTimestamp(w.site.ID, alarmLastTimestamp)
+		newCount++
+		w.reporters.ReportEvent(
+			fmt.Sprintf("Alert: Site=%s[%s] ID=%s Action=%s Category=%s", w.site.Name, w.site.ID, alarm.ID, alarm.InnerAlertAction, alarm.InnerAlertCategory),
+			fmt.Sprintf("%s\nTimestamp: %s", alarm.Message, alarm.DatetimeStr),  // TODO: add more info
+			fmt.Sprintf("severity:%d", alarm.InnerAlertSeverity),
+			fmt.Sprintf("signature_id:%d", alarm.InnerAlertSignatureID),
+			fmt.Sprintf("revision:%d", alarm.InnerAlertRevision),
+			fmt.Sprintf("gid:%d", alarm.InnerAlertGID),
+		)
+	}
+	if err := alarms.Err(); err != nil {
+		logger.Warn("unable to list alarms", zap.String("site", w.site.Name), zap.Error(err))
+		return
 	}
+	w.reporters.ReportMetric(reporters.CountMetricType,"alarm.new.count", float64(newCount))
+	db.PersistAlarmTimestamp(w.site.ID, alarmLastTimestamp)
 }
 
 func (w *reporterWorker) ReportEventStats() {
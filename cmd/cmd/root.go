@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
@@ -57,20 +58,38 @@ func init() {
 	rootCmd.PersistentFlags().StringP("baseurl", "b", "", "BaseURL for the controller API. Ex. https://1.2.3.4:8443/")
 	rootCmd.PersistentFlags().StringP("username", "u", "", "API username for the controller")
 	rootCmd.PersistentFlags().StringP("password", "p", "", "API user password for the controller")
+	rootCmd.PersistentFlags().String("totp", "", "Two-factor code for accounts with 2FA enabled, only valid for the first login, set totp_seed for long-running commands")
+	rootCmd.PersistentFlags().String("apikey", "", "API key for the controller integration API, used instead of username/password")
+	rootCmd.PersistentFlags().Bool("sessionCache", true, "Reuse the session across runs, cached encrypted under the state directory")
 	// http settings
 	rootCmd.PersistentFlags().DurationP("timeout", "t", time.Second*30, "API timeout duration")
 	rootCmd.PersistentFlags().BoolP("disableTLS", "k", false, "Disable TLS checks on http client")
 	rootCmd.PersistentFlags().StringSlice("x509certs", []string{}, "Specify a list of x509 certificates for the http client trust")
 	rootCmd.PersistentFlags().String("pemCert", "", "Specify a PEM cert file for the http client trust")
+	rootCmd.PersistentFlags().String("proxy", "", "Proxy url for the controller, http://, https:// or socks5://")
+	rootCmd.PersistentFlags().String("clientCert", "", "PEM client certificate for controllers behind mutual TLS proxies")
+	rootCmd.PersistentFlags().String("clientKey", "", "PEM private key of the client certificate")
+	rootCmd.PersistentFlags().Bool("http2", true, "Negotiate HTTP/2 with the controller")
+	rootCmd.PersistentFlags().StringSlice("pin", []string{}, "Pin SHA-256 fingerprints of the controller certificate or its public key instead of verifying the chain")
+	rootCmd.PersistentFlags().Bool("tofu", false, "Trust the controller certificate on first use and reject changes, see the fingerprint command")
 
 	viper.BindPFlag("loglevel", rootCmd.PersistentFlags().Lookup("loglevel"))
 	viper.BindPFlag("baseurl", rootCmd.PersistentFlags().Lookup("baseurl"))
 	viper.BindPFlag("username", rootCmd.PersistentFlags().Lookup("username"))
 	viper.BindPFlag("password", rootCmd.PersistentFlags().Lookup("password"))
+	viper.BindPFlag("totp", rootCmd.PersistentFlags().Lookup("totp"))
+	viper.BindPFlag("apikey", rootCmd.PersistentFlags().Lookup("apikey"))
+	viper.BindPFlag("session_cache", rootCmd.PersistentFlags().Lookup("sessionCache"))
 	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
 	viper.BindPFlag("disableTLS", rootCmd.PersistentFlags().Lookup("disableTLS"))
 	viper.BindPFlag("x509certs", rootCmd.PersistentFlags().Lookup("x509certs"))
 	viper.BindPFlag("pemCert", rootCmd.PersistentFlags().Lookup("pemCert"))
+	viper.BindPFlag("proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	viper.BindPFlag("client_cert", rootCmd.PersistentFlags().Lookup("clientCert"))
+	viper.BindPFlag("client_key", rootCmd.PersistentFlags().Lookup("clientKey"))
+	viper.BindPFlag("http2", rootCmd.PersistentFlags().Lookup("http2"))
+	viper.BindPFlag("pin", rootCmd.PersistentFlags().Lookup("pin"))
+	viper.BindPFlag("tofu", rootCmd.PersistentFlags().Lookup("tofu"))
 	viper.SetDefault("loglevel", "warn")
 }
 
@@ -151,13 +170,46 @@ func initConfig() {
 	if viper.GetString("pemCert") != "" {
 		certConfig.PEMCert = viper.GetString("pemCert")
 	}
-	client, err = unifi.NewClient(baseURL.String(), &certConfig, viper.GetDuration("timeout"))
+	certConfig.PinnedFingerprints = viper.GetStringSlice("pin")
+	if viper.GetBool("tofu") {
+		certConfig.KnownHostsFile, err = knownHostsFile()
+		if err != nil {
+			logger.Error("unable to locate known hosts file", zap.Error(err))
+		}
+	}
+	clientOpts := append([]unifi.ClientOption{
+		unifi.WithInterceptors(unifi.LoggingInterceptor(logger.Sugar().Debugf)),
+	}, transportOptions()...)
+	apiKey := viper.GetString("apikey")
+	if apiKey != "" {
+		clientOpts = append(clientOpts, unifi.WithAPIKey(apiKey))
+	}
+	if code := viper.GetString("totp"); code != "" {
+		clientOpts = append(clientOpts, unifi.WithTOTPProvider(unifi.StaticTOTP(code)))
+	} else if seed := viper.GetString("totp_seed"); seed != "" {
+		generator, err := unifi.NewTOTPGenerator(seed)
+		if err != nil {
+			logger.Error("invalid totp_seed", zap.Error(err))
+		} else {
+			clientOpts = append(clientOpts, unifi.WithTOTPProvider(generator))
+		}
+	}
+	client, err = unifi.NewClient(baseURL.String(), &certConfig, viper.GetDuration("timeout"), clientOpts...)
 	if err != nil {
 		logger.Error("unable to initialize client", zap.Error(err))
 	} else {
 		logger.Debug("initialized client")
 	}
-	err = client.Login(viper.GetString("username"), viper.GetString("password"), false)
+	if apiKey != "" {
+		// api key authentication does not need a session
+		return
+	}
+	if viper.GetBool("session_cache") {
+		// long running session cached under the state directory, only logs in again when rejected
+		err = loginWithSessionCache(context.Background(), client, baseURL.String(), viper.GetString("username"), viper.GetString("password"))
+	} else {
+		err = client.Login(context.Background(), viper.GetString("username"), viper.GetString("password"), false)
+	}
 	if err != nil {
 		logger.Error("unable to authenticate against controller", zap.Error(err))
 	} else {
@@ -165,6 +217,21 @@ func initConfig() {
 	}
 }
 
+// transportOptions returns the client options configuring how to connect to the controller
+func transportOptions() []unifi.ClientOption {
+	var opts []unifi.ClientOption
+	if proxy := viper.GetString("proxy"); proxy != "" {
+		opts = append(opts, unifi.WithProxy(proxy))
+	}
+	if certFile := viper.GetString("client_cert"); certFile != "" {
+		opts = append(opts, unifi.WithClientCertificateFile(certFile, viper.GetString("client_key")))
+	}
+	if viper.IsSet("http2") {
+		opts = append(opts, unifi.WithHTTP2(viper.GetBool("http2")))
+	}
+	return opts
+}
+
 var defaultConfigData = []byte(`# Autogenerated Default configuration
 
 # loglevel options are: debug, info, warn, error, fatal, none
@@ -174,6 +241,16 @@ baseurl: FIXME
 timeout: 30s
 # username: or specify via the UNIFI_USERNAME env variable 
 # password: or specify via the UNIFI_PASSWORD env variable
+# totp_seed: base32 seed of the 2FA authenticator for accounts with two-factor authentication enabled
+# apikey: or specify via the UNIFI_APIKEY env variable, replaces username/password
+# proxy: "" # http://, https:// or socks5:// proxy in front of the controller, defaults to HTTPS_PROXY from the environment
+# client_cert: "" # PEM client certificate and key for controllers behind mutual TLS reverse proxies
+# client_key: ""
+# http2: true
+# pin: [] # SHA-256 fingerprints of the controller certificate or its public key, see the fingerprint command
+# tofu: false # trust the controller certificate on first use, stored in known_hosts under the state directory
+# known_hosts: "" # known hosts file used with tofu, by default is known_hosts in the state directory
+# session_cache: true # reuse the login session across runs, stored encrypted with the password in the state directory
 workers: 1
 # state_dir: "" # set the default state directory, by default is ~/.unifi.state/
 
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// session cache encryption parameters, the key is derived from the controller password
+const (
+	sessionCacheSaltSize   = 16
+	sessionCacheIterations = 100000
+)
+
+// stateDirectory returns the configured state directory, creating it when missing
+func stateDirectory() (string, error) {
+	stateDir := viper.GetString("state_dir")
+	if stateDir == "" {
+		stateDir = path.Join(homeDir, ".unifi.state")
+	}
+	err := os.MkdirAll(stateDir, 0700)
+	if err != nil {
+		return "", err
+	}
+	return stateDir, nil
+}
+
+// sessionCacheFile returns the cache file of the session of username on the controller
+func sessionCacheFile(baseURL string, username string) (string, error) {
+	stateDir, err := stateDirectory()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + username))
+	return path.Join(stateDir, "session-"+hex.EncodeToString(sum[:8])), nil
+}
+
+// loginWithSessionCache restores the cached session of the user, and only logs in when there is none
+// or the controller rejects it. The resulting session is cached again.
+func loginWithSessionCache(ctx context.Context, client *unifi.Client, baseURL string, username string, password string) error {
+	file, err := sessionCacheFile(baseURL, username)
+	if err != nil {
+		logger.Warn("session cache unavailable", zap.Error(err))
+		return client.Login(ctx, username, password, true)
+	}
+
+	restored := false
+	state, err := readSessionCache(file, password)
+	if err == nil {
+		err = client.ImportSession(state)
+	}
+	if err == nil {
+		// re-login transparently if the controller rejects the cached session
+		client.SetCredentialProvider(unifi.StaticCredentials{Username: username, Password: password}, true)
+		_, err = client.Self(ctx)
+		restored = err == nil
+	}
+	if !restored {
+		if !os.IsNotExist(err) {
+			logger.Debug("unable to reuse cached session", zap.String("file", file), zap.Error(err))
+		}
+		err = client.Login(ctx, username, password, true)
+		if err != nil {
+			return err
+		}
+	} else {
+		logger.Debug("reusing cached session", zap.String("file", file))
+	}
+
+	state, err = client.ExportSession()
+	if err == nil {
+		err = writeSessionCache(file, password, state)
+	}
+	if err != nil {
+		logger.Warn("unable to cache session", zap.String("file", file), zap.Error(err))
+	}
+	return nil
+}
+
+// readSessionCache decrypts the cached session
+func readSessionCache(file string, password string) (*unifi.SessionState, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < sessionCacheSaltSize {
+		return nil, fmt.Errorf("corrupt session cache")
+	}
+	aead, err := sessionCacheCipher(password, data[:sessionCacheSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[sessionCacheSaltSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("corrupt session cache")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		// most likely the password changed
+		return nil, fmt.Errorf("unable to decrypt session cache: %v", err)
+	}
+	var state unifi.SessionState
+	err = json.Unmarshal(plain, &state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// writeSessionCache encrypts the session into the cache file, readable by the current user only
+func writeSessionCache(file string, password string, state *unifi.SessionState) error {
+	plain, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, sessionCacheSaltSize)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	aead, err := sessionCacheCipher(password, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := append(salt, nonce...)
+	data = aead.Seal(data, nonce, plain, nil)
+
+	tmp := file + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// sessionCacheCipher returns the AES-256-GCM cipher keyed by the password with PBKDF2-HMAC-SHA256
+func sessionCacheCipher(password string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), salt, sessionCacheIterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+func TestSessionCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unifi-session-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	state := &unifi.SessionState{
+		BaseURL:   "https://controller.example.com:8443",
+		Flavor:    unifi.ControllerFlavorUniFiOS,
+		Cookies:   []unifi.SessionCookie{{Name: "TOKEN", Value: "secret-session"}},
+		CSRFToken: "csrf",
+		Expires:   time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+		Remember:  true,
+	}
+	file := path.Join(dir, "session")
+	if err := writeSessionCache(file, "password", state); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the cache to be readable by the user only, got %s", info.Mode())
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		data     func() []byte
+		wantErr  bool
+	}{
+		{name: "round trip", password: "password", data: func() []byte { return data }},
+		{name: "wrong password", password: "other", data: func() []byte { return data }, wantErr: true},
+		{
+			name:     "tampered ciphertext",
+			password: "password",
+			data: func() []byte {
+				tampered := append([]byte(nil), data...)
+				tampered[len(tampered)-1] ^= 0xff
+				return tampered
+			},
+			wantErr: true,
+		},
+		{
+			name:     "tampered salt",
+			password: "password",
+			data: func() []byte {
+				tampered := append([]byte(nil), data...)
+				tampered[0] ^= 0xff
+				return tampered
+			},
+			wantErr: true,
+		},
+		{name: "truncated", password: "password", data: func() []byte { return data[:sessionCacheSaltSize+4] }, wantErr: true},
+		{name: "empty", password: "password", data: func() []byte { return nil }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := path.Join(dir, "test-session")
+			if err := ioutil.WriteFile(file, tt.data(), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readSessionCache(file, tt.password)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected the cache to be rejected, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.BaseURL != state.BaseURL || got.Flavor != state.Flavor || got.CSRFToken != state.CSRFToken ||
+				!got.Expires.Equal(state.Expires) || !got.Remember || len(got.Cookies) != 1 || got.Cookies[0] != state.Cookies[0] {
+				t.Fatalf("expected %+v, got %+v", state, got)
+			}
+		})
+	}
+}
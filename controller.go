@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 )
@@ -32,9 +33,9 @@ type ControllerStatus struct {
 
 // ControllerStatus returns some very basic server information
 // This appears to be the only endpoint that can be reached without an authentication
-func (c *Client) ControllerStatus() (*ControllerStatus, error) {
+func (c *Client) ControllerStatus(ctx context.Context, opts ...RequestOption) (*ControllerStatus, error) {
 	var status ControllerStatus
-	err := c.doRequest(http.MethodGet, "/status", nil, &status)
+	err := c.doRequest(ctx, http.MethodGet, "/status", nil, &status, opts...)
 	return &status, err
 }
 
@@ -60,9 +61,12 @@ type SitesResponse struct {
 }
 
 // AvailableSites returns the available sites for the controller.
-func (c *Client) AvailableSites() (*SitesResponse, error) {
+func (c *Client) AvailableSites(ctx context.Context, opts ...RequestOption) (*SitesResponse, error) {
+	if c.usesAPIKey() {
+		return c.integrationAvailableSites(ctx)
+	}
 	var ret SitesResponse
-	err := c.doRequest(http.MethodGet, "/api/self/sites", nil, &ret)
+	err := c.doRequest(ctx, http.MethodGet, "/api/self/sites", nil, &ret, opts...)
 	return &ret, err
 }
 
@@ -183,9 +187,9 @@ type SitesVerboseResponse struct {
 }
 
 // AvailableSitesVerbose returns the available sites with verbose health data
-func (c *Client) AvailableSitesVerbose() (*SitesVerboseResponse, error) {
+func (c *Client) AvailableSitesVerbose(ctx context.Context, opts ...RequestOption) (*SitesVerboseResponse, error) {
 	var ret SitesVerboseResponse
-	err := c.doRequest(http.MethodGet, "/api/stat/sites", nil, &ret)
+	err := c.doRequest(ctx, http.MethodGet, "/api/stat/sites", nil, &ret, opts...)
 	return &ret, err
 }
 
@@ -196,8 +200,8 @@ type SiteAdminsResponse struct {
 }
 
 // SiteAdmins returns a list of administrators and permissions for all sites
-func (c *Client) SiteAdmins() (*SiteAdminsResponse, error) {
+func (c *Client) SiteAdmins(ctx context.Context, opts ...RequestOption) (*SiteAdminsResponse, error) {
 	var resp SiteAdminsResponse
-	err := c.doRequest(http.MethodGet, "/api/stat/admin", nil, &resp)
+	err := c.doRequest(ctx, http.MethodGet, "/api/stat/admin", nil, &resp, opts...)
 	return &resp, err
 }
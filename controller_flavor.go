@@ -0,0 +1,134 @@
+package unifi
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ControllerFlavor identifies the kind of controller the client is talking to
+type ControllerFlavor string
+
+// The supported controller flavors
+const (
+	// ControllerFlavorUnknown will auto-detect the flavor on the first request
+	ControllerFlavorUnknown ControllerFlavor = ""
+	// ControllerFlavorClassic is the standalone network controller software (/api/login, /api/s/{site}/...)
+	ControllerFlavorClassic ControllerFlavor = "classic"
+	// ControllerFlavorUniFiOS is a UniFi OS console like the UDM, UDM-Pro or Cloud Key Gen2+
+	// where the network application lives under /proxy/network.
+	ControllerFlavorUniFiOS ControllerFlavor = "unifi-os"
+)
+
+// IsValid returns true if it's a valid controller flavor.
+// there are only a few valid types
+func (f ControllerFlavor) IsValid() bool {
+	switch f {
+	case ControllerFlavorUnknown, ControllerFlavorClassic, ControllerFlavorUniFiOS:
+		return true
+	default:
+		return false
+	}
+}
+
+// UniFi OS specific paths and headers
+const (
+	unifiOSNetworkPrefix   = "/proxy/network"
+	unifiOSAuthPrefix      = "/api/auth/"
+	unifiOSLoginPath       = "/api/auth/login"
+	unifiOSLogoutPath      = "/api/auth/logout"
+	csrfTokenHeader        = "X-CSRF-Token"
+	updatedCSRFTokenHeader = "X-Updated-CSRF-Token"
+	// UniFi OS answers a login without the required MFA token with this non-standard status
+	unifiOSTwoFactorStatusCode = 499
+)
+
+// SetControllerFlavor forces the controller flavor instead of auto-detecting it.
+func (c *Client) SetControllerFlavor(flavor ControllerFlavor) {
+	c.flavorMu.Lock()
+	defer c.flavorMu.Unlock()
+	c.flavor = flavor
+}
+
+// ControllerFlavor returns the current controller flavor, this is ControllerFlavorUnknown until detected.
+func (c *Client) ControllerFlavor() ControllerFlavor {
+	c.flavorMu.Lock()
+	defer c.flavorMu.Unlock()
+	return c.flavor
+}
+
+// DetectControllerFlavor probes the controller to find out whether it runs on UniFi OS.
+// UniFi OS consoles answer the root path directly, while the classic controller redirects to /manage.
+func (c *Client) DetectControllerFlavor(ctx context.Context) (ControllerFlavor, error) {
+	c.flavorMu.Lock()
+	defer c.flavorMu.Unlock()
+	return c.detectFlavorLocked(ctx)
+}
+
+// ensureControllerFlavor detects the flavor once if it is still unknown.
+func (c *Client) ensureControllerFlavor(ctx context.Context) (ControllerFlavor, error) {
+	c.flavorMu.Lock()
+	defer c.flavorMu.Unlock()
+	if c.flavor != ControllerFlavorUnknown {
+		return c.flavor, nil
+	}
+	return c.detectFlavorLocked(ctx)
+}
+
+func (c *Client) detectFlavorLocked(ctx context.Context) (ControllerFlavor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.WithPathAndQueryParams("/").String(), nil)
+	if err != nil {
+		return ControllerFlavorUnknown, err
+	}
+
+	// do not follow the classic controller redirect, that is how we tell them apart
+	probeClient := *c.HTTPClient
+	probeClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return ControllerFlavorUnknown, err
+	}
+	resp.Body.Close()
+
+	c.flavor = ControllerFlavorClassic
+	if resp.StatusCode == http.StatusOK {
+		c.flavor = ControllerFlavorUniFiOS
+		c.storeSession(resp)
+	}
+	return c.flavor, nil
+}
+
+// networkPath rewrites a classic controller api path for the current controller flavor.
+func (c *Client) networkPath(flavor ControllerFlavor, extPath string) string {
+	if flavor != ControllerFlavorUniFiOS {
+		return extPath
+	}
+	p := path.Join("/", extPath)
+	if strings.HasPrefix(p, unifiOSAuthPrefix) || strings.HasPrefix(p, unifiOSNetworkPrefix+"/") {
+		return p
+	}
+	return unifiOSNetworkPrefix + p
+}
+
+// storeSession keeps the session cookies and the rotating UniFi OS CSRF token, which is echoed back on every request.
+func (c *Client) storeSession(resp *http.Response) {
+	token := resp.Header.Get(updatedCSRFTokenHeader)
+	if token == "" {
+		token = resp.Header.Get(csrfTokenHeader)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		c.cookies.SetCookies(resp.Request.URL, cookies)
+		if expires := cookiesExpiry(cookies); !expires.IsZero() {
+			c.sessionExpires = expires
+		}
+	}
+	if token != "" {
+		c.csrfToken = token
+	}
+}
@@ -0,0 +1,139 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+func TestControllerFlavors(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []unifitest.ServerOption
+		flavor     unifi.ControllerFlavor
+		loginPath  string
+		healthPath string
+		groupPath  string
+		csrf       bool
+	}{
+		{
+			name:       "classic",
+			flavor:     unifi.ControllerFlavorClassic,
+			loginPath:  "/api/login",
+			healthPath: "/api/s/default/stat/health",
+			groupPath:  "/api/s/default/rest/usergroup",
+		},
+		{
+			name:       "unifi os",
+			opts:       []unifitest.ServerOption{unifitest.WithUniFiOS()},
+			flavor:     unifi.ControllerFlavorUniFiOS,
+			loginPath:  "/api/auth/login",
+			healthPath: "/proxy/network/api/s/default/stat/health",
+			groupPath:  "/proxy/network/api/s/default/rest/usergroup",
+			csrf:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer(tt.opts...)
+			defer srv.Close()
+
+			ctx := context.Background()
+			client, err := srv.Client(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flavor := client.ControllerFlavor(); flavor != tt.flavor {
+				t.Fatalf("detected flavor %q, want %q", flavor, tt.flavor)
+			}
+			if _, err := client.SiteHealth(ctx, "default"); err != nil {
+				t.Fatal(err)
+			}
+
+			// every write must echo the CSRF token rotated by the previous response
+			for _, name := range []string{"slow", "fast"} {
+				if _, err := client.CreateUserGroup(ctx, "default", "", name, 1000, 1000); err != nil {
+					t.Fatalf("creating user group %s failed: %v", name, err)
+				}
+			}
+			// a new session comes with a new token
+			srv.ExpireSessions()
+			if _, err := client.CreateUserGroup(ctx, "default", "", "guest", 500, 500); err != nil {
+				t.Fatalf("creating a user group after the session expired failed: %v", err)
+			}
+			if groups := srv.Objects("default", unifitest.CollectionUserGroups); len(groups) != 3 {
+				t.Fatalf("expected 3 user groups, got %+v", groups)
+			}
+
+			var logins, health int
+			var tokens []string
+			for _, req := range srv.Requests() {
+				switch req.Path {
+				case tt.loginPath:
+					logins++
+				case tt.healthPath:
+					health++
+				case tt.groupPath:
+					if req.Method == http.MethodPost {
+						tokens = append(tokens, req.Header.Get("X-CSRF-Token"))
+					}
+				}
+			}
+			if logins != 2 || health != 1 {
+				t.Fatalf("expected 2 logins and 1 health request on the %s paths, got %d and %d in %+v", tt.name, logins, health, srv.Requests())
+			}
+			// the expired write is answered with 401 before the token is checked, so it is sent with the stale token
+			if len(tokens) != 4 {
+				t.Fatalf("expected 4 user group writes, got %d", len(tokens))
+			}
+			seen := make(map[string]bool)
+			for _, token := range []string{tokens[0], tokens[1], tokens[3]} {
+				if !tt.csrf && token != "" {
+					t.Fatalf("the classic controller does not hand out CSRF tokens, got %q", token)
+				}
+				if tt.csrf && (token == "" || seen[token]) {
+					t.Fatalf("expected a fresh CSRF token on every write, got %q", tokens)
+				}
+				seen[token] = true
+			}
+
+			if err := client.Logout(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.SiteHealth(ctx, "default"); !errors.Is(err, unifi.ErrLoginRequired) {
+				t.Fatalf("expected the session to end on logout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUniFiOSRejectsStaleCSRFToken(t *testing.T) {
+	srv := unifitest.NewServer(unifitest.WithUniFiOS())
+	defer srv.Close()
+
+	// replaying an already used token shows the fake enforces the rotation the client relies on
+	var stale string
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+		if stale != "" {
+			req.Header.Set("X-CSRF-Token", stale)
+		}
+		return next(ctx, req)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateUserGroup(ctx, "default", "", "slow", 1000, 1000); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	stale = reqs[len(reqs)-1].Header.Get("X-CSRF-Token")
+	_, err = client.CreateUserGroup(ctx, "default", "", "fast", 1000, 1000)
+	if !errors.Is(err, unifi.ErrNoPermission) {
+		t.Fatalf("expected the stale CSRF token to be rejected, got %v", err)
+	}
+}
@@ -14,13 +14,36 @@ const (
 
 // Common errors
 const (
-	APINoPermissionError = "api.err.NoPermission"
-	APIInvalidError      = "api.err.Invalid"
+	APINoPermissionError  = "api.err.NoPermission"
+	APIInvalidError       = "api.err.Invalid"
+	APILoginRequiredError = "api.err.LoginRequired"
+	// APITwoFactorRequiredError is returned on login when the account has 2FA enabled and no token was sent
+	APITwoFactorRequiredError = "api.err.Ubic2faTokenRequired"
+)
+
+// Sentinel errors matched by APIError, use them with errors.Is
+var (
+	// ErrNoPermission indicates the account is not allowed to perform the request
+	ErrNoPermission = fmt.Errorf("no permission")
+	// ErrInvalid indicates the controller rejected the request payload or parameters
+	ErrInvalid = fmt.Errorf("invalid request")
+	// ErrLoginRequired indicates the session is missing or expired
+	ErrLoginRequired = fmt.Errorf("login required")
+	// ErrNotFound indicates the endpoint or object does not exist
+	ErrNotFound = fmt.Errorf("not found")
+	// ErrControllerUnavailable indicates the controller, or a proxy in front of it, is not able to serve requests
+	ErrControllerUnavailable = fmt.Errorf("controller unavailable")
 )
 
 // ErrInvalidResponseBody indicates and error with the body of the response
 var ErrInvalidResponseBody = fmt.Errorf("invalid response body")
 
+// ErrNoCredentials indicates the session expired and there are no credentials to log in again with.
+var ErrNoCredentials = fmt.Errorf("no credentials available to re-authenticate")
+
+// ErrTwoFactorRequired indicates the account requires a two-factor token, configure one with SetTOTPProvider.
+var ErrTwoFactorRequired = fmt.Errorf("two-factor authentication token required")
+
 // ErrJSONDecode indicates an unexpected unmarshal problem from the API, check this is a valid endpoint.
 var ErrJSONDecode = fmt.Errorf("unable to unmarshal json response")
 
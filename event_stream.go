@@ -0,0 +1,268 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// StreamMessageType is the kind of message pushed by the controller on the events websocket
+type StreamMessageType string
+
+// The stream message types decoded into typed structs, other types are only available raw
+const (
+	StreamMessageEvents       StreamMessageType = "events"
+	StreamMessageAlarm        StreamMessageType = "alarm"
+	StreamMessageDeviceSync   StreamMessageType = "device:sync"
+	StreamMessageDeviceUpdate StreamMessageType = "device:update"
+	StreamMessageClientSync   StreamMessageType = "sta:sync"
+)
+
+// eventStreamBuffer is the number of stream events buffered for slow consumers
+const eventStreamBuffer = 64
+
+// The keepalive of the events websocket, a connection without any message or pong within streamPongWait is considered dead.
+// The controller answers the pings sent every streamPingInterval, which must be shorter than streamPongWait.
+var (
+	streamPongWait     = 60 * time.Second
+	streamPingInterval = 25 * time.Second
+)
+
+// StreamEvent is a single item pushed by the controller.
+// Only the field matching Type is set, Raw always holds the undecoded item.
+// Items reporting a dropped connection or a failed reconnect only have Err set.
+type StreamEvent struct {
+	Type   StreamMessageType
+	Event  *SiteEventsEvent
+	Alarm  *SiteAlarmsAlarm
+	Device *SiteDeviceDetailedData
+	Client *SiteActiveClient
+	Raw    json.RawMessage
+	Err    error
+}
+
+// streamKeepalive holds the keepalive durations of a subscription
+type streamKeepalive struct {
+	pongWait     time.Duration
+	pingInterval time.Duration
+}
+
+// streamMessage is the envelope of the websocket messages
+type streamMessage struct {
+	Meta struct {
+		ResponseCode ResponseCode      `json:"rc"`
+		Message      StreamMessageType `json:"message"`
+	} `json:"meta"`
+	Data []json.RawMessage `json:"data"`
+}
+
+// SubscribeEvents streams the events pushed by the controller for the site until ctx is done.
+// The connection is re-established with the backoff of the retry policy when it drops, re-authenticating if needed.
+// Dropped connections and failed reconnects are delivered as items with Err set, events pushed meanwhile are lost.
+// The returned channel is closed once ctx is done, or after an error a reconnect can not fix like a missing permission.
+// An error is only returned if the first connection fails.
+// site - the site to subscribe to
+func (c *Client) SubscribeEvents(ctx context.Context, site string) (<-chan StreamEvent, error) {
+	conn, err := c.dialEvents(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan StreamEvent, eventStreamBuffer)
+	keepalive := streamKeepalive{pongWait: streamPongWait, pingInterval: streamPingInterval}
+	go c.streamEvents(ctx, site, conn, keepalive, events)
+	return events, nil
+}
+
+// streamEvents reads the websocket, reconnecting until ctx is done or the reconnect fails permanently
+func (c *Client) streamEvents(ctx context.Context, site string, conn *websocket.Conn, keepalive streamKeepalive, events chan<- StreamEvent) {
+	defer close(events)
+
+	c.mu.RLock()
+	policy := c.retryPolicy
+	c.mu.RUnlock()
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = time.Second
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 30 * time.Second
+	}
+
+	for attempt := 0; ; {
+		if conn != nil {
+			attempt = 0
+			err := readEvents(ctx, conn, keepalive, events)
+			conn = nil
+			if ctx.Err() != nil || !sendStreamError(ctx, events, errors.Wrap(err, "event stream dropped")) {
+				return
+			}
+		}
+
+		attempt++
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		var err error
+		conn, err = c.dialEvents(ctx, site)
+		if err != nil {
+			if ctx.Err() != nil || !sendStreamError(ctx, events, errors.Wrap(err, "unable to reconnect event stream")) {
+				return
+			}
+			if isPermanentStreamError(err) {
+				return
+			}
+		}
+	}
+}
+
+// sendStreamError delivers err as a stream item, it returns false if ctx is done first
+func sendStreamError(ctx context.Context, events chan<- StreamEvent, err error) bool {
+	select {
+	case events <- StreamEvent{Err: err}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// isPermanentStreamError returns true for the answers a reconnect can not fix, like a missing permission or an unknown site.
+// Too many requests and the 5xx answers of a restarting controller are retried, like transport errors.
+func isPermanentStreamError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode >= http.StatusBadRequest && apiErr.StatusCode < http.StatusInternalServerError &&
+		apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// readEvents delivers the messages of conn until it fails or ctx is done, it always closes conn.
+// The connection is pinged to detect a controller that went away without closing it, ReadMessage then times out.
+func readEvents(ctx context.Context, conn *websocket.Conn, keepalive streamKeepalive, events chan<- StreamEvent) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// unblock ReadMessage when the context is done
+		defer conn.Close()
+		ticker := time.NewTicker(keepalive.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				// WriteControl is safe to call concurrently with ReadMessage
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(keepalive.pingInterval))
+				if err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	// every message or pong proves the connection is alive
+	_ = conn.SetReadDeadline(time.Now().Add(keepalive.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(keepalive.pongWait))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(keepalive.pongWait))
+		var msg streamMessage
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+		for _, raw := range msg.Data {
+			select {
+			case events <- decodeStreamEvent(msg.Meta.Message, raw):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// decodeStreamEvent decodes an item into the struct matching its message type
+func decodeStreamEvent(typ StreamMessageType, raw json.RawMessage) StreamEvent {
+	event := StreamEvent{Type: typ, Raw: raw}
+	var err error
+	switch typ {
+	case StreamMessageEvents:
+		event.Event = &SiteEventsEvent{}
+		err = json.Unmarshal(raw, event.Event)
+	case StreamMessageAlarm:
+		event.Alarm = &SiteAlarmsAlarm{}
+		err = json.Unmarshal(raw, event.Alarm)
+	case StreamMessageDeviceSync, StreamMessageDeviceUpdate:
+		event.Device = &SiteDeviceDetailedData{}
+		err = json.Unmarshal(raw, event.Device)
+	case StreamMessageClientSync:
+		event.Client = &SiteActiveClient{}
+		err = json.Unmarshal(raw, event.Client)
+	}
+	if err != nil {
+		// keep the raw item, the typed structs do not match every controller version
+		return StreamEvent{Type: typ, Raw: raw}
+	}
+	return event
+}
+
+// dialEvents opens the events websocket of the site, re-authenticating once if the session expired
+func (c *Client) dialEvents(ctx context.Context, site string) (*websocket.Conn, error) {
+	flavor, err := c.ensureControllerFlavor(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to detect controller flavor")
+	}
+	u := c.WithPathAndQueryParams(c.networkPath(flavor, fmt.Sprintf("/wss/s/%s/events", site)))
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: c.HTTPClient.Timeout,
+	}
+	if tr, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+		dialer.Proxy = tr.Proxy
+		dialer.NetDialContext = tr.DialContext
+		dialer.TLSClientConfig = tr.TLSClientConfig
+	}
+
+	for relogin := c.hasCredentials(); ; relogin = false {
+		generation := atomic.LoadUint64(&c.sessionGeneration)
+
+		// reuse the api headers, the cookies are looked up for the http url
+		header := &http.Request{URL: u, Header: make(http.Header)}
+		c.SetHeaders(header)
+		header.Header.Set("Origin", u.Scheme+"://"+u.Host)
+
+		wsURL := *u
+		wsURL.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+		conn, resp, err := dialer.DialContext(ctx, wsURL.String(), header.Header)
+		if err == nil {
+			return conn, nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized || !relogin {
+			if resp != nil {
+				return nil, &APIError{StatusCode: resp.StatusCode, Method: http.MethodGet, Endpoint: u.Path}
+			}
+			return nil, err
+		}
+		err = c.reauthenticate(ctx, generation)
+		if err != nil {
+			return nil, errors.Wrap(err, "session expired and re-authentication failed")
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// streamRetryPolicy reconnects the event streams quickly
+var streamRetryPolicy = unifi.RetryPolicy{MaxAttempts: 1, MinBackoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+// nextStreamEvent returns the next item of the stream, failing the test if none arrives in time
+func nextStreamEvent(t *testing.T, events <-chan unifi.StreamEvent) (unifi.StreamEvent, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		return event, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a stream event")
+		return unifi.StreamEvent{}, false
+	}
+}
+
+func TestSubscribeEventsReconnectErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fault     unifitest.Fault
+		wantErr   error
+		wantClose bool
+	}{
+		{
+			name:    "controller unavailable is retried",
+			fault:   unifitest.Fault{Path: "/wss/s/*/events", StatusCode: http.StatusServiceUnavailable, Times: 2},
+			wantErr: unifi.ErrControllerUnavailable,
+		},
+		{
+			name:      "missing permission closes the stream",
+			fault:     unifitest.Fault{Path: "/wss/s/*/events", StatusCode: http.StatusForbidden},
+			wantErr:   unifi.ErrNoPermission,
+			wantClose: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			client, err := srv.Client(ctx, unifi.WithRetryPolicy(streamRetryPolicy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := client.SubscribeEvents(ctx, "default")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			srv.InjectFault(tt.fault)
+			srv.DropStreams()
+
+			event, _ := nextStreamEvent(t, events)
+			if event.Err == nil {
+				t.Fatalf("expected the dropped connection to be reported, got %+v", event)
+			}
+			event, _ = nextStreamEvent(t, events)
+			if !errors.Is(event.Err, tt.wantErr) {
+				t.Fatalf("expected the failed reconnect to be reported as %v, got %v", tt.wantErr, event.Err)
+			}
+
+			if tt.wantClose {
+				if event, ok := nextStreamEvent(t, events); ok {
+					t.Fatalf("expected the stream to be closed, got %+v", event)
+				}
+				return
+			}
+			// the second failed reconnect, then the stream is back
+			if event, _ = nextStreamEvent(t, events); !errors.Is(event.Err, tt.wantErr) {
+				t.Fatalf("expected a second failed reconnect, got %+v", event)
+			}
+			for srv.Subscribers("default") == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			srv.Publish("default", "events", unifitest.Object{"key": "EVT_AP_Connected"})
+			if event, _ = nextStreamEvent(t, events); event.Err != nil || event.Event == nil || event.Event.Key != "EVT_AP_Connected" {
+				t.Fatalf("expected the event after reconnecting, got %+v", event)
+			}
+		})
+	}
+}
+
+func TestSubscribeEventsKeepalive(t *testing.T) {
+	defer unifi.SetStreamKeepalive(100*time.Millisecond, 20*time.Millisecond)()
+
+	t.Run("pongs keep an idle stream open", func(t *testing.T) {
+		srv := unifitest.NewServer()
+		defer srv.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client, err := srv.Client(ctx, unifi.WithRetryPolicy(streamRetryPolicy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		events, err := client.SubscribeEvents(ctx, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(300 * time.Millisecond)
+		srv.Publish("default", "events", unifitest.Object{"key": "EVT_AP_Connected"})
+		if event, _ := nextStreamEvent(t, events); event.Err != nil || event.Event == nil {
+			t.Fatalf("expected the event on the idle stream, got %+v", event)
+		}
+	})
+
+	t.Run("a silent controller times out", func(t *testing.T) {
+		upgrader := websocket.Upgrader{}
+		release := make(chan struct{})
+		defer close(release)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			// never read, so pings are not answered
+			<-release
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client, err := unifi.NewClient(srv.URL, nil, 5*time.Second, unifi.WithRetryPolicy(streamRetryPolicy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetControllerFlavor(unifi.ControllerFlavorClassic)
+		events, err := client.SubscribeEvents(ctx, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		event, _ := nextStreamEvent(t, events)
+		var netErr net.Error
+		if !errors.As(event.Err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("expected the stream to time out, got %+v", event)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/platinummonkey/unifi"
 )
@@ -18,8 +20,9 @@ func main() {
 	username := os.Getenv("UNIFI_USER")
 	pass := os.Getenv("UNIFI_PASSWORD")
 
-	c, _ := unifi.NewClient(baseURL, disableCertCheck)
-	err := c.Login(username, pass, false) // set to true for long running sessions, for this example it's short.
+	ctx := context.Background()
+	c, _ := unifi.NewClient(baseURL, &unifi.CertificationConfig{DisableCertCheck: disableCertCheck}, 30*time.Second)
+	err := c.Login(ctx, username, pass, false) // set to true for long running sessions, for this example it's short.
 	if err != nil {
 		log.Printf("login error: %v\n", err)
 	} else {
@@ -27,7 +30,7 @@ func main() {
 	}
 	hr()
 
-	status, err := c.ControllerStatus()
+	status, err := c.ControllerStatus(ctx)
 	if err != nil {
 		log.Printf("status error: %v", err)
 	} else {
@@ -35,7 +38,7 @@ func main() {
 	}
 	hr()
 
-	self, err := c.Self()
+	self, err := c.Self(ctx)
 	if err != nil {
 		log.Printf("self error: %v\n", err)
 	} else {
@@ -43,7 +46,7 @@ func main() {
 	}
 	hr()
 
-	siteAdmins, err := c.SiteAdmins()
+	siteAdmins, err := c.SiteAdmins(ctx)
 	if err != nil {
 		log.Printf("site-admins error: %v\n", err)
 	} else {
@@ -51,7 +54,7 @@ func main() {
 	}
 	hr()
 
-	sites, err := c.AvailableSites()
+	sites, err := c.AvailableSites(ctx)
 	if err != nil {
 		log.Printf("available sites error: %v\n", err)
 	} else {
@@ -59,7 +62,7 @@ func main() {
 	}
 	hr()
 
-	sitesVerbose, err := c.AvailableSitesVerbose()
+	sitesVerbose, err := c.AvailableSitesVerbose(ctx)
 	if err != nil {
 		log.Printf("available sites verbose error: %v\n", err)
 	} else {
@@ -69,7 +72,7 @@ func main() {
 	siteID := sites.Data[0].Name
 
 	// get the site health
-	siteHealth, err := c.SiteHealth(siteID)
+	siteHealth, err := c.SiteHealth(ctx, siteID)
 	if err != nil {
 		log.Printf("site-%s-health error: %v\n", siteID, err)
 	} else {
@@ -78,7 +81,7 @@ func main() {
 	hr()
 
 	// get events for first site
-	siteEvents, err := c.SiteEvents(siteID, 0, 0, 10, unifi.EventSortOrderTimeDescending)
+	siteEvents, err := c.SiteEvents(ctx, siteID, 0, 0, 10, unifi.EventSortOrderTimeDescending)
 	if err != nil {
 		log.Printf("site-%s-events error: %v\n", siteID, err)
 	} else {
@@ -87,7 +90,7 @@ func main() {
 	hr()
 
 	// get alarms
-	siteAlarms, err := c.SiteAlarms(siteID, 0, 0, 10, unifi.EventSortOrderTimeDescending)
+	siteAlarms, err := c.SiteAlarms(ctx, siteID, 0, 0, 10, unifi.EventSortOrderTimeDescending, false)
 	if err != nil {
 		log.Printf("site-%s-alarms error: %v\n", siteID, err)
 	} else {
@@ -96,7 +99,7 @@ func main() {
 	hr()
 
 	// active clients
-	activeClients, err := c.SiteActiveClients(siteID)
+	activeClients, err := c.SiteActiveClients(ctx, siteID, "")
 	if err != nil {
 		log.Printf("site-%s-active-clients error: %v\n", siteID, err)
 	} else {
@@ -105,7 +108,7 @@ func main() {
 	hr()
 
 	// logout
-	err = c.Logout()
+	err = c.Logout(ctx)
 	if err != nil {
 		log.Printf("logout error: %v\n", err)
 	} else {
@@ -0,0 +1,21 @@
+package unifi
+
+import "time"
+
+// SetIteratorPageSize changes the page size of the iterators and returns a func restoring it, tests use small pages
+func SetIteratorPageSize(size int) func() {
+	prev := iteratorPageSize
+	iteratorPageSize = size
+	return func() {
+		iteratorPageSize = prev
+	}
+}
+
+// SetStreamKeepalive changes the keepalive of the event streams subscribed afterwards and returns a func restoring it
+func SetStreamKeepalive(pongWait time.Duration, pingInterval time.Duration) func() {
+	prevPongWait, prevPingInterval := streamPongWait, streamPingInterval
+	streamPongWait, streamPingInterval = pongWait, pingInterval
+	return func() {
+		streamPongWait, streamPingInterval = prevPongWait, prevPingInterval
+	}
+}
@@ -0,0 +1,298 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFleetParallelism is the number of concurrent requests a Fleet makes when not configured
+const DefaultFleetParallelism = 4
+
+// FleetController configures a controller added to a Fleet with AddController
+type FleetController struct {
+	Name       string               // unique name of the controller in the fleet
+	BaseURL    string               // base url of the controller
+	CertConfig *CertificationConfig // tls configuration, nil for the system defaults
+	Timeout    time.Duration        // http timeout
+	Username   string               // login username, leave empty for api key authentication or to skip the login
+	Password   string
+	Remember   bool           // long running session
+	Options    []ClientOption // extra client options like WithAPIKey
+}
+
+// Fleet holds named clients for independent controllers and fans requests out over them.
+// A Fleet is safe for concurrent use.
+type Fleet struct {
+	parallelism int
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewFleet creates an empty fleet
+// parallelism - maximum number of concurrent requests across the fleet, DefaultFleetParallelism if zero
+func NewFleet(parallelism int) *Fleet {
+	if parallelism <= 0 {
+		parallelism = DefaultFleetParallelism
+	}
+	return &Fleet{
+		parallelism: parallelism,
+		clients:     make(map[string]*Client),
+	}
+}
+
+// Add adds a client under a unique name
+func (f *Fleet) Add(name string, client *Client) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkNameLocked(name); err != nil {
+		return err
+	}
+	f.clients[name] = client
+	return nil
+}
+
+// checkNameLocked returns an error if name is empty or already taken, the caller must hold the lock
+func (f *Fleet) checkNameLocked(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("controller name must not be empty")
+	}
+	if _, ok := f.clients[name]; ok {
+		return fmt.Errorf("controller %q is already part of the fleet", name)
+	}
+	return nil
+}
+
+// AddController creates a client for the controller, logs in when credentials are set and adds it to the fleet.
+// The name is checked before logging in, so an invalid or duplicate name never opens a session.
+func (f *Fleet) AddController(ctx context.Context, cfg FleetController) (*Client, error) {
+	f.mu.RLock()
+	err := f.checkNameLocked(cfg.Name)
+	f.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(cfg.BaseURL, cfg.CertConfig, cfg.Timeout, cfg.Options...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Username != "" {
+		err = client.Login(ctx, cfg.Username, cfg.Password, cfg.Remember)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to login to controller %q", cfg.Name)
+		}
+	}
+	err = f.Add(cfg.Name, client)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Remove removes a controller from the fleet
+func (f *Fleet) Remove(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.clients, name)
+}
+
+// Client returns the client of the named controller
+func (f *Fleet) Client(name string) (*Client, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	client, ok := f.clients[name]
+	return client, ok
+}
+
+// Names returns the sorted names of the controllers in the fleet
+func (f *Fleet) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.clients))
+	for name := range f.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FleetError aggregates the failures of a fleet wide operation.
+// Errors is keyed by controller name, or by controller/site for failures of a single site.
+type FleetError struct {
+	Errors map[string]error
+}
+
+// Error implements error
+func (e *FleetError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", key, e.Errors[key]))
+	}
+	return fmt.Sprintf("%d fleet operations failed: %s", len(keys), strings.Join(msgs, "; "))
+}
+
+// fleetErrors collects errors concurrently
+type fleetErrors struct {
+	mu     sync.Mutex
+	errors map[string]error
+}
+
+func (e *fleetErrors) add(key string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.errors == nil {
+		e.errors = make(map[string]error)
+	}
+	e.errors[key] = err
+}
+
+// err returns a *FleetError if any error was collected
+func (e *fleetErrors) err() error {
+	if len(e.errors) == 0 {
+		return nil
+	}
+	return &FleetError{Errors: e.errors}
+}
+
+// run calls fn for every job with bounded parallelism, it returns early when ctx is done
+func (f *Fleet) run(ctx context.Context, jobs int, fn func(i int)) {
+	sem := make(semaphore, f.parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		if sem.acquire(ctx) != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer sem.release()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// ForEachController calls fn for every controller of the fleet with bounded parallelism.
+// The failures are returned as a *FleetError keyed by controller name.
+func (f *Fleet) ForEachController(ctx context.Context, fn func(ctx context.Context, controller string, client *Client) error) error {
+	names := f.Names()
+	var errs fleetErrors
+	f.run(ctx, len(names), func(i int) {
+		client, ok := f.Client(names[i])
+		if !ok {
+			// removed in the meantime
+			return
+		}
+		if err := fn(ctx, names[i], client); err != nil {
+			errs.add(names[i], err)
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+// FleetSite identifies a site of a controller in the fleet
+type FleetSite struct {
+	Controller string
+	Site       SitesResponseData
+}
+
+// Sites lists the sites of every controller, the listing failures are returned as a *FleetError
+// next to the sites of the controllers that succeeded.
+func (f *Fleet) Sites(ctx context.Context) ([]FleetSite, error) {
+	var mu sync.Mutex
+	var sites []FleetSite
+	err := f.ForEachController(ctx, func(ctx context.Context, controller string, client *Client) error {
+		resp, err := client.AvailableSites(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, site := range resp.Data {
+			sites = append(sites, FleetSite{Controller: controller, Site: site})
+		}
+		return nil
+	})
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].Controller != sites[j].Controller {
+			return sites[i].Controller < sites[j].Controller
+		}
+		return sites[i].Site.Name < sites[j].Site.Name
+	})
+	return sites, err
+}
+
+// ForEachSite calls fn for every site of every controller with bounded parallelism.
+// The failures are returned as a *FleetError, keyed by controller when its sites could not be listed
+// and by controller/site when fn failed.
+func (f *Fleet) ForEachSite(ctx context.Context, fn func(ctx context.Context, client *Client, site FleetSite) error) error {
+	sites, err := f.Sites(ctx)
+	var errs fleetErrors
+	if fleetErr, ok := err.(*FleetError); ok {
+		for key, e := range fleetErr.Errors {
+			errs.add(key, e)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	f.run(ctx, len(sites), func(i int) {
+		site := sites[i]
+		client, ok := f.Client(site.Controller)
+		if !ok {
+			return
+		}
+		if err := fn(ctx, client, site); err != nil {
+			errs.add(site.Controller+"/"+site.Site.Name, err)
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+// FleetSiteHealth is the health of a site of the fleet
+type FleetSiteHealth struct {
+	FleetSite
+	Health []SiteHealthData
+}
+
+// CollectSiteHealth queries the health of every site of the fleet.
+// The results are sorted by controller and site, failures are returned as a *FleetError next to the partial results.
+func (f *Fleet) CollectSiteHealth(ctx context.Context) ([]FleetSiteHealth, error) {
+	var mu sync.Mutex
+	var results []FleetSiteHealth
+	err := f.ForEachSite(ctx, func(ctx context.Context, client *Client, site FleetSite) error {
+		resp, err := client.SiteHealth(ctx, site.Site.Name)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, FleetSiteHealth{FleetSite: site, Health: resp.Data})
+		return nil
+	})
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Controller != results[j].Controller {
+			return results[i].Controller < results[j].Controller
+		}
+		return results[i].Site.Name < results[j].Site.Name
+	})
+	return results, err
+}
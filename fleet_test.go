@@ -0,0 +1,193 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// fleetController returns the fleet configuration of a fake controller
+func fleetController(name string, srv *unifitest.Server) unifi.FleetController {
+	return unifi.FleetController{
+		Name:     name,
+		BaseURL:  srv.URL,
+		Timeout:  10 * time.Second,
+		Username: unifitest.DefaultUsername,
+		Password: unifitest.DefaultPassword,
+		Options:  []unifi.ClientOption{unifi.WithRetryPolicy(unifi.NoRetryPolicy())},
+	}
+}
+
+// countLogins returns the number of logins the fake controller received
+func countLogins(srv *unifitest.Server) int {
+	logins := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/api/login" {
+			logins++
+		}
+	}
+	return logins
+}
+
+func TestFleetAddControllerNames(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	fleet := unifi.NewFleet(0)
+	if _, err := fleet.AddController(ctx, fleetController("main", srv)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		controller string
+	}{
+		{name: "empty", controller: ""},
+		{name: "blank", controller: "  "},
+		{name: "duplicate", controller: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins := countLogins(srv)
+			if _, err := fleet.AddController(ctx, fleetController(tt.controller, srv)); err == nil {
+				t.Fatalf("expected controller name %q to be rejected", tt.controller)
+			}
+			if got := countLogins(srv); got != logins {
+				t.Fatalf("expected no login for a rejected name, got %d", got-logins)
+			}
+		})
+	}
+
+	if names := fleet.Names(); len(names) != 1 || names[0] != "main" {
+		t.Fatalf("unexpected fleet %v", names)
+	}
+}
+
+func TestFleetAddControllerLoginFailure(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	fleet := unifi.NewFleet(0)
+	cfg := fleetController("main", srv)
+	cfg.Password = "wrong"
+	if _, err := fleet.AddController(context.Background(), cfg); !errors.Is(err, unifi.ErrInvalid) {
+		t.Fatalf("expected the login to fail with ErrInvalid, got %v", err)
+	}
+	if _, ok := fleet.Client("main"); ok {
+		t.Fatal("a controller that failed to login was added")
+	}
+}
+
+func TestFleetCollectSiteHealth(t *testing.T) {
+	first := unifitest.NewServer()
+	defer first.Close()
+	second := unifitest.NewServer()
+	defer second.Close()
+	second.AddSite("branch", "Branch office")
+	second.AddSite("warehouse", "Warehouse")
+
+	ctx := context.Background()
+	fleet := unifi.NewFleet(2)
+	for name, srv := range map[string]*unifitest.Server{"first": first, "second": second} {
+		if _, err := fleet.AddController(ctx, fleetController(name, srv)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := fleet.CollectSiteHealth(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"first/default", "second/branch", "second/default", "second/warehouse"}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d sites, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if got := result.Controller + "/" + result.Site.Name; got != want[i] {
+			t.Fatalf("expected %s at %d, got %s", want[i], i, got)
+		}
+	}
+}
+
+func TestFleetPartialFailure(t *testing.T) {
+	healthy := unifitest.NewServer()
+	defer healthy.Close()
+	flakySite := unifitest.NewServer()
+	defer flakySite.Close()
+	flakySite.AddSite("branch", "Branch office")
+	down := unifitest.NewServer()
+	defer down.Close()
+
+	ctx := context.Background()
+	fleet := unifi.NewFleet(0)
+	for name, srv := range map[string]*unifitest.Server{"healthy": healthy, "flaky": flakySite, "down": down} {
+		if _, err := fleet.AddController(ctx, fleetController(name, srv)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	flakySite.InjectFault(unifitest.Fault{Path: "/api/s/branch/stat/health", StatusCode: http.StatusInternalServerError, Message: "api.err.ServerError"})
+	down.InjectFault(unifitest.Fault{Path: "/api/self/sites", StatusCode: http.StatusBadGateway})
+
+	results, err := fleet.CollectSiteHealth(ctx)
+	var fleetErr *unifi.FleetError
+	if !errors.As(err, &fleetErr) {
+		t.Fatalf("expected a *FleetError, got %v", err)
+	}
+	if len(fleetErr.Errors) != 2 || fleetErr.Errors["down"] == nil || fleetErr.Errors["flaky/branch"] == nil {
+		t.Fatalf("expected the down controller and the flaky site to fail, got %v", fleetErr.Errors)
+	}
+
+	// the sites that answered are still returned
+	want := []string{"flaky/default", "healthy/default"}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d partial results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if got := result.Controller + "/" + result.Site.Name; got != want[i] {
+			t.Fatalf("expected %s at %d, got %s", want[i], i, got)
+		}
+	}
+}
+
+func TestFleetParallelism(t *testing.T) {
+	fleet := unifi.NewFleet(2)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		client, err := unifi.NewClient("https://"+name+".example.com", nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fleet.Add(name, client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	running, maxRunning, calls := 0, 0, 0
+	err := fleet.ForEachController(context.Background(), func(ctx context.Context, controller string, client *unifi.Client) error {
+		mu.Lock()
+		running++
+		calls++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 5 || maxRunning > 2 {
+		t.Fatalf("expected 5 calls at most 2 at a time, got %d calls and %d at a time", calls, maxRunning)
+	}
+}
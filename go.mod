@@ -7,6 +7,7 @@ require (
 	github.com/aymerick/raymond v2.0.2+incompatible // indirect
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
 	github.com/gobuffalo/velvet v0.0.0-20170320144106-d97471bf5d8f
+	github.com/gorilla/websocket v1.4.2
 	github.com/markbates/inflect v1.0.4 // indirect
 	github.com/microcosm-cc/bluemonday v1.0.3 // indirect
 	github.com/mitchellh/go-homedir v1.1.0
@@ -25,4 +26,5 @@ require (
 	github.com/timshannon/badgerhold v0.0.0-20200316131017-7bcffb989f0d
 	github.com/zorkian/go-datadog-api v2.29.0+incompatible
 	go.uber.org/zap v1.15.0
+	golang.org/x/crypto v0.0.0-20190605123033-f99c8df09eb5
 )
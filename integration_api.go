@@ -0,0 +1,304 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The official UniFi Network integration API is authenticated with an api key instead of a cookie session.
+const (
+	apiKeyHeader          = "X-API-KEY"
+	integrationAPIPrefix  = "/proxy/network/integration/v1"
+	integrationPageLimit  = 200
+	integrationStateOK    = "ONLINE"
+	integrationWiredType  = "WIRED"
+	integrationAPFeature  = "accessPoint"
+	integrationSWFeature  = "switching"
+	integrationNotAdopted = "PENDING_ADOPTION"
+)
+
+// WithAPIKey authenticates every request with the given api key, created in the UniFi Network application
+// under the integrations settings. No Login is necessary when using an api key.
+// AvailableSites, SiteDevicesBasic and SiteActiveClients are served from the integration API in this mode.
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) error {
+		if strings.TrimSpace(apiKey) == "" {
+			return fmt.Errorf("api key must not be empty")
+		}
+		c.apiKey = strings.TrimSpace(apiKey)
+		// the integration API is only available on UniFi OS
+		c.flavor = ControllerFlavorUniFiOS
+		return nil
+	}
+}
+
+// usesAPIKey returns true when the client authenticates with an api key
+func (c *Client) usesAPIKey() bool {
+	return c.apiKey != ""
+}
+
+// integrationPage is the paginated envelope used by the integration API
+type integrationPage struct {
+	Offset     int             `json:"offset"`
+	Limit      int             `json:"limit"`
+	Count      int             `json:"count"`
+	TotalCount int             `json:"totalCount"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// integrationSite is a site as returned by the integration API
+type integrationSite struct {
+	ID                string `json:"id"`
+	InternalReference string `json:"internalReference"`
+	Name              string `json:"name"`
+}
+
+// integrationDevice is a device as returned by the integration API
+type integrationDevice struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Model      string   `json:"model"`
+	MACAddress string   `json:"macAddress"`
+	IPAddress  string   `json:"ipAddress"`
+	State      string   `json:"state"`
+	Features   []string `json:"features"`
+}
+
+// integrationClient is a connected client as returned by the integration API
+type integrationClient struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	Name           string `json:"name"`
+	ConnectedAt    string `json:"connectedAt"`
+	IPAddress      string `json:"ipAddress"`
+	MACAddress     string `json:"macAddress"`
+	UplinkDeviceID string `json:"uplinkDeviceId"`
+}
+
+// integrationList fetches every page of an integration API collection and appends the decoded items via appendPage.
+func (c *Client) integrationList(ctx context.Context, extPath string, appendPage func(data json.RawMessage) error) error {
+	offset := 0
+	for {
+		u := c.WithPathAndQueryParams(integrationAPIPrefix+extPath, "offset", strconv.Itoa(offset), "limit", strconv.Itoa(integrationPageLimit))
+		req := &APIRequest{Method: http.MethodGet, Path: u.Path, Query: u.Query(), Header: make(http.Header)}
+		resp, err := c.execute(ctx, req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{StatusCode: resp.StatusCode, Method: req.Method, Endpoint: req.Path}
+		}
+
+		var page integrationPage
+		err = json.Unmarshal(resp.Body, &page)
+		if err != nil {
+			return errors.Wrap(err, ErrJSONDecode.Error())
+		}
+		err = appendPage(page.Data)
+		if err != nil {
+			return errors.Wrap(err, ErrJSONDecode.Error())
+		}
+
+		offset += page.Count
+		if page.Count == 0 || offset >= page.TotalCount {
+			return nil
+		}
+	}
+}
+
+// integrationSiteList lists the sites through the integration API and refreshes the site name to id mapping.
+func (c *Client) integrationSiteList(ctx context.Context) ([]integrationSite, error) {
+	sites := make([]integrationSite, 0)
+	err := c.integrationList(ctx, "/sites", func(data json.RawMessage) error {
+		var page []integrationSite
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		sites = append(sites, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	siteIDs := make(map[string]string, len(sites))
+	for _, s := range sites {
+		siteIDs[s.InternalReference] = s.ID
+	}
+	c.integrationMu.Lock()
+	c.integrationSites = siteIDs
+	c.integrationMu.Unlock()
+	return sites, nil
+}
+
+// integrationSiteID resolves the classic site name (ex. `default`) into the integration API site id.
+func (c *Client) integrationSiteID(ctx context.Context, site string) (string, error) {
+	c.integrationMu.Lock()
+	id, ok := c.integrationSites[site]
+	c.integrationMu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	_, err := c.integrationSiteList(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.integrationMu.Lock()
+	id, ok = c.integrationSites[site]
+	c.integrationMu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown site: %s", site)
+	}
+	return id, nil
+}
+
+// integrationAvailableSites fills the classic self/sites response from the integration API
+func (c *Client) integrationAvailableSites(ctx context.Context) (*SitesResponse, error) {
+	sites, err := c.integrationSiteList(ctx)
+	if err != nil {
+		return &SitesResponse{}, err
+	}
+
+	resp := SitesResponse{
+		Meta: CommonMeta{ResponseCode: ResponseCodeOK, Count: len(sites)},
+		Data: make([]SitesResponseData, 0, len(sites)),
+	}
+	for _, s := range sites {
+		resp.Data = append(resp.Data, SitesResponseData{
+			ID:          s.ID,
+			Name:        s.InternalReference,
+			Description: s.Name,
+		})
+	}
+	return &resp, nil
+}
+
+// integrationDeviceType maps the integration API device to the classic device type.
+// Gateways have no feature of their own, they are recognized by their model like UDM Pro, UXG Lite or USG 3P.
+func integrationDeviceType(model string, features []string) string {
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(model))
+	for _, prefix := range []string{"UDM", "UDR", "UDW", "UCG"} {
+		if strings.HasPrefix(normalized, prefix) {
+			// consoles with a built-in gateway, also reported as udm by the classic api
+			return "udm"
+		}
+	}
+	for _, prefix := range []string{"UXG", "EFG"} {
+		if strings.HasPrefix(normalized, prefix) {
+			return "uxg"
+		}
+	}
+	if strings.HasPrefix(normalized, "USG") || strings.HasPrefix(normalized, "UGW") {
+		return "ugw"
+	}
+	for _, f := range features {
+		if f == integrationAPFeature {
+			return "uap"
+		}
+	}
+	for _, f := range features {
+		if f == integrationSWFeature {
+			return "usw"
+		}
+	}
+	return ""
+}
+
+// integrationDeviceState maps the integration API device state to the classic numeric state
+func integrationDeviceState(state string) int {
+	switch state {
+	case integrationStateOK:
+		return 1
+	case "UPDATING":
+		return 4
+	case "PROVISIONING", "GETTING_READY":
+		return 5
+	case "ADOPTING":
+		return 7
+	default:
+		return 0
+	}
+}
+
+// integrationSiteDevicesBasic fills the classic stat/device-basic response from the integration API
+func (c *Client) integrationSiteDevicesBasic(ctx context.Context, site string, typeFilter string) (*SiteDeviceBasicResponse, error) {
+	siteID, err := c.integrationSiteID(ctx, site)
+	if err != nil {
+		return &SiteDeviceBasicResponse{}, err
+	}
+
+	resp := SiteDeviceBasicResponse{
+		Meta: CommonMeta{ResponseCode: ResponseCodeOK},
+		Data: make([]SiteDeviceBasic, 0),
+	}
+	err = c.integrationList(ctx, fmt.Sprintf("/sites/%s/devices", siteID), func(data json.RawMessage) error {
+		var page []integrationDevice
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, d := range page {
+			device := SiteDeviceBasic{
+				Adopted: d.State != integrationNotAdopted,
+				MAC:     strings.ToLower(d.MACAddress),
+				State:   integrationDeviceState(d.State),
+				Type:    integrationDeviceType(d.Model, d.Features),
+			}
+			if typeFilter != "" && device.Type != typeFilter {
+				continue
+			}
+			resp.Data = append(resp.Data, device)
+		}
+		return nil
+	})
+	resp.Meta.Count = len(resp.Data)
+	return &resp, err
+}
+
+// integrationSiteActiveClients fills the classic stat/sta response from the integration API
+func (c *Client) integrationSiteActiveClients(ctx context.Context, site string, filterMac string) (*SiteActiveClientsResponse, error) {
+	siteID, err := c.integrationSiteID(ctx, site)
+	if err != nil {
+		return &SiteActiveClientsResponse{}, err
+	}
+
+	resp := SiteActiveClientsResponse{
+		Meta: CommonMeta{ResponseCode: ResponseCodeOK},
+		Data: make([]SiteActiveClient, 0),
+	}
+	err = c.integrationList(ctx, fmt.Sprintf("/sites/%s/clients", siteID), func(data json.RawMessage) error {
+		var page []integrationClient
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, cl := range page {
+			mac := strings.ToLower(cl.MACAddress)
+			if filterMac != "" && mac != strings.ToLower(filterMac) {
+				continue
+			}
+			activeClient := SiteActiveClient{
+				ID:       cl.ID,
+				HostName: cl.Name,
+				IP:       cl.IPAddress,
+				IsWired:  cl.Type == integrationWiredType,
+				MAC:      mac,
+				SiteID:   siteID,
+			}
+			if connectedAt, err := time.Parse(time.RFC3339, cl.ConnectedAt); err == nil {
+				activeClient.AssociationTime = connectedAt.Unix()
+				activeClient.LatestAssociationTime = connectedAt.Unix()
+			}
+			resp.Data = append(resp.Data, activeClient)
+		}
+		return nil
+	})
+	resp.Meta.Count = len(resp.Data)
+	return &resp, err
+}
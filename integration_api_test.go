@@ -0,0 +1,176 @@
+package unifi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+const testAPIKey = "integration-key"
+
+// apiKeyClient returns a client of the fake controller authenticated with the api key only
+func apiKeyClient(t *testing.T, srv *unifitest.Server) *unifi.Client {
+	t.Helper()
+	client, err := unifi.NewClient(srv.URL, nil, 10*time.Second, unifi.WithAPIKey(testAPIKey), unifi.WithRetryPolicy(unifi.NoRetryPolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestAPIKeyRequests(t *testing.T) {
+	srv := unifitest.NewServer(unifitest.WithAPIKey(testAPIKey))
+	defer srv.Close()
+	client := apiKeyClient(t, srv)
+
+	ctx := context.Background()
+	sites, err := client.AvailableSites(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sites.Data) != 1 || sites.Data[0].Name != "default" || sites.Data[0].Description != "Default" {
+		t.Fatalf("unexpected sites %+v", sites.Data)
+	}
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateUserGroup(ctx, "default", "", "slow", 1000, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, req := range srv.Requests() {
+		paths = append(paths, req.Method+" "+req.Path)
+		if got := req.Header.Get("X-API-KEY"); got != testAPIKey {
+			t.Errorf("%s %s sent api key %q", req.Method, req.Path, got)
+		}
+		if req.Header.Get("Cookie") != "" {
+			t.Errorf("%s %s sent a session cookie", req.Method, req.Path)
+		}
+	}
+	want := []string{
+		"GET /proxy/network/integration/v1/sites",
+		"GET /proxy/network/api/s/default/stat/health",
+		"POST /proxy/network/api/s/default/rest/usergroup",
+	}
+	if strings.Join(paths, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("unexpected requests, no login nor flavor detection is expected\ngot  %v\nwant %v", paths, want)
+	}
+}
+
+func TestAPIKeyRejected(t *testing.T) {
+	srv := unifitest.NewServer(unifitest.WithAPIKey("another-key"))
+	defer srv.Close()
+	client := apiKeyClient(t, srv)
+
+	_, err := client.AvailableSites(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "http 401") {
+		t.Fatalf("expected the api key to be rejected, got %v", err)
+	}
+}
+
+func TestAPIKeyDeviceTypes(t *testing.T) {
+	srv := unifitest.NewServer(unifitest.WithAPIKey(testAPIKey))
+	defer srv.Close()
+	devices := []unifitest.Object{
+		{"mac": "F0:9F:C2:00:00:01", "type": "uap", "model": "U6 Lite", "state": 1},
+		{"mac": "f0:9f:c2:00:00:02", "type": "usw", "model": "USW 24 PoE", "state": 1},
+		{"mac": "f0:9f:c2:00:00:03", "type": "ugw", "model": "USG 3P", "state": 1},
+		{"mac": "f0:9f:c2:00:00:04", "type": "udm", "model": "UDM Pro", "state": 1},
+		{"mac": "f0:9f:c2:00:00:05", "type": "uxg", "model": "UXG-Lite", "state": 0},
+		{"mac": "f0:9f:c2:00:00:06", "type": "uap", "model": "U6 Pro", "adopted": false},
+	}
+	for _, device := range devices {
+		srv.AddDevice("default", device)
+	}
+	client := apiKeyClient(t, srv)
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{filter: "", want: []string{
+			"f0:9f:c2:00:00:01 uap adopted state 1",
+			"f0:9f:c2:00:00:02 usw adopted state 1",
+			"f0:9f:c2:00:00:03 ugw adopted state 1",
+			"f0:9f:c2:00:00:04 udm adopted state 1",
+			"f0:9f:c2:00:00:05 uxg adopted state 0",
+			"f0:9f:c2:00:00:06 uap pending state 0",
+		}},
+		{filter: "uap", want: []string{"f0:9f:c2:00:00:01 uap adopted state 1", "f0:9f:c2:00:00:06 uap pending state 0"}},
+		{filter: "usw", want: []string{"f0:9f:c2:00:00:02 usw adopted state 1"}},
+		{filter: "ugw", want: []string{"f0:9f:c2:00:00:03 ugw adopted state 1"}},
+		{filter: "udm", want: []string{"f0:9f:c2:00:00:04 udm adopted state 1"}},
+		{filter: "uxg", want: []string{"f0:9f:c2:00:00:05 uxg adopted state 0"}},
+	}
+	for _, tt := range tests {
+		t.Run("filter "+tt.filter, func(t *testing.T) {
+			resp, err := client.SiteDevicesBasic(context.Background(), "default", tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range resp.Data {
+				adopted := "pending"
+				if d.Adopted {
+					adopted = "adopted"
+				}
+				got = append(got, fmt.Sprintf("%s %s %s state %d", d.MAC, d.Type, adopted, d.State))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") || resp.Meta.Count != len(tt.want) {
+				t.Fatalf("unexpected devices (count %d)\ngot  %v\nwant %v", resp.Meta.Count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyActiveClientsPaging(t *testing.T) {
+	srv := unifitest.NewServer(unifitest.WithAPIKey(testAPIKey))
+	defer srv.Close()
+	const clients = 205
+	for i := 0; i < clients; i++ {
+		srv.AddClient("default", unifitest.Object{
+			"mac": fmt.Sprintf("00:11:22:33:%02X:%02X", i/256, i%256), "hostname": fmt.Sprintf("host-%d", i),
+			"ip": "192.168.1.10", "is_wired": i%2 == 0, "assoc_time": 1600000000 + i,
+		})
+	}
+	client := apiKeyClient(t, srv)
+
+	ctx := context.Background()
+	resp, err := client.SiteActiveClients(ctx, "default", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != clients {
+		t.Fatalf("expected %d clients over two pages, got %d", clients, len(resp.Data))
+	}
+	first := resp.Data[0]
+	if first.MAC != "00:11:22:33:00:00" || first.HostName != "host-0" || !first.IsWired || first.AssociationTime != 1600000000 {
+		t.Fatalf("unexpected client %+v", first)
+	}
+	var pages []string
+	for _, req := range srv.Requests() {
+		if req.Method == http.MethodGet && strings.HasSuffix(req.Path, "/clients") {
+			pages = append(pages, req.Query)
+		}
+	}
+	sort.Strings(pages)
+	if strings.Join(pages, " ") != "limit=200&offset=0 limit=200&offset=200" {
+		t.Fatalf("unexpected pages %v", pages)
+	}
+
+	resp, err = client.SiteActiveClients(ctx, "default", "00:11:22:33:00:0A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].HostName != "host-10" || !resp.Data[0].IsWired {
+		t.Fatalf("unexpected filtered clients %+v", resp.Data)
+	}
+}
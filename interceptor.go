@@ -0,0 +1,97 @@
+package unifi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// APIRequest is a controller api request as seen by the interceptors.
+// Interceptors may modify it before calling the next handler, the url is built from Path and Query when it is sent.
+type APIRequest struct {
+	Method string
+	Site   string      // the site the request is scoped to, empty for controller level endpoints
+	Path   string      // endpoint path including the base url path, like /api/s/default/stat/health
+	Query  url.Values  // query parameters
+	Header http.Header // headers applied on top of the default client headers
+	Body   []byte      // request payload, nil if there is none
+}
+
+// APIResponse is the controller response as seen by the interceptors.
+type APIResponse struct {
+	StatusCode   int
+	ResponseCode ResponseCode  // decoded meta.rc, empty if the response had no meta
+	Message      string        // decoded meta.msg
+	Body         []byte        // raw response body
+	Latency      time.Duration // time taken including retries and re-authentication
+}
+
+// Handler executes an api request
+type Handler func(ctx context.Context, req *APIRequest) (*APIResponse, error)
+
+// Interceptor wraps the execution of every api request.
+// It must call next to continue the chain, or may return its own response or error to short-circuit it.
+// Logins bypass the chain, including the re-login of an expired session which happens within next,
+// so are the controller flavor probe and the event stream handshake.
+type Interceptor func(ctx context.Context, req *APIRequest, next Handler) (*APIResponse, error)
+
+// WithInterceptors adds interceptors to the client, the first one is the outermost.
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) error {
+		c.interceptors = append(c.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// Use appends interceptors to the chain, the first one added is the outermost.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	chain := make([]Interceptor, 0, len(c.interceptors)+len(interceptors))
+	chain = append(chain, c.interceptors...)
+	c.interceptors = append(chain, interceptors...)
+}
+
+// execute runs the request through the interceptor chain
+func (c *Client) execute(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	c.mu.RLock()
+	interceptors := c.interceptors
+	c.mu.RUnlock()
+
+	handler := Handler(c.send)
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return handler(ctx, req)
+}
+
+// LoggingInterceptor logs every request with its outcome using the printf style logf, like log.Printf.
+func LoggingInterceptor(logf func(format string, v ...interface{})) Interceptor {
+	return func(ctx context.Context, req *APIRequest, next Handler) (*APIResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		latency := time.Since(start)
+		if err != nil {
+			logf("unifi: %s %s site=%q latency=%s error=%v", req.Method, req.Path, req.Site, latency, err)
+			return resp, err
+		}
+		logf("unifi: %s %s site=%q status=%d rc=%q msg=%q latency=%s",
+			req.Method, req.Path, req.Site, resp.StatusCode, resp.ResponseCode, resp.Message, latency)
+		return resp, err
+	}
+}
+
+// TimingInterceptor reports the latency of every request to observe, for example to record request metrics.
+// resp is nil when err is set.
+func TimingInterceptor(observe func(req *APIRequest, resp *APIResponse, latency time.Duration, err error)) Interceptor {
+	return func(ctx context.Context, req *APIRequest, next Handler) (*APIResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		observe(req, resp, time.Since(start), err)
+		return resp, err
+	}
+}
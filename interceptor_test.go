@@ -0,0 +1,205 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// recordingInterceptor appends name before and after calling next
+func recordingInterceptor(name string, calls *[]string) unifi.Interceptor {
+	return func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+		*calls = append(*calls, name+" "+req.Path)
+		resp, err := next(ctx, req)
+		*calls = append(*calls, name+" done")
+		return resp, err
+	}
+}
+
+func TestInterceptorChainOrder(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	var calls []string
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(recordingInterceptor("a", &calls), recordingInterceptor("b", &calls)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Use(recordingInterceptor("c", &calls))
+
+	// the expired session is re-established within the chain, the login itself bypasses it
+	srv.ExpireSessions()
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"a /api/s/default/stat/health",
+		"b /api/s/default/stat/health",
+		"c /api/s/default/stat/health",
+		"c done",
+		"b done",
+		"a done",
+	}
+	if strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("unexpected chain order\ngot  %v\nwant %v", calls, want)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	cached := []byte(`{"meta":{"rc":"ok"},"data":[{"subsystem":"wlan","status":"ok"}]}`)
+	client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+		switch {
+		case strings.HasSuffix(req.Path, "/stat/health"):
+			return &unifi.APIResponse{StatusCode: http.StatusOK, ResponseCode: unifi.ResponseCodeOK, Body: cached}, nil
+		case strings.HasSuffix(req.Path, "/stat/sysinfo"):
+			return nil, errors.New("offline")
+		}
+		return next(ctx, req)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := len(srv.Requests())
+
+	health, err := client.SiteHealth(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(health.Data) != 1 || health.Data[0].SubSystem != "wlan" {
+		t.Fatalf("expected the cached response, got %+v", health.Data)
+	}
+	if _, err := client.SiteSysInfo(ctx, "default"); err == nil || err.Error() != "offline" {
+		t.Fatalf("expected the interceptor error, got %v", err)
+	}
+	if reqs := srv.Requests()[before:]; len(reqs) != 0 {
+		t.Fatalf("short-circuited requests reached the controller: %+v", reqs)
+	}
+}
+
+func TestInterceptorRewritesRequest(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.AddSite("branch", "Branch")
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+		req.Path = strings.Replace(req.Path, "/api/s/default/", "/api/s/branch/", 1)
+		req.Query.Set("within", "2")
+		req.Header.Set("X-Trace", "abc")
+		return next(ctx, req)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := len(srv.Requests())
+	if _, err := client.SiteHealth(ctx, "default", unifi.Type("wan")); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := srv.Requests()[before:]
+	if len(reqs) != 1 {
+		t.Fatalf("expected a single request, got %+v", reqs)
+	}
+	if reqs[0].Path != "/api/s/branch/stat/health" || reqs[0].Query != "type=wan&within=2" || reqs[0].Header.Get("X-Trace") != "abc" {
+		t.Fatalf("the rewritten request was not sent: %s?%s %v", reqs[0].Path, reqs[0].Query, reqs[0].Header)
+	}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	var lines []string
+	logf := func(format string, v ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, v...))
+	}
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(unifi.LoggingInterceptor(logf)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.InjectFault(unifitest.Fault{Path: "/api/s/default/stat/sysinfo", Message: "api.err.NoPermission", StatusCode: http.StatusForbidden})
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SiteSysInfo(ctx, "default"); !errors.Is(err, unifi.ErrNoPermission) {
+		t.Fatalf("expected no permission, got %v", err)
+	}
+	srv.Close()
+	if _, err := client.SiteHealth(ctx, "default"); err == nil {
+		t.Fatal("expected the closed controller to fail the request")
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %q", lines)
+	}
+	prefixes := []string{
+		`unifi: GET /api/s/default/stat/health site="default" status=200 rc="ok" msg="" latency=`,
+		`unifi: GET /api/s/default/stat/sysinfo site="default" status=403 rc="error" msg="api.err.NoPermission" latency=`,
+		`unifi: GET /api/s/default/stat/health site="default" latency=`,
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("log line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+	if !strings.Contains(lines[2], " error=") {
+		t.Errorf("expected the transport error to be logged, got %q", lines[2])
+	}
+}
+
+func TestTimingInterceptor(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	type observation struct {
+		path    string
+		status  int
+		latency time.Duration
+		err     error
+	}
+	var observed []observation
+	observe := func(req *unifi.APIRequest, resp *unifi.APIResponse, latency time.Duration, err error) {
+		o := observation{path: req.Path, latency: latency, err: err}
+		if resp != nil {
+			o.status = resp.StatusCode
+		}
+		observed = append(observed, o)
+	}
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(unifi.TimingInterceptor(observe)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.InjectFault(unifitest.Fault{Path: "/api/s/default/stat/health", Latency: 20 * time.Millisecond, Times: 1})
+	if _, err := client.SiteHealth(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.SiteHealth(cancelled, "default"); err == nil {
+		t.Fatal("expected the cancelled request to fail")
+	}
+
+	if len(observed) != 2 {
+		t.Fatalf("expected 2 observations, got %+v", observed)
+	}
+	if o := observed[0]; o.path != "/api/s/default/stat/health" || o.status != http.StatusOK || o.latency < 20*time.Millisecond || o.err != nil {
+		t.Fatalf("unexpected observation of the delayed request %+v", o)
+	}
+	if o := observed[1]; o.status != 0 || !errors.Is(o.err, context.Canceled) {
+		t.Fatalf("expected the cancelled request to be observed without a response, got %+v", o)
+	}
+}
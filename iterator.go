@@ -0,0 +1,293 @@
+package unifi
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// iteratorPageSize is the number of items fetched per page, the controller caps pages at 3000
+var iteratorPageSize = 1000
+
+// pager holds the paging loop shared by the iterators, which only buffer the typed page.
+// Items are deduplicated by _id since new items shift the offsets between pages,
+// and iteration stops at the first item older than the since watermark when sorted newest first.
+type pager struct {
+	ctx        context.Context
+	since      time.Time
+	descending bool
+	load       func(offset int, limit int) (int, error) // loads the page into the iterator buffer and returns its size
+	item       func(i int) (string, time.Time)          // returns the id and time of buffered item i
+	offset     int
+	size       int // number of buffered items
+	pos        int // next buffered item
+	last       bool
+	seen       map[string]bool
+	err        error
+}
+
+func newPager(ctx context.Context, since time.Time, descending bool, load func(offset int, limit int) (int, error), item func(i int) (string, time.Time)) pager {
+	return pager{
+		ctx:        ctx,
+		since:      since,
+		descending: descending,
+		load:       load,
+		item:       item,
+		seen:       make(map[string]bool),
+	}
+}
+
+// next advances to the next admitted item and returns its index in the buffer, false when done or on error
+func (p *pager) next() (int, bool) {
+	for {
+		for p.pos < p.size {
+			i := p.pos
+			p.pos++
+			yield, stop := p.admit(p.item(i))
+			if stop {
+				p.last, p.size = true, 0
+				return 0, false
+			}
+			if yield {
+				return i, true
+			}
+		}
+		if !p.fetch() {
+			return 0, false
+		}
+	}
+}
+
+// admit returns whether the item should be yielded, and false for stop once the watermark is reached
+func (p *pager) admit(id string, t time.Time) (yield bool, stop bool) {
+	if !p.since.IsZero() && !t.After(p.since) {
+		// sorted newest first everything that follows is older as well
+		return false, p.descending
+	}
+	if id != "" {
+		if p.seen[id] {
+			return false, false
+		}
+		p.seen[id] = true
+	}
+	return true, false
+}
+
+// fetch loads the next page, it returns false when there are no more pages
+func (p *pager) fetch() bool {
+	if p.last || p.err != nil {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	n, err := p.load(p.offset, iteratorPageSize)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.offset += n
+	p.size, p.pos = n, 0
+	p.last = n < iteratorPageSize
+	return n > 0
+}
+
+// historyHours returns the history to query to reach back to since, at least an hour
+func historyHours(since time.Time, def int) int {
+	if since.IsZero() {
+		return def
+	}
+	hours := int(math.Ceil(time.Since(since).Hours()))
+	if hours < 1 {
+		hours = 1
+	}
+	return hours
+}
+
+// millisToTime converts the millisecond timestamps used by the controller
+func millisToTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// EventsIterator pages through site events or IPS events.
+//
+//	it := client.EventsIterator(ctx, "default", since, unifi.EventSortOrderTimeDescending)
+//	for it.Next() {
+//		event := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EventsIterator struct {
+	pager
+	buf []SiteEventsEvent
+	cur SiteEventsEvent
+}
+
+// newEventsIterator returns an events iterator over the pages returned by load
+func newEventsIterator(ctx context.Context, since time.Time, order EventSortOrder, load func(offset int, limit int) ([]SiteEventsEvent, error)) *EventsIterator {
+	it := &EventsIterator{}
+	it.pager = newPager(ctx, since, order == EventSortOrderTimeDescending,
+		func(offset int, limit int) (int, error) {
+			page, err := load(offset, limit)
+			it.buf = page
+			return len(page), err
+		},
+		func(i int) (string, time.Time) {
+			return it.buf[i].ID, millisToTime(it.buf[i].Time)
+		})
+	return it
+}
+
+// EventsIterator returns an iterator over the site events newer than since, all events of the last 30 days if since is zero.
+// site - site to query
+// since - only return events after this time
+// order - how to order the events
+func (c *Client) EventsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder) *EventsIterator {
+	hours := historyHours(since, 720)
+	return newEventsIterator(ctx, since, order, func(offset int, limit int) ([]SiteEventsEvent, error) {
+		resp, err := c.SiteEvents(ctx, site, hours, offset, limit, order)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IPSEventsIterator returns an iterator over the IPS/IDS events newer than since, the events of the last 24 hours if since is zero.
+// site - site to query
+// since - only return events after this time
+// order - how to order the events
+func (c *Client) IPSEventsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder) *EventsIterator {
+	end := time.Now().UTC()
+	start := since
+	if start.IsZero() {
+		start = end.Add(-24 * time.Hour)
+	}
+	return newEventsIterator(ctx, since, order, func(offset int, limit int) ([]SiteEventsEvent, error) {
+		resp, err := c.SiteIPSEvents(ctx, site, start, end, offset, limit, order)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// Next advances to the next event, it returns false when done or on error
+func (it *EventsIterator) Next() bool {
+	i, ok := it.next()
+	if ok {
+		it.cur = it.buf[i]
+	}
+	return ok
+}
+
+// Event returns the current event
+func (it *EventsIterator) Event() SiteEventsEvent {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *EventsIterator) Err() error {
+	return it.err
+}
+
+// AlarmsIterator pages through site alarms
+type AlarmsIterator struct {
+	pager
+	buf []SiteAlarmsAlarm
+	cur SiteAlarmsAlarm
+}
+
+// AlarmsIterator returns an iterator over the site alarms newer than since, the alarms of the last 24 hours if since is zero.
+// site - site to query
+// since - only return alarms after this time
+// order - how to order the alarms
+// archived - iterate archived (when true) or unarchived alarms
+func (c *Client) AlarmsIterator(ctx context.Context, site string, since time.Time, order EventSortOrder, archived bool) *AlarmsIterator {
+	hours := historyHours(since, 24)
+	it := &AlarmsIterator{}
+	it.pager = newPager(ctx, since, order == EventSortOrderTimeDescending,
+		func(offset int, limit int) (int, error) {
+			resp, err := c.SiteAlarms(ctx, site, hours, offset, limit, order, archived)
+			if err != nil {
+				return 0, err
+			}
+			it.buf = resp.Data
+			return len(it.buf), nil
+		},
+		func(i int) (string, time.Time) {
+			return it.buf[i].ID, millisToTime(it.buf[i].Time)
+		})
+	return it
+}
+
+// Next advances to the next alarm, it returns false when done or on error
+func (it *AlarmsIterator) Next() bool {
+	i, ok := it.next()
+	if ok {
+		it.cur = it.buf[i]
+	}
+	return ok
+}
+
+// Alarm returns the current alarm
+func (it *AlarmsIterator) Alarm() SiteAlarmsAlarm {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AlarmsIterator) Err() error {
+	return it.err
+}
+
+// SessionsIterator pages through the login sessions of a client
+type SessionsIterator struct {
+	pager
+	buf []map[string]interface{}
+	cur map[string]interface{}
+}
+
+// SessionsIterator returns an iterator over the login sessions of a client associated after since, all sessions if since is zero.
+// site - site to query
+// mac - the client device mac
+// since - only return sessions associated after this time
+// order - how to order the sessions
+func (c *Client) SessionsIterator(ctx context.Context, site string, mac string, since time.Time, order SiteSessionOrder) *SessionsIterator {
+	it := &SessionsIterator{}
+	it.pager = newPager(ctx, since, order == SiteSessionSortOrderTimeDescending,
+		func(offset int, limit int) (int, error) {
+			resp, err := c.ListLatestSessions(ctx, site, mac, order, offset, limit)
+			if err != nil {
+				return 0, err
+			}
+			it.buf = resp.Data
+			return len(it.buf), nil
+		},
+		func(i int) (string, time.Time) {
+			id, _ := it.buf[i]["_id"].(string)
+			assocTime, _ := it.buf[i]["assoc_time"].(float64)
+			return id, time.Unix(int64(assocTime), 0)
+		})
+	return it
+}
+
+// Next advances to the next session, it returns false when done or on error
+func (it *SessionsIterator) Next() bool {
+	i, ok := it.next()
+	if ok {
+		it.cur = it.buf[i]
+	}
+	return ok
+}
+
+// Session returns the current session
+func (it *SessionsIterator) Session() map[string]interface{} {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SessionsIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,137 @@
+package unifi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// eventsRequests counts the stat/event pages requested
+func eventsRequests(srv *unifitest.Server) int {
+	n := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/api/s/default/stat/event" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEventsIterator(t *testing.T) {
+	defer unifi.SetIteratorPageSize(2)()
+	now := time.Now()
+	// minutesAgo returns the millisecond timestamp the controller uses
+	minutesAgo := func(m int) int64 {
+		return now.Add(-time.Duration(m)*time.Minute).UnixNano() / int64(time.Millisecond)
+	}
+
+	tests := []struct {
+		name         string
+		events       []int // minutes ago of the events added before iterating, with ids e<minutes>
+		inserted     []int // minutes ago of the events added right after the first page was served
+		since        time.Time
+		want         []string
+		wantRequests int
+	}{
+		{
+			name:         "dedupe across shifted pages",
+			events:       []int{1, 2, 3, 4, 5},
+			inserted:     []int{0, 0},
+			want:         []string{"e1", "e2", "e3", "e4", "e5"},
+			wantRequests: 4,
+		},
+		{
+			name:         "stop at the since watermark",
+			events:       []int{1, 2, 3, 4, 5},
+			since:        now.Add(-3 * time.Minute),
+			want:         []string{"e1", "e2"},
+			wantRequests: 2,
+		},
+		{
+			name:         "terminate on a short page",
+			events:       []int{1, 2, 3},
+			want:         []string{"e1", "e2", "e3"},
+			wantRequests: 2,
+		},
+		{
+			name:         "empty page after a full one",
+			events:       []int{1, 2, 3, 4},
+			want:         []string{"e1", "e2", "e3", "e4"},
+			wantRequests: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+			for _, m := range tt.events {
+				srv.AddEvent("default", unifitest.Object{"_id": fmt.Sprintf("e%d", m), "key": "EVT_WU_Connected", "time": minutesAgo(m)})
+			}
+
+			ctx := context.Background()
+			pages := 0
+			client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+				resp, err := next(ctx, req)
+				if req.Path == "/api/s/default/stat/event" {
+					pages++
+					if pages == 1 {
+						// newer events shift the offsets of the following pages
+						for i, m := range tt.inserted {
+							srv.AddEvent("default", unifitest.Object{"_id": fmt.Sprintf("n%d", i), "key": "EVT_WU_Connected", "time": minutesAgo(m)})
+						}
+					}
+				}
+				return resp, err
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			it := client.EventsIterator(ctx, "default", tt.since, unifi.EventSortOrderTimeDescending)
+			for it.Next() {
+				got = append(got, it.Event().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+			if n := eventsRequests(srv); n != tt.wantRequests {
+				t.Errorf("requested %d pages, want %d", n, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestAlarmsIteratorStopsAtWatermark(t *testing.T) {
+	defer unifi.SetIteratorPageSize(2)()
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	now := time.Now()
+	for m := 1; m <= 5; m++ {
+		srv.AddAlarm("default", unifitest.Object{"_id": fmt.Sprintf("a%d", m), "key": "EVT_AP_Lost_Contact", "time": now.Add(-time.Duration(m)*time.Minute).UnixNano() / int64(time.Millisecond)})
+	}
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	it := client.AlarmsIterator(ctx, "default", now.Add(-4*time.Minute), unifi.EventSortOrderTimeDescending, false)
+	for it.Next() {
+		got = append(got, it.Alarm().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[a1 a2 a3]" {
+		t.Fatalf("alarms = %v, want [a1 a2 a3]", got)
+	}
+}
@@ -0,0 +1,263 @@
+package unifi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig throttles the requests sent to a controller, small controllers like the Cloud Key
+// answer with 5xx errors or lock out logins when too many requests arrive at once.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 // sustained request rate, 0 disables the rate limit
+	Burst             int     // requests allowed above the sustained rate, defaults to 1
+	MaxInFlight       int     // maximum concurrent requests, 0 for unlimited
+	// MaxInFlightPerSite caps the concurrent requests per site, 0 for unlimited.
+	MaxInFlightPerSite int
+	// PerSiteFairness hands out the tokens round-robin between the sites waiting for one,
+	// so a loop over hundreds of sites can not starve the requests of another site. Without it tokens are granted in arrival order.
+	PerSiteFairness bool
+}
+
+// RateLimiter throttles the requests of the clients it is attached to.
+// Every attempt takes a token, including retries, the replay after a re-login and the login itself,
+// while a request holds its in-flight slots across all of its attempts.
+type RateLimiter struct {
+	bucket    *tokenBucket
+	inFlight  semaphore
+	siteSlots *siteSemaphores
+}
+
+// NewRateLimiter returns a rate limiter enforcing cfg, attach it to several clients with WithRateLimiter
+// to throttle them together.
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{}
+	if cfg.RequestsPerSecond > 0 {
+		l.bucket = newTokenBucket(cfg.RequestsPerSecond, cfg.Burst, cfg.PerSiteFairness)
+	}
+	if cfg.MaxInFlight > 0 {
+		l.inFlight = make(semaphore, cfg.MaxInFlight)
+	}
+	if cfg.MaxInFlightPerSite > 0 {
+		l.siteSlots = &siteSemaphores{size: cfg.MaxInFlightPerSite, sites: make(map[string]semaphore)}
+	}
+	return l
+}
+
+// WithRateLimit throttles all requests made by the client according to cfg.
+func WithRateLimit(cfg RateLimitConfig) ClientOption {
+	return WithRateLimiter(NewRateLimiter(cfg))
+}
+
+// WithRateLimiter throttles all requests made by the client with limiter, which may be shared between clients.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.rateLimiter = limiter
+		return nil
+	}
+}
+
+// acquire takes the in-flight slots of a request to site, the returned function releases them.
+// A nil limiter does not limit anything.
+func (l *RateLimiter) acquire(ctx context.Context, site string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	var held []semaphore
+	release := func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].release()
+		}
+	}
+	if l.siteSlots != nil {
+		sem := l.siteSlots.get(site)
+		if err := sem.acquire(ctx); err != nil {
+			return nil, err
+		}
+		held = append(held, sem)
+	}
+	if l.inFlight != nil {
+		if err := l.inFlight.acquire(ctx); err != nil {
+			release()
+			return nil, err
+		}
+		held = append(held, l.inFlight)
+	}
+	return release, nil
+}
+
+// wait blocks until the next attempt to site may be sent
+func (l *RateLimiter) wait(ctx context.Context, site string) error {
+	if l == nil || l.bucket == nil {
+		return nil
+	}
+	return l.bucket.wait(ctx, site)
+}
+
+// semaphore caps the number of concurrent holders to its capacity
+type semaphore chan struct{}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
+
+// siteSemaphores lazily creates a semaphore per site
+type siteSemaphores struct {
+	mu    sync.Mutex
+	size  int
+	sites map[string]semaphore
+}
+
+func (s *siteSemaphores) get(site string) semaphore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sem, ok := s.sites[site]
+	if !ok {
+		sem = make(semaphore, s.size)
+		s.sites[site] = sem
+	}
+	return sem
+}
+
+// tokenBucket is a token bucket rate limiter whose waiters are queued per site,
+// the sites are served round-robin when fair is set, otherwise all waiters share a single queue.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	fair   bool
+	queues map[string][]chan struct{} // waiters per site, closed once granted a token
+	order  []string                   // sites with waiters, the first one is served next
+}
+
+func newTokenBucket(rate float64, burst int, fair bool) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		fair:   fair,
+		queues: make(map[string][]chan struct{}),
+	}
+}
+
+// wait blocks until a token is granted to this waiter or the context is done
+func (b *tokenBucket) wait(ctx context.Context, site string) error {
+	if !b.fair {
+		site = ""
+	}
+	b.mu.Lock()
+	granted := b.enqueueLocked(site)
+	for {
+		delay := b.grantLocked()
+		b.mu.Unlock()
+
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		if delay > 0 {
+			timer = time.NewTimer(delay)
+			timeout = timer.C
+		}
+		select {
+		case <-granted:
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			b.cancel(site, granted)
+			return ctx.Err()
+		case <-timeout:
+		}
+		b.mu.Lock()
+	}
+}
+
+// enqueueLocked queues a waiter for site, the returned channel is closed once it is granted a token
+func (b *tokenBucket) enqueueLocked(site string) chan struct{} {
+	granted := make(chan struct{})
+	if len(b.queues[site]) == 0 {
+		b.order = append(b.order, site)
+	}
+	b.queues[site] = append(b.queues[site], granted)
+	return granted
+}
+
+// cancel removes a waiter whose context is done, a token granted in the meantime is handed to the next waiter
+func (b *tokenBucket) cancel(site string, granted chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-granted:
+		b.tokens++
+		b.grantLocked()
+		return
+	default:
+	}
+
+	queue := b.queues[site]
+	for i, w := range queue {
+		if w == granted {
+			queue = append(queue[:i:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) > 0 {
+		b.queues[site] = queue
+		return
+	}
+	delete(b.queues, site)
+	for i, s := range b.order {
+		if s == site {
+			b.order = append(b.order[:i:i], b.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// grantLocked refills the bucket and grants the available tokens one site at a time,
+// it returns how long until the next token when waiters are left.
+func (b *tokenBucket) grantLocked() time.Duration {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	for len(b.order) > 0 && b.tokens >= 1 {
+		site := b.order[0]
+		queue := b.queues[site]
+		close(queue[0])
+		b.tokens--
+		if len(queue) == 1 {
+			delete(b.queues, site)
+			b.order = b.order[1:]
+		} else {
+			// the site goes to the back of the line for its next waiter
+			b.queues[site] = queue[1:]
+			b.order = append(b.order[1:], site)
+		}
+	}
+	if len(b.order) == 0 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
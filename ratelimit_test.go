@@ -0,0 +1,107 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestTokenBucketFairness(t *testing.T) {
+	tests := []struct {
+		name string
+		fair bool
+		// token that goes to the quiet site, queued after 10 waiters of the busy site
+		position int
+	}{
+		{name: "round-robin", fair: true, position: 2},
+		{name: "arrival order", fair: false, position: 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(1, 1, tt.fair)
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.tokens = 0
+
+			site := func(s string) string {
+				if tt.fair {
+					return s
+				}
+				return ""
+			}
+			busy := make([]chan struct{}, 10)
+			for i := range busy {
+				busy[i] = b.enqueueLocked(site("busy"))
+			}
+			quiet := b.enqueueLocked(site("quiet"))
+
+			for position := 1; position <= 11; position++ {
+				// hand out a single token
+				b.tokens = 1
+				b.last = time.Now()
+				b.grantLocked()
+				if isClosed(quiet) {
+					if position != tt.position {
+						t.Fatalf("expected the quiet site to get token %d, got %d", tt.position, position)
+					}
+					return
+				}
+			}
+			t.Fatal("the quiet site was never granted a token")
+		})
+	}
+}
+
+func TestTokenBucketGrantsWaiters(t *testing.T) {
+	b := newTokenBucket(200, 1, true)
+	ctx := context.Background()
+
+	start := time.Now()
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		site := "busy"
+		if i%2 == 0 {
+			site = "quiet"
+		}
+		go func() {
+			errs <- b.wait(ctx, site)
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the burst covers the first waiter, the other 9 wait 5ms each
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("10 waiters were granted within %s", elapsed)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	b := newTokenBucket(20, 1, true)
+	if err := b.wait(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx, "a"); err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.queues) != 0 || len(b.order) != 0 {
+		t.Fatalf("cancelled waiter was left queued: %v %v", b.queues, b.order)
+	}
+}
@@ -0,0 +1,80 @@
+package unifi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+func TestRateLimitAppliesToRetries(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx,
+		unifi.WithRetryPolicy(unifi.RetryPolicy{
+			MaxAttempts:          4,
+			MinBackoff:           time.Millisecond,
+			MaxBackoff:           time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusBadGateway},
+		}),
+		unifi.WithRateLimit(unifi.RateLimitConfig{RequestsPerSecond: 20, Burst: 1}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.InjectFault(unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusBadGateway, Times: 3})
+
+	// the login took the burst, each of the 4 attempts then waits 50ms for its token
+	start := time.Now()
+	_, err = client.SiteHealth(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 175*time.Millisecond {
+		t.Fatalf("retries bypassed the rate limit, 4 attempts took %s", elapsed)
+	}
+
+	attempts := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/api/s/default/stat/health" {
+			attempts++
+		}
+	}
+	if attempts != 4 {
+		t.Fatalf("expected 4 attempts, got %d", attempts)
+	}
+}
+
+func TestRateLimiterSharedBetweenClients(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	limiter := unifi.NewRateLimiter(unifi.RateLimitConfig{RequestsPerSecond: 20, Burst: 1})
+	first, err := srv.Client(ctx, unifi.WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := srv.Client(ctx, unifi.WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := first.SiteHealth(ctx, "default"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := second.SiteHealth(ctx, "default"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 175*time.Millisecond {
+		t.Fatalf("clients sharing a limiter were not throttled together, 4 requests took %s", elapsed)
+	}
+}
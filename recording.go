@@ -0,0 +1,351 @@
+package unifi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Fixture is a recorded request/response pair
+type Fixture struct {
+	Method          string          `json:"method"`
+	Path            string          `json:"path"`
+	Query           string          `json:"query,omitempty"`
+	RequestBody     json.RawMessage `json:"request_body,omitempty"`
+	StatusCode      int             `json:"status_code"`
+	ResponseHeaders http.Header     `json:"response_headers,omitempty"`
+	ResponseBody    json.RawMessage `json:"response_body,omitempty"`
+}
+
+// RecordOptions controls how fixtures are sanitized before they are written.
+// Session cookies, CSRF tokens, API keys, passwords and the controller's x_ prefixed secrets are always removed.
+type RecordOptions struct {
+	ScrubMACs    bool     // replace mac addresses with stable fake ones, consistently across all fixtures, requests filtering on a mac then only replay when they are the only one recorded for the endpoint
+	RedactFields []string // additional json fields to redact
+}
+
+// sensitiveHeaders are removed from the recorded fixtures
+var sensitiveHeaders = []string{"Cookie", "Set-Cookie", "Authorization", csrfTokenHeader, updatedCSRFTokenHeader, apiKeyHeader}
+
+// sensitiveFields are json fields always redacted from the recorded fixtures, next to any x_ prefixed field
+var sensitiveFields = []string{"password", "passphrase", "token", "ubic_2fa_token"}
+
+// redacted replaces sensitive values
+const redacted = "REDACTED"
+
+var macPattern = regexp.MustCompile(`(?i)\b[0-9a-f]{2}(:[0-9a-f]{2}){5}\b`)
+
+// Recorder captures the requests of a client as fixture files, one file per endpoint and request body.
+// Use it with WithRecorder against a real controller, then serve the fixtures with NewReplayTransport.
+type Recorder struct {
+	Transport http.RoundTripper // transport performing the requests, defaults to the client transport
+
+	dir     string
+	opts    RecordOptions
+	mu      sync.Mutex
+	macs    map[string]string
+	redacts map[string]bool
+}
+
+// NewRecorder creates a recorder writing fixtures into dir
+func NewRecorder(dir string, opts RecordOptions) *Recorder {
+	redacts := make(map[string]bool)
+	for _, field := range append(sensitiveFields, opts.RedactFields...) {
+		redacts[strings.ToLower(field)] = true
+	}
+	return &Recorder{
+		dir:     dir,
+		opts:    opts,
+		macs:    make(map[string]string),
+		redacts: redacts,
+	}
+}
+
+// WithRecorder records every request of the client with the recorder
+func WithRecorder(r *Recorder) ClientOption {
+	return func(c *Client) error {
+		if r.Transport == nil {
+			r.Transport = c.HTTPClient.Transport
+		}
+		c.HTTPClient.Transport = r
+		return nil
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	next := r.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	err = r.write(req, reqBody, resp, respBody)
+	if err != nil {
+		return nil, fmt.Errorf("unable to record fixture: %v", err)
+	}
+	return resp, nil
+}
+
+// write sanitizes and stores the fixture
+func (r *Recorder) write(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	headers := resp.Header.Clone()
+	for _, h := range sensitiveHeaders {
+		headers.Del(h)
+	}
+	// the body may change while sanitizing, and dates only add noise to fixture diffs
+	headers.Del("Content-Length")
+	headers.Del("Date")
+	fixture := Fixture{
+		Method:          req.Method,
+		Path:            r.scrub(req.URL.Path),
+		Query:           r.scrub(req.URL.RawQuery),
+		RequestBody:     r.sanitizeBody(reqBody),
+		StatusCode:      resp.StatusCode,
+		ResponseHeaders: headers,
+		ResponseBody:    r.sanitizeBody(respBody),
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(r.dir, 0755)
+	if err != nil {
+		return err
+	}
+	name := fixtureName(fixture.Method, fixture.Path, fixture.Query, bodyKey(fixture.RequestBody))
+	return ioutil.WriteFile(filepath.Join(r.dir, name), data, 0644)
+}
+
+// sanitizeBody redacts sensitive fields and scrubs mac addresses, non json bodies are stored as a json string
+func (r *Recorder) sanitizeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		data, _ := json.Marshal(r.scrub(string(body)))
+		return data
+	}
+	data, _ := json.Marshal(r.sanitizeValue(v))
+	return data
+}
+
+// sanitizeValue walks a decoded json value
+func (r *Recorder) sanitizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, field := range val {
+			key := strings.ToLower(k)
+			if r.redacts[key] || strings.HasPrefix(key, "x_") {
+				val[k] = redacted
+				continue
+			}
+			val[k] = r.sanitizeValue(field)
+		}
+		return val
+	case []interface{}:
+		for i := range val {
+			val[i] = r.sanitizeValue(val[i])
+		}
+		return val
+	case string:
+		return r.scrub(val)
+	default:
+		return v
+	}
+}
+
+// scrub replaces mac addresses when enabled, the same mac is always replaced by the same fake one
+func (r *Recorder) scrub(s string) string {
+	if !r.opts.ScrubMACs {
+		return s
+	}
+	return macPattern.ReplaceAllStringFunc(s, func(mac string) string {
+		mac = strings.ToLower(mac)
+		fake, ok := r.macs[mac]
+		if !ok {
+			n := len(r.macs) + 1
+			// locally administered unicast range, never assigned to real hardware
+			fake = fmt.Sprintf("02:00:00:%02x:%02x:%02x", n>>16&0xff, n>>8&0xff, n&0xff)
+			r.macs[mac] = fake
+		}
+		return fake
+	})
+}
+
+var fixtureNameReplacer = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixtureName returns the file name of the fixture for the endpoint and the key of the request body
+func fixtureName(method string, path string, query string, bodyKey string) string {
+	name := endpointKey(method, path, query)
+	if bodyKey != "" {
+		name = name + "_" + bodyKey
+	}
+	return name + ".json"
+}
+
+// endpointKey identifies the endpoint of a request by method, path and query
+func endpointKey(method string, path string, query string) string {
+	name := strings.Trim(path, "/")
+	if query != "" {
+		name = name + "_" + query
+	}
+	if name == "" {
+		name = "root"
+	}
+	return method + "_" + fixtureNameReplacer.ReplaceAllString(name, "_")
+}
+
+// keySanitizer redacts the always sensitive fields of request bodies before they are hashed
+var keySanitizer = NewRecorder("", RecordOptions{})
+
+// bodyKey returns a short hash of the normalized request body, empty if there is none.
+// The paged endpoints like stat/event send the offset in the body, so every page gets its own fixture.
+// Sensitive fields are redacted like in the recorded fixtures, and the keys of json objects are sorted when encoded.
+func bodyKey(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		// non json bodies are recorded as a json string
+		v = string(body)
+	}
+	data, _ := json.Marshal(keySanitizer.sanitizeValue(v))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// ReplayTransport serves recorded fixtures instead of talking to a controller.
+// Requests are matched on method, path, query and body, requests without a fixture fail.
+// A request whose body differs from the recorded one, like a login with the real password, is still served
+// when it is the only fixture recorded for the endpoint.
+type ReplayTransport struct {
+	fixtures map[string]map[string]Fixture // endpoint key to body key to fixture
+}
+
+// NewReplayTransport loads the fixtures recorded in dir
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	t := &ReplayTransport{fixtures: make(map[string]map[string]Fixture)}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var fixture Fixture
+		err = json.Unmarshal(data, &fixture)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %v", file, err)
+		}
+		endpoint := endpointKey(fixture.Method, fixture.Path, fixture.Query)
+		if t.fixtures[endpoint] == nil {
+			t.fixtures[endpoint] = make(map[string]Fixture)
+		}
+		t.fixtures[endpoint][bodyKey(fixture.RequestBody)] = fixture
+	}
+	return t, nil
+}
+
+// lookup returns the fixture recorded for the request
+func (t *ReplayTransport) lookup(method string, path string, query string, body []byte) (Fixture, bool) {
+	recorded := t.fixtures[endpointKey(method, path, query)]
+	if fixture, ok := recorded[bodyKey(body)]; ok {
+		return fixture, true
+	}
+	if len(recorded) == 1 {
+		for _, fixture := range recorded {
+			return fixture, true
+		}
+	}
+	return Fixture{}, false
+}
+
+// WithReplay serves the fixtures recorded in dir instead of talking to the controller
+func WithReplay(dir string) ClientOption {
+	return func(c *Client) error {
+		t, err := NewReplayTransport(dir)
+		if err != nil {
+			return err
+		}
+		c.HTTPClient.Transport = t
+		return nil
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	fixture, ok := t.lookup(req.Method, req.URL.Path, req.URL.RawQuery, reqBody)
+	if !ok {
+		return nil, fmt.Errorf("no fixture recorded for %s %s", req.Method, req.URL.RequestURI())
+	}
+
+	body := []byte(fixture.ResponseBody)
+	var s string
+	if json.Unmarshal(fixture.ResponseBody, &s) == nil {
+		// non json bodies are stored as a json string
+		body = []byte(s)
+	}
+	header := fixture.ResponseHeaders.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.StatusCode, http.StatusText(fixture.StatusCode)),
+		StatusCode:    fixture.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
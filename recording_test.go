@@ -0,0 +1,174 @@
+package unifi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// readFixtures returns the raw content of every fixture recorded in dir
+func readFixtures(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures := make(map[string][]byte, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixtures[filepath.Base(file)] = data
+	}
+	return fixtures
+}
+
+func TestRecorderSanitizesFixtures(t *testing.T) {
+	const (
+		apMAC     = "AA:BB:CC:DD:EE:01"
+		clientMAC = "aa:bb:cc:dd:ee:02"
+		secret    = "wpa-secret-value"
+	)
+	srv := unifitest.NewServer(unifitest.WithUniFiOS())
+	defer srv.Close()
+	srv.AddDevice("default", unifitest.Object{"mac": strings.ToLower(apMAC), "type": "uap", "x_authkey": secret})
+	srv.AddClient("default", unifitest.Object{"mac": clientMAC, "ap_mac": apMAC, "hostname": "laptop"})
+
+	dir, err := ioutil.TempDir("", "unifi-recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithRecorder(unifi.NewRecorder(dir, unifi.RecordOptions{ScrubMACs: true})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err := client.SiteDevicesBasic(ctx, "default", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients, err := client.SiteActiveClients(ctx, "default", clientMAC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices.Data) != 1 || len(clients.Data) != 1 {
+		t.Fatalf("expected a device and a client, got %d and %d", len(devices.Data), len(clients.Data))
+	}
+	// the client must not see the sanitized response
+	if clients.Data[0].MAC != clientMAC {
+		t.Fatalf("expected the real mac to be returned, got %s", clients.Data[0].MAC)
+	}
+
+	fixtures := readFixtures(t, dir)
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures recorded")
+	}
+	var loginFixture, deviceFixture, clientFixture unifi.Fixture
+	for name, data := range fixtures {
+		content := strings.ToLower(string(data))
+		for _, leak := range []string{unifitest.DefaultPassword, secret, strings.ToLower(apMAC), clientMAC} {
+			if strings.Contains(content, strings.ToLower(leak)) {
+				t.Errorf("%s contains %q", name, leak)
+			}
+		}
+
+		var fixture unifi.Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, h := range []string{"Set-Cookie", "X-Csrf-Token", "X-Updated-Csrf-Token"} {
+			if v := fixture.ResponseHeaders.Get(h); v != "" {
+				t.Errorf("%s recorded the %s header: %s", name, h, v)
+			}
+		}
+		switch {
+		case fixture.Method == http.MethodPost && strings.HasSuffix(fixture.Path, "/api/auth/login"):
+			loginFixture = fixture
+		case strings.HasSuffix(fixture.Path, "/stat/device-basic"):
+			deviceFixture = fixture
+		case strings.Contains(fixture.Path, "/stat/sta/"):
+			clientFixture = fixture
+		}
+	}
+
+	var login map[string]interface{}
+	if err := json.Unmarshal(loginFixture.RequestBody, &login); err != nil {
+		t.Fatal(err)
+	}
+	if login["password"] != "REDACTED" || login["username"] != unifitest.DefaultUsername {
+		t.Fatalf("expected only the password to be redacted, got %v", login)
+	}
+
+	var device struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(deviceFixture.ResponseBody, &device); err != nil {
+		t.Fatal(err)
+	}
+	var sta struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(clientFixture.ResponseBody, &sta); err != nil {
+		t.Fatal(err)
+	}
+	if len(device.Data) != 1 || len(sta.Data) != 1 {
+		t.Fatalf("unexpected fixtures %s and %s", deviceFixture.ResponseBody, clientFixture.ResponseBody)
+	}
+	if device.Data[0]["x_authkey"] != "REDACTED" {
+		t.Fatalf("expected the x_ field to be redacted, got %v", device.Data[0]["x_authkey"])
+	}
+
+	// the same mac is scrubbed to the same fake one in every fixture, in paths as well as bodies
+	fakeAP, _ := device.Data[0]["mac"].(string)
+	fakeClient, _ := sta.Data[0]["mac"].(string)
+	if fakeAP == "" || fakeClient == "" || fakeAP == fakeClient {
+		t.Fatalf("expected distinct fake macs, got %q and %q", fakeAP, fakeClient)
+	}
+	if sta.Data[0]["ap_mac"] != fakeAP {
+		t.Fatalf("expected the ap mac to be scrubbed to %s, got %v", fakeAP, sta.Data[0]["ap_mac"])
+	}
+	if !strings.HasSuffix(clientFixture.Path, "/stat/sta/"+fakeClient) {
+		t.Fatalf("expected the mac in the path to be scrubbed to %s, got %s", fakeClient, clientFixture.Path)
+	}
+}
+
+func TestRecorderDoesNotRecordAPIKey(t *testing.T) {
+	const apiKey = "recorder-test-api-key"
+	srv := unifitest.NewServer(unifitest.WithAPIKey(apiKey))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "unifi-recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := unifi.NewClient(srv.URL, nil, 0, unifi.WithAPIKey(apiKey), unifi.WithRecorder(unifi.NewRecorder(dir, unifi.RecordOptions{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AvailableSites(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := readFixtures(t, dir)
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures recorded")
+	}
+	for name, data := range fixtures {
+		if strings.Contains(string(data), apiKey) {
+			t.Errorf("%s contains the api key", name)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package unifi_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+// fixtureVersions are the controller versions recorded under testdata
+var fixtureVersions = []struct {
+	version string
+	flavor  unifi.ControllerFlavor
+}{
+	{version: "6.5.55", flavor: unifi.ControllerFlavorClassic},
+	{version: "7.4.162", flavor: unifi.ControllerFlavorUniFiOS},
+}
+
+// replayClient returns a client logged in against the fixtures recorded for version
+func replayClient(t *testing.T, version string) *unifi.Client {
+	t.Helper()
+	client, err := unifi.NewClient("https://unifi.example.com", nil, 10*time.Second,
+		unifi.WithReplay(filepath.Join("testdata", version)), unifi.WithRetryPolicy(unifi.NoRetryPolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Login(context.Background(), "admin", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestReplayDecodesTypedResponses(t *testing.T) {
+	ctx := context.Background()
+	reportEnd := time.Unix(1602900000, 0).UTC()
+	reportStart := reportEnd.Add(-24 * time.Hour)
+
+	for _, fv := range fixtureVersions {
+		fv := fv
+		t.Run(fv.version, func(t *testing.T) {
+			client := replayClient(t, fv.version)
+			if flavor := client.ControllerFlavor(); flavor != fv.flavor {
+				t.Fatalf("detected flavor %q, want %q", flavor, fv.flavor)
+			}
+
+			tests := []struct {
+				name  string
+				check func(t *testing.T)
+			}{
+				{"ControllerStatus", func(t *testing.T) {
+					resp, err := client.ControllerStatus(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if resp.Meta.ServerVersion != fv.version || !resp.Meta.Up {
+						t.Errorf("unexpected status meta %+v", resp.Meta)
+					}
+				}},
+				{"AvailableSites", func(t *testing.T) {
+					resp, err := client.AvailableSites(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].Name != "default" || resp.Data[1].LocationLatitude == 0 {
+						t.Errorf("unexpected sites %+v", resp.Data)
+					}
+				}},
+				{"AvailableSitesVerbose", func(t *testing.T) {
+					resp, err := client.AvailableSitesVerbose(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].NumberNewAlarms != 2 || len(resp.Data[0].Health) == 0 {
+						t.Errorf("unexpected verbose sites %+v", resp.Data)
+					}
+				}},
+				{"SiteAdmins", func(t *testing.T) {
+					resp, err := client.SiteAdmins(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0]["name"] != "admin" {
+						t.Errorf("unexpected admins %+v", resp.Data)
+					}
+				}},
+				{"Self", func(t *testing.T) {
+					resp, err := client.Self(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || !resp.Data[0].IsSuper || resp.Data[0].LastSiteName != "default" {
+						t.Errorf("unexpected self %+v", resp.Data)
+					}
+				}},
+				{"SiteActiveClients", func(t *testing.T) {
+					resp, err := client.SiteActiveClients(ctx, "default", "")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].HostName != "laptop-7" || resp.Data[1].MAC != "00:11:32:aa:bb:cc" {
+						t.Errorf("unexpected clients %+v", resp.Data)
+					}
+				}},
+				{"SiteActiveRoutes", func(t *testing.T) {
+					resp, err := client.SiteActiveRoutes(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].PFX != "0.0.0.0/0" || len(resp.Data[0].NH) != 1 {
+						t.Errorf("unexpected routes %+v", resp.Data)
+					}
+				}},
+				{"SiteAlarms", func(t *testing.T) {
+					resp, err := client.SiteAlarms(ctx, "default", 24, 0, 100, unifi.EventSortOrderTimeDescending, false)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].Key != "EVT_IPS_IpsAlert" || resp.Data[0].InnerAlertSignatureID != 2025705 {
+						t.Errorf("unexpected alarms %+v", resp.Data)
+					}
+				}},
+				{"SiteAlarmsCount", func(t *testing.T) {
+					resp, err := client.SiteAlarmsCount(ctx, "default", 24, false)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0]["count"] != float64(2) {
+						t.Errorf("unexpected alarm count %+v", resp.Data)
+					}
+				}},
+				{"SiteCountryCodes", func(t *testing.T) {
+					resp, err := client.SiteCountryCodes(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].Key != "US" || resp.Data[0].Code == nil {
+						t.Errorf("unexpected country codes %+v", resp.Data)
+					}
+				}},
+				{"SiteCurrentChannels", func(t *testing.T) {
+					resp, err := client.SiteCurrentChannels(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || len(resp.Data[0].ChannelsNA) != 9 {
+						t.Errorf("unexpected channels %+v", resp.Data)
+					}
+				}},
+				{"SiteDetailedSettings", func(t *testing.T) {
+					resp, err := client.SiteDetailedSettings(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 4 || resp.Data[0]["key"] != "mgmt" {
+						t.Errorf("unexpected settings %+v", resp.Data)
+					}
+				}},
+				{"SiteDevicesBasic", func(t *testing.T) {
+					resp, err := client.SiteDevicesBasic(ctx, "default", "")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 3 || resp.Data[0].Type != "ugw" || !resp.Data[0].Adopted {
+						t.Errorf("unexpected basic devices %+v", resp.Data)
+					}
+				}},
+				{"SiteDevicesDetailed", func(t *testing.T) {
+					resp, err := client.SiteDevicesDetailed(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].Model != "U7LR" || resp.Data[1].Type != "usw" || resp.Data[1].Uptime == 0 {
+						t.Errorf("unexpected detailed devices %+v", resp.Data)
+					}
+				}},
+				{"SiteEvents", func(t *testing.T) {
+					resp, err := client.SiteEvents(ctx, "default", 24, 0, 100, unifi.EventSortOrderTimeDescending)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 4 || resp.Data[0].Key != "EVT_WU_Connected" || resp.Data[0].Time == 0 {
+						t.Errorf("unexpected events %+v", resp.Data)
+					}
+				}},
+				{"SiteIPSEvents", func(t *testing.T) {
+					resp, err := client.SiteIPSEvents(ctx, "default", reportStart, reportEnd, 0, 100, unifi.EventSortOrderTimeDescending)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0].Key != "EVT_IPS_IpsAlert" {
+						t.Errorf("unexpected ips events %+v", resp.Data)
+					}
+				}},
+				{"SiteFirewallGroups", func(t *testing.T) {
+					resp, err := client.SiteFirewallGroups(ctx, "default", "")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[1]["group_type"] != "port-group" {
+						t.Errorf("unexpected firewall groups %+v", resp.Data)
+					}
+				}},
+				{"SiteFirewallRules", func(t *testing.T) {
+					resp, err := client.SiteFirewallRules(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0]["ruleset"] != "WAN_IN" {
+						t.Errorf("unexpected firewall rules %+v", resp.Data)
+					}
+				}},
+				{"ListFirewallRules", func(t *testing.T) {
+					// the typed rules come back in evaluation order
+					resp, err := client.ListFirewallRules(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0].Ruleset != unifi.FirewallRuleset("LAN_IN") || resp.Data[0].RuleIndex != 2001 {
+						t.Errorf("unexpected typed firewall rules %+v", resp.Data)
+					}
+				}},
+				{"SiteHealth", func(t *testing.T) {
+					resp, err := client.SiteHealth(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 5 || resp.Data[0].SubSystem != "wlan" || resp.Data[0].NumberAccessPoints != 3 {
+						t.Errorf("unexpected health %+v", resp.Data)
+					}
+				}},
+				{"SiteReport", func(t *testing.T) {
+					resp, err := client.SiteReport(ctx, "default", reportStart, reportEnd, unifi.ReportIntervalHourly, unifi.ReportTypeSite,
+						[]unifi.ReportAttribute{
+							unifi.ReportAttributeNumberSTA, unifi.ReportAttributeWLANBytes, unifi.ReportAttributeWANTXBytes,
+							unifi.ReportAttributeWANRXBytes, unifi.ReportAttributeLANNumberSTA, unifi.ReportAttributeWLANNumberSTA,
+						})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[1]["num_sta"] != float64(21) {
+						t.Errorf("unexpected report %+v", resp.Data)
+					}
+				}},
+				{"SiteRougeAccessPoints", func(t *testing.T) {
+					resp, err := client.SiteRougeAccessPoints(ctx, "default", 24)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0].ESSID != "Neighbor-2G" || resp.Data[0].Channel != 6 {
+						t.Errorf("unexpected rogue access points %+v", resp.Data)
+					}
+				}},
+				{"SiteRougeKnownAccessPoints", func(t *testing.T) {
+					resp, err := client.SiteRougeKnownAccessPoints(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0].BSSID != "c8:3a:35:01:02:03" {
+						t.Errorf("unexpected known rogue access points %+v", resp.Data)
+					}
+				}},
+				{"SiteSysInfo", func(t *testing.T) {
+					resp, err := client.SiteSysInfo(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0].Version != fv.version || resp.Data[0].HTTPSPort == 0 {
+						t.Errorf("unexpected sysinfo %+v", resp.Data)
+					}
+				}},
+				{"SiteTaggedMACs", func(t *testing.T) {
+					resp, err := client.SiteTaggedMACs(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0]["name"] != "Cameras" {
+						t.Errorf("unexpected tags %+v", resp.Data)
+					}
+				}},
+				{"SiteUserDefinedRoutes", func(t *testing.T) {
+					resp, err := client.SiteUserDefinedRoutes(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0]["static-route_network"] != "10.10.0.0/16" {
+						t.Errorf("unexpected user routes %+v", resp.Data)
+					}
+				}},
+				{"SiteWLANConfigs", func(t *testing.T) {
+					resp, err := client.SiteWLANConfigs(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[0]["name"] != "HomeNet" {
+						t.Errorf("unexpected wlan configs %+v", resp.Data)
+					}
+				}},
+				{"SiteWLANGroups", func(t *testing.T) {
+					resp, err := client.SiteWLANGroups(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 1 || resp.Data[0]["name"] != "Default" {
+						t.Errorf("unexpected wlan groups %+v", resp.Data)
+					}
+				}},
+				{"ListWLANs", func(t *testing.T) {
+					resp, err := client.ListWLANs(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					// the VLAN is a string on the 5.x controllers
+					if len(resp.Data) != 2 || resp.Data[1].Name != "HomeNet-IoT" || resp.Data[1].VLAN != 30 {
+						t.Errorf("unexpected wlans %+v", resp.Data)
+					}
+				}},
+				{"ListNetworks", func(t *testing.T) {
+					resp, err := client.ListNetworks(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 4 || resp.Data[1].VLAN != 30 || resp.Data[2].WANVLAN != 201 || resp.Data[0].IPSubnet != "192.168.1.1/24" {
+						t.Errorf("unexpected networks %+v", resp.Data)
+					}
+				}},
+				{"ListPortForwards", func(t *testing.T) {
+					resp, err := client.ListPortForwards(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 2 || resp.Data[1].Protocol != unifi.PortForwardProtocolTCPUDP || resp.Data[1].DstPort != "27015-27030,27036" {
+						t.Errorf("unexpected port forwards %+v", resp.Data)
+					}
+				}},
+				{"ListPortProfiles", func(t *testing.T) {
+					resp, err := client.ListPortProfiles(ctx, "default")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(resp.Data) != 3 || resp.Data[1].Name != "Camera" {
+						t.Errorf("unexpected port profiles %+v", resp.Data)
+					}
+				}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, tt.check)
+			}
+		})
+	}
+}
+
+// TestReplayPagedEvents checks every page of a paged endpoint replays its own fixture,
+// the pages only differ by the _start offset sent in the request body.
+func TestReplayPagedEvents(t *testing.T) {
+	ctx := context.Background()
+	for _, fv := range fixtureVersions {
+		fv := fv
+		t.Run(fv.version, func(t *testing.T) {
+			client := replayClient(t, fv.version)
+
+			seen := make(map[string]bool)
+			for offset := 0; ; offset += 2 {
+				resp, err := client.SiteEvents(ctx, "default", 24, offset, 2, unifi.EventSortOrderTimeDescending)
+				if err != nil {
+					t.Fatalf("page at offset %d: %v", offset, err)
+				}
+				for _, event := range resp.Data {
+					if seen[event.ID] {
+						t.Fatalf("page at offset %d replayed event %s again", offset, event.ID)
+					}
+					seen[event.ID] = true
+				}
+				if len(resp.Data) < 2 {
+					break
+				}
+			}
+			if len(seen) != 4 {
+				t.Fatalf("expected 4 events over all pages, got %d", len(seen))
+			}
+
+			_, err := client.SiteEvents(ctx, "default", 24, 6, 2, unifi.EventSortOrderTimeDescending)
+			if err == nil {
+				t.Fatal("a page that was never recorded must not be replayed")
+			}
+		})
+	}
+}
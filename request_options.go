@@ -0,0 +1,132 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RequestOption customizes a single api request, like adding query filters or headers.
+// The api methods sending a single request accept them as trailing arguments, they apply to that request only,
+// never to the requests a method sends on its own, like the conflict check of CreatePortForward.
+// Methods with variadic arguments of their own, like SiteDevicesDetailed, take them in their WithOptions variant.
+//
+// Example, to only fetch the ips events of the last 2 hours:
+//
+//	events, err := client.SiteEvents(ctx, "default", 2, 0, 100, unifi.EventSortOrderTimeDescending, unifi.Type("ips"))
+type RequestOption func(o *requestOptions)
+
+// requestOptions collects the options applied to a request
+type requestOptions struct {
+	query  url.Values
+	header http.Header
+	body   interface{}
+	encode BodyEncoder
+}
+
+// newRequestOptions collects the given options, later options override earlier ones
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{
+		query:  make(url.Values),
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// QueryParam sets a query parameter, replacing any previous value.
+func QueryParam(key string, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.query.Set(key, value)
+	}
+}
+
+// QueryParams adds all the given query parameters.
+func QueryParams(values url.Values) RequestOption {
+	return func(o *requestOptions) {
+		for key, vals := range values {
+			for _, v := range vals {
+				o.query.Add(key, v)
+			}
+		}
+	}
+}
+
+// Within limits the results to the last hours, supported by the stat endpoints.
+func Within(hours int) RequestOption {
+	return QueryParam("within", strconv.Itoa(hours))
+}
+
+// Limit limits the number of results returned.
+func Limit(limit int) RequestOption {
+	return QueryParam("_limit", strconv.Itoa(limit))
+}
+
+// Type filters the results by their type, like `ips` or `device` on events.
+func Type(typ string) RequestOption {
+	return QueryParam("type", typ)
+}
+
+// Archived selects archived or active alarms.
+func Archived(archived bool) RequestOption {
+	return QueryParam("archived", strconv.FormatBool(archived))
+}
+
+// Header sets a request header, replacing the client default for that header.
+func Header(key string, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// Body replaces the request payload with v encoded by encode.
+func Body(v interface{}, encode BodyEncoder) RequestOption {
+	return func(o *requestOptions) {
+		o.body = v
+		o.encode = encode
+	}
+}
+
+// BodyEncoder encodes a request payload and returns it with its content type.
+type BodyEncoder func(v interface{}) (contentType string, body []byte, err error)
+
+// JSONEncoder encodes the payload as json, the format expected by almost all endpoints.
+func JSONEncoder(v interface{}) (string, []byte, error) {
+	body, err := json.Marshal(v)
+	return ContentTypeHeader, body, err
+}
+
+// FormEncoder encodes url.Values as a url encoded form.
+func FormEncoder(v interface{}) (string, []byte, error) {
+	values, ok := v.(url.Values)
+	if !ok {
+		return "", nil, fmt.Errorf("form encoder expects url.Values, got %T", v)
+	}
+	return "application/x-www-form-urlencoded", []byte(values.Encode()), nil
+}
+
+// apply merges the options into the request
+func (o *requestOptions) apply(req *APIRequest) error {
+	if len(o.query) > 0 && req.Query == nil {
+		req.Query = make(url.Values)
+	}
+	for key, vals := range o.query {
+		req.Query[key] = vals
+	}
+	for key, vals := range o.header {
+		req.Header[key] = vals
+	}
+	if o.encode != nil {
+		contentType, body, err := o.encode(o.body)
+		if err != nil {
+			return err
+		}
+		req.Body = body
+		req.Header.Set("Content-Type", contentType)
+	}
+	return nil
+}
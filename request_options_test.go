@@ -0,0 +1,160 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// lastQuery returns the query of the last request received for path
+func lastQuery(t *testing.T, srv *unifitest.Server, method string, path string) url.Values {
+	t.Helper()
+	requests := srv.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == method && requests[i].Path == path {
+			query, err := url.ParseQuery(requests[i].Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return query
+		}
+	}
+	t.Fatalf("no %s %s request received", method, path)
+	return nil
+}
+
+func TestRequestOptionsQuery(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		path string
+		want url.Values
+	}{
+		{
+			name: "within limit and type",
+			call: func() error {
+				_, err := client.SiteEvents(ctx, "default", 1, 0, 10, unifi.EventSortOrderTimeDescending,
+					unifi.Within(2), unifi.Limit(5), unifi.Type("ips"))
+				return err
+			},
+			path: "/api/s/default/stat/event",
+			want: url.Values{"within": {"2"}, "_limit": {"5"}, "type": {"ips"}},
+		},
+		{
+			name: "archived from the method argument",
+			call: func() error {
+				_, err := client.SiteAlarms(ctx, "default", 1, 0, 10, unifi.EventSortOrderTimeDescending, true)
+				return err
+			},
+			path: "/api/s/default/stat/alarm",
+			want: url.Values{"archived": {"true"}},
+		},
+		{
+			name: "archived overridden by an option",
+			call: func() error {
+				_, err := client.SiteAlarms(ctx, "default", 1, 0, 10, unifi.EventSortOrderTimeDescending, true, unifi.Archived(false))
+				return err
+			},
+			path: "/api/s/default/stat/alarm",
+			want: url.Values{"archived": {"false"}},
+		},
+		{
+			name: "variadic method through its WithOptions variant",
+			call: func() error {
+				_, err := client.SiteDevicesDetailedWithOptions(ctx, "default", nil, unifi.QueryParam("foo", "bar"))
+				return err
+			},
+			path: "/api/s/default/stat/device",
+			want: url.Values{"foo": {"bar"}},
+		},
+		{
+			name: "custom query param",
+			call: func() error {
+				_, err := client.SiteHealth(ctx, "default", unifi.QueryParam("foo", "bar"))
+				return err
+			},
+			path: "/api/s/default/stat/health",
+			want: url.Values{"foo": {"bar"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatal(err)
+			}
+			query := lastQuery(t, srv, http.MethodGet, tt.path)
+			for key, want := range tt.want {
+				if got := query[key]; len(got) != len(want) || got[0] != want[0] {
+					t.Errorf("query %s = %v, want %v (query %q)", key, got, want, query.Encode())
+				}
+			}
+		})
+	}
+}
+
+func TestRequestOptionsHeaderAndBody(t *testing.T) {
+	var gotHeader string
+	var gotBody []byte
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+		gotHeader = req.Header.Get("X-Test")
+		gotBody = req.Body
+		return next(ctx, req)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SiteHealth(ctx, "default", unifi.Header("X-Test", "yes"), unifi.Body(map[string]int{"_limit": 3}, unifi.JSONEncoder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotHeader != "yes" {
+		t.Errorf("header X-Test = %q, want yes", gotHeader)
+	}
+	if string(gotBody) != `{"_limit":3}` {
+		t.Errorf("body = %s", gotBody)
+	}
+}
+
+// TestRequestOptionsNotAppliedToInternalRequests checks the options of a call do not leak into the requests
+// the method sends on its own, a limited conflict check would miss existing port forwards.
+func TestRequestOptionsNotAppliedToInternalRequests(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.Add("default", "portforward", unifitest.Object{"name": "web", "enabled": true, "src": "any", "dst_port": "80", "fwd": "10.0.0.2", "fwd_port": "80", "proto": "tcp"})
+	srv.Add("default", "portforward", unifitest.Object{"name": "ssh", "enabled": true, "src": "any", "dst_port": "22", "fwd": "10.0.0.3", "fwd_port": "22", "proto": "tcp"})
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreatePortForward(ctx, "default", unifi.PortForward{
+		Name: "ssh2", Enabled: true, Src: "any", DstPort: "22", Fwd: "10.0.0.4", FwdPort: "22", Protocol: unifi.PortForwardProtocolTCP,
+	}, unifi.Limit(1))
+	if !errors.Is(err, unifi.ErrPortForwardConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if query := lastQuery(t, srv, http.MethodGet, "/api/s/default/rest/portforward"); len(query) != 0 {
+		t.Fatalf("the conflict check sent the caller options: %q", query.Encode())
+	}
+}
@@ -0,0 +1,176 @@
+package unifi
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy defines how requests that failed with a transient error are retried.
+// Only safe methods (GET, HEAD, OPTIONS) are retried unless RetryMutatingMethods is set,
+// and all attempts together are bounded by Client.RetryTimeout when it is non-zero.
+type RetryPolicy struct {
+	MaxAttempts          int           // total attempts including the first one, 1 or less disables retries
+	MinBackoff           time.Duration // backoff before the first retry, doubled for every following retry
+	MaxBackoff           time.Duration // upper bound for a single backoff, 0 for no bound
+	Jitter               float64       // fraction [0, 1] of the backoff that is randomized
+	RetryableStatusCodes []int         // http status codes that are considered transient
+	RetryMutatingMethods bool          // opt-in to also retry POST, PUT and DELETE commands
+	// RetryableError classifies transport errors, defaults to IsRetryableError when nil
+	RetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Jitter:      0.5,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// NoRetryPolicy returns a retry policy that never retries
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// WithRetryPolicy overrides the default retry policy
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// SetRetryPolicy overrides the retry policy
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retryPolicy = policy
+}
+
+// IsRetryableError returns true for transport errors that are likely transient:
+// timeouts, refused or reset connections and connections closed mid-response.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// allowsMethod returns true if requests with this method may be retried
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return p.RetryMutatingMethods
+	}
+}
+
+// retryableStatus returns true if the status code is considered transient
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// retryableError returns true if the transport error is considered transient
+func (p RetryPolicy) retryableError(err error) bool {
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+	return IsRetryableError(err)
+}
+
+// backoff returns the time to wait before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff) && d < math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 && d > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
+
+// roundTrip performs the request, retrying transient failures according to the retry policy.
+func (c *Client) roundTrip(ctx context.Context, req *APIRequest) (int, []byte, error) {
+	c.mu.RLock()
+	policy := c.retryPolicy
+	c.mu.RUnlock()
+
+	var deadline time.Time
+	if c.RetryTimeout > 0 {
+		deadline = time.Now().Add(c.RetryTimeout)
+	}
+
+	for attempt := 1; ; attempt++ {
+		// every attempt takes a token, so retries do not multiply the configured rate
+		if err := c.rateLimiter.wait(ctx, req.Site); err != nil {
+			return 0, nil, err
+		}
+		statusCode, body, err := c.roundTripOnce(ctx, req)
+
+		retry := attempt < policy.MaxAttempts && policy.allowsMethod(req.Method)
+		if err != nil {
+			retry = retry && policy.retryableError(err)
+		} else {
+			retry = retry && policy.retryableStatus(statusCode)
+		}
+		if !retry {
+			return statusCode, body, err
+		}
+
+		wait := policy.backoff(attempt)
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			// out of retry budget, report the last outcome
+			return statusCode, body, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, body, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package unifi
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{retry: 1, want: 100 * time.Millisecond},
+		{retry: 2, want: 200 * time.Millisecond},
+		{retry: 3, want: 400 * time.Millisecond},
+		{retry: 4, want: 800 * time.Millisecond},
+		{retry: 5, want: time.Second},
+		{retry: 50, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.retry, got, tt.want)
+		}
+	}
+
+	uncapped := RetryPolicy{MinBackoff: time.Millisecond}
+	if got := uncapped.backoff(4); got != 8*time.Millisecond {
+		t.Errorf("backoff without a cap = %s, want 8ms", got)
+	}
+	if got := uncapped.backoff(100); got <= 0 {
+		t.Errorf("backoff without a cap overflowed to %s", got)
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	tests := []struct {
+		jitter float64
+		min    time.Duration
+	}{
+		{jitter: 0.5, min: 200 * time.Millisecond},
+		{jitter: 1, min: 0},
+		{jitter: 3, min: 0}, // clamped to 1
+	}
+	for _, tt := range tests {
+		policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: 400 * time.Millisecond, Jitter: tt.jitter}
+		for i := 0; i < 100; i++ {
+			if got := policy.backoff(3); got < tt.min || got > 400*time.Millisecond {
+				t.Fatalf("backoff(3) with jitter %v = %s, want between %s and 400ms", tt.jitter, got, tt.min)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyAllowsMethod(t *testing.T) {
+	tests := []struct {
+		method   string
+		mutating bool
+		want     bool
+	}{
+		{method: http.MethodGet, want: true},
+		{method: http.MethodHead, want: true},
+		{method: http.MethodOptions, want: true},
+		{method: http.MethodPost},
+		{method: http.MethodPut},
+		{method: http.MethodDelete},
+		{method: http.MethodPost, mutating: true, want: true},
+		{method: http.MethodDelete, mutating: true, want: true},
+	}
+	for _, tt := range tests {
+		policy := RetryPolicy{RetryMutatingMethods: tt.mutating}
+		if got := policy.allowsMethod(tt.method); got != tt.want {
+			t.Errorf("allowsMethod(%s) with mutating %v = %v, want %v", tt.method, tt.mutating, got, tt.want)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil"},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, want: true},
+		{name: "connection refused", err: errors.Wrap(syscall.ECONNREFUSED, "dial"), want: true},
+		{name: "broken pipe", err: syscall.EPIPE, want: true},
+		{name: "eof", err: io.EOF, want: true},
+		{name: "unexpected eof", err: errors.Wrap(io.ErrUnexpectedEOF, "read body"), want: true},
+		{name: "timeout", err: &net.OpError{Op: "dial", Err: timeoutError{}}, want: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline", err: errors.Wrap(context.DeadlineExceeded, "request"), want: false},
+		{name: "other", err: errors.New("tls: bad certificate")},
+	}
+	for _, tt := range tests {
+		if got := IsRetryableError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	custom := RetryPolicy{RetryableError: func(err error) bool { return err == io.ErrClosedPipe }}
+	if !custom.retryableError(io.ErrClosedPipe) || custom.retryableError(io.EOF) {
+		t.Error("the RetryableError func of the policy must replace IsRetryableError")
+	}
+}
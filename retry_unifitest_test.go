@@ -0,0 +1,177 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	policy := unifi.RetryPolicy{
+		MaxAttempts:          3,
+		MinBackoff:           time.Millisecond,
+		MaxBackoff:           2 * time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+	}
+	mutating := policy
+	mutating.RetryMutatingMethods = true
+
+	health := func(ctx context.Context, client *unifi.Client) error {
+		_, err := client.SiteHealth(ctx, "default")
+		return err
+	}
+	createGroup := func(ctx context.Context, client *unifi.Client) error {
+		_, err := client.CreateUserGroup(ctx, "default", "", "slow", 1000, 1000)
+		return err
+	}
+	tests := []struct {
+		name     string
+		policy   unifi.RetryPolicy
+		fault    unifitest.Fault
+		call     func(ctx context.Context, client *unifi.Client) error
+		attempts int
+		wantErr  error
+	}{
+		{
+			name:     "recovers within the attempts",
+			policy:   policy,
+			fault:    unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusBadGateway, Times: 2},
+			call:     health,
+			attempts: 3,
+		},
+		{
+			name:     "gives up after the attempts",
+			policy:   policy,
+			fault:    unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusServiceUnavailable, Times: 5},
+			call:     health,
+			attempts: 3,
+			wantErr:  unifi.ErrControllerUnavailable,
+		},
+		{
+			name:     "status not retryable",
+			policy:   policy,
+			fault:    unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusGatewayTimeout, Times: 1},
+			call:     health,
+			attempts: 1,
+			wantErr:  unifi.ErrControllerUnavailable,
+		},
+		{
+			name:     "client error not retried",
+			policy:   policy,
+			fault:    unifitest.Fault{Path: "/api/s/*/stat/health", Message: "api.err.NoPermission", Times: 1},
+			call:     health,
+			attempts: 1,
+			wantErr:  unifi.ErrNoPermission,
+		},
+		{
+			name:     "post not retried by default",
+			policy:   policy,
+			fault:    unifitest.Fault{Path: "/api/s/*/rest/usergroup", StatusCode: http.StatusBadGateway, Times: 1},
+			call:     createGroup,
+			attempts: 1,
+			wantErr:  unifi.ErrControllerUnavailable,
+		},
+		{
+			name:     "post retried when mutating methods opt in",
+			policy:   mutating,
+			fault:    unifitest.Fault{Path: "/api/s/*/rest/usergroup", StatusCode: http.StatusBadGateway, Times: 1},
+			call:     createGroup,
+			attempts: 2,
+		},
+		{
+			name:     "no retries",
+			policy:   unifi.NoRetryPolicy(),
+			fault:    unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusBadGateway, Times: 1},
+			call:     health,
+			attempts: 1,
+			wantErr:  unifi.ErrControllerUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+
+			ctx := context.Background()
+			client, err := srv.Client(ctx, unifi.WithRetryPolicy(tt.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv.InjectFault(tt.fault)
+			before := len(srv.Requests())
+
+			err = tt.call(ctx, client)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if attempts := len(srv.Requests()) - before; attempts != tt.attempts {
+				t.Fatalf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryTimeoutBoundsAttempts(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx, unifi.WithRetryPolicy(unifi.RetryPolicy{
+		MaxAttempts:          100,
+		MinBackoff:           40 * time.Millisecond,
+		MaxBackoff:           40 * time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusBadGateway},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.RetryTimeout = 100 * time.Millisecond
+	srv.InjectFault(unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusBadGateway})
+	before := len(srv.Requests())
+
+	start := time.Now()
+	_, err = client.SiteHealth(ctx, "default")
+	elapsed := time.Since(start)
+	if !errors.Is(err, unifi.ErrControllerUnavailable) {
+		t.Fatalf("expected the last failure to be reported, got %v", err)
+	}
+	// attempts at 0, 40 and 80ms, the next backoff would end past the 100ms budget
+	if attempts := len(srv.Requests()) - before; attempts != 3 {
+		t.Fatalf("expected 3 attempts within the retry timeout, got %d", attempts)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("the retry timeout did not bound the retries, took %s", elapsed)
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	client, err := srv.Client(context.Background(), unifi.WithRetryPolicy(unifi.RetryPolicy{
+		MaxAttempts:          5,
+		MinBackoff:           time.Hour,
+		MaxBackoff:           time.Hour,
+		RetryableStatusCodes: []int{http.StatusBadGateway},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.RetryTimeout = 0
+	srv.InjectFault(unifitest.Fault{Path: "/api/s/*/stat/health", StatusCode: http.StatusBadGateway})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.SiteHealth(ctx, "default")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the backoff to end with the context, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package unifi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSessionExpired indicates an imported session is past its expiry, log in again instead
+var ErrSessionExpired = fmt.Errorf("session expired")
+
+// SessionCookie is a cookie of an exported session
+type SessionCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SessionState is the exportable state of an authenticated session.
+// It holds the session secrets, store it with the same care as the credentials.
+type SessionState struct {
+	BaseURL   string           `json:"base_url"`
+	Flavor    ControllerFlavor `json:"flavor,omitempty"`
+	Cookies   []SessionCookie  `json:"cookies"`
+	CSRFToken string           `json:"csrf_token,omitempty"`
+	Expires   time.Time        `json:"expires"` // zero when the controller did not send an expiry
+	Remember  bool             `json:"remember"`
+}
+
+// Expired returns true if the session is known to be expired
+func (s *SessionState) Expired() bool {
+	return !s.Expires.IsZero() && !time.Now().Before(s.Expires)
+}
+
+// ExportSession returns the current session so it can be restored with ImportSession, for example by a later process.
+// Only sessions created with remember=true outlive the controller's short default expiry.
+func (c *Client) ExportSession() (*SessionState, error) {
+	// read before taking mu, flavorMu is always acquired first
+	flavor := c.ControllerFlavor()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cookies := c.cookies.Cookies(c.baseURL)
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf("no session to export, login first")
+	}
+	state := &SessionState{
+		BaseURL:   c.baseURLStr,
+		Flavor:    flavor,
+		CSRFToken: c.csrfToken,
+		Expires:   c.sessionExpires,
+		Remember:  c.longRunningSession,
+	}
+	for _, cookie := range cookies {
+		state.Cookies = append(state.Cookies, SessionCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return state, nil
+}
+
+// ImportSession restores a session exported with ExportSession, replacing the current session.
+// The controller may still reject the session, set a CredentialProvider to log in again transparently when it does.
+// state - the exported session, it must belong to the controller of the client
+func (c *Client) ImportSession(state *SessionState) error {
+	if state.Expired() {
+		return ErrSessionExpired
+	}
+	u, err := url.Parse(state.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid session base url: %v", err)
+	}
+
+	c.mu.Lock()
+	if u.Scheme != c.baseURL.Scheme || u.Host != c.baseURL.Host {
+		c.mu.Unlock()
+		return fmt.Errorf("session belongs to controller %s, not %s", state.BaseURL, c.baseURLStr)
+	}
+	cookies := make([]*http.Cookie, 0, len(state.Cookies))
+	for _, cookie := range state.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/", Expires: state.Expires})
+	}
+	c.cookies.SetCookies(c.baseURL, cookies)
+	c.csrfToken = state.CSRFToken
+	c.sessionExpires = state.Expires
+	c.longRunningSession = state.Remember
+	c.mu.Unlock()
+
+	if state.Flavor.IsValid() && state.Flavor != ControllerFlavorUnknown {
+		c.SetControllerFlavor(state.Flavor)
+	}
+	atomic.AddUint64(&c.sessionGeneration, 1)
+	return nil
+}
+
+// cookiesExpiry returns the earliest expiry of the cookies, zero if none expires
+func cookiesExpiry(cookies []*http.Cookie) time.Time {
+	var expires time.Time
+	for _, cookie := range cookies {
+		var t time.Time
+		switch {
+		case cookie.MaxAge > 0:
+			t = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		case cookie.MaxAge == 0 && !cookie.Expires.IsZero():
+			t = cookie.Expires
+		default:
+			continue
+		}
+		if expires.IsZero() || t.Before(expires) {
+			expires = t
+		}
+	}
+	return expires
+}
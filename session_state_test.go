@@ -0,0 +1,156 @@
+package unifi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// TestExportSessionConcurrentFlavorDetection exports the session while the UniFi OS flavor detection stores it,
+// taking the client locks in opposite orders deadlocks.
+func TestExportSessionConcurrentFlavorDetection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// UniFi OS answers the root page and hands out a session
+		http.SetCookie(w, &http.Cookie{Name: "TOKEN", Value: "abc", Path: "/"})
+		w.Header().Set("X-CSRF-Token", "def")
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := unifi.NewClient(srv.URL, nil, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DetectControllerFlavor(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if _, err := client.ExportSession(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if _, err := client.DetectControllerFlavor(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(20 * time.Second):
+		t.Fatal("ExportSession and the flavor detection deadlocked")
+	}
+	state, err := client.ExportSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Flavor != unifi.ControllerFlavorUniFiOS || state.CSRFToken != "def" {
+		t.Fatalf("unexpected session %+v", state)
+	}
+}
+
+func TestExportImportSessionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []unifitest.ServerOption
+	}{
+		{name: "classic"},
+		{name: "unifi os", opts: []unifitest.ServerOption{unifitest.WithUniFiOS()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer(tt.opts...)
+			defer srv.Close()
+
+			ctx := context.Background()
+			first, err := srv.Client(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state, err := first.ExportSession()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := unifi.NewClient(srv.URL, nil, 5*time.Second, unifi.WithRetryPolicy(unifi.NoRetryPolicy()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := second.ImportSession(state); err != nil {
+				t.Fatal(err)
+			}
+			if flavor := second.ControllerFlavor(); flavor != first.ControllerFlavor() {
+				t.Fatalf("expected flavor %q, got %q", first.ControllerFlavor(), flavor)
+			}
+
+			// a write needs the CSRF token on UniFi OS, and no request may log in again
+			sent := len(srv.Requests())
+			if _, err := second.CreateUserGroup(ctx, "default", "", "imported", -1, -1); err != nil {
+				t.Fatal(err)
+			}
+			requests := srv.Requests()[sent:]
+			if len(requests) != 1 {
+				t.Fatalf("expected a single request with the imported session, got %+v", requests)
+			}
+			if requests[0].Header.Get("Cookie") == "" {
+				t.Fatalf("%s %s was sent without the imported session", requests[0].Method, requests[0].Path)
+			}
+		})
+	}
+}
+
+func TestImportSessionRejected(t *testing.T) {
+	client, err := unifi.NewClient("https://controller.example.com:8443", nil, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ExportSession(); err == nil {
+		t.Fatal("expected export without a session to fail")
+	}
+
+	tests := []struct {
+		name  string
+		state unifi.SessionState
+		want  error
+	}{
+		{
+			name:  "expired",
+			state: unifi.SessionState{BaseURL: "https://controller.example.com:8443", Expires: time.Now().Add(-time.Minute)},
+			want:  unifi.ErrSessionExpired,
+		},
+		{
+			name:  "other controller",
+			state: unifi.SessionState{BaseURL: "https://other.example.com:8443", Expires: time.Now().Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ImportSession(&tt.state)
+			if err == nil || (tt.want != nil && err != tt.want) {
+				t.Fatalf("expected the session to be rejected with %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -70,7 +71,7 @@ type SiteAlarmsResponse struct {
 // limit - limit the max amount of events returned, defaults to 3000 if zero-value
 // order - defined the sort order of the alarm events
 // archived - query archived (when true) or unarchived (default) alarm events
-func (c *Client) SiteAlarms(site string, historyHours int, offset int, limit int, order EventSortOrder, archived bool) (*SiteAlarmsResponse, error) {
+func (c *Client) SiteAlarms(ctx context.Context, site string, historyHours int, offset int, limit int, order EventSortOrder, archived bool, opts ...RequestOption) (*SiteAlarmsResponse, error) {
 	if historyHours <= 0 {
 		historyHours = 24
 	}
@@ -95,7 +96,7 @@ func (c *Client) SiteAlarms(site string, historyHours int, offset int, limit int
 	data, _ := json.Marshal(&payload)
 
 	var resp SiteAlarmsResponse
-	err := c.doSiteRequest(http.MethodGet, site, fmt.Sprintf("stat/alarm?archived=%t", archived), bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/alarm", bytes.NewReader(data), &resp, append([]RequestOption{Archived(archived)}, opts...)...)
 	return &resp, err
 }
 
@@ -109,7 +110,7 @@ type SiteAlarmsCountResponse struct {
 // site - site to query
 // historyHours - number of hours of history to return, defaults to 24 hours
 // archived - query archived (when true) or unarchived (default) alarm events
-func (c *Client) SiteAlarmsCount(site string, historyHours int, archived bool) (*SiteAlarmsCountResponse, error) {
+func (c *Client) SiteAlarmsCount(ctx context.Context, site string, historyHours int, archived bool, opts ...RequestOption) (*SiteAlarmsCountResponse, error) {
 	if historyHours <= 0 {
 		historyHours = 720
 	}
@@ -121,6 +122,6 @@ func (c *Client) SiteAlarmsCount(site string, historyHours int, archived bool) (
 	data, _ := json.Marshal(&payload)
 
 	var resp SiteAlarmsCountResponse
-	err := c.doSiteRequest(http.MethodGet, site, fmt.Sprintf("stat/alarm/cnt/?archived=%t", archived), bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/alarm/cnt", bytes.NewReader(data), &resp, append([]RequestOption{Archived(archived)}, opts...)...)
 	return &resp, err
 }
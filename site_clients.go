@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -76,27 +77,30 @@ type SiteActiveClientsResponse struct {
 // SiteActiveClients will list active clients
 // site - the site to query
 // filterMac - filter to a specific mac, if zero-value, then no filter is applied
-func (c *Client) SiteActiveClients(site string, filterMac string) (*SiteActiveClientsResponse, error) {
+func (c *Client) SiteActiveClients(ctx context.Context, site string, filterMac string, opts ...RequestOption) (*SiteActiveClientsResponse, error) {
+	if c.usesAPIKey() {
+		return c.integrationSiteActiveClients(ctx, site, filterMac)
+	}
 	extPath := "stat/sta"
 	if filterMac != "" {
 		extPath = extPath + "/" + strings.ToLower(filterMac)
 	}
 
 	var resp SiteActiveClientsResponse
-	err := c.doSiteRequest(http.MethodGet, site, extPath, nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, extPath, nil, &resp, opts...)
 	return &resp, err
 }
 
 // ClientDetails gets the details for a single client
 // site - the site to query
 // mac - the client mac to query
-func (c *Client) ClientDetails(site string, mac string) (*GenericResponse, error) {
+func (c *Client) ClientDetails(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	if mac == "" {
 		return nil, fmt.Errorf("must specify a client MAC")
 	}
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, fmt.Sprintf("stat/user/%s", strings.ToLower(mac)), nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, fmt.Sprintf("stat/user/%s", strings.ToLower(mac)), nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -106,7 +110,7 @@ func (c *Client) ClientDetails(site string, mac string) (*GenericResponse, error
 // useFixedIP - true to set a fixedIP, false to unset
 // networkID - if useFixedIP set this to the specified value
 // fixedIP - if userFixedIP set this to the fixed IP specified
-func (c *Client) UpdateClientFixedIP(site string, clientID string, useFixedIP bool, networkID *string, fixedIP *string) (*GenericResponse, error) {
+func (c *Client) UpdateClientFixedIP(ctx context.Context, site string, clientID string, useFixedIP bool, networkID *string, fixedIP *string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"_id":         strings.TrimSpace(strings.ToLower(clientID)),
 		"use_fixedip": useFixedIP,
@@ -125,6 +129,6 @@ func (c *Client) UpdateClientFixedIP(site string, clientID string, useFixedIP bo
 	extPath := fmt.Sprintf("rest/user/%s", strings.TrimSpace(strings.ToLower(clientID)))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -8,13 +9,12 @@ import (
 // site - the site to query
 // scale5Min - if true will return stats based on 5 minute intervals, otherwise defaults to hourly stats.
 // note this only works on controllers >= 5.5.x
-func (c *Client) ListDashboardMetrics(site string, scale5Min bool) (*GenericResponse, error) {
-	var queryParams []string
+func (c *Client) ListDashboardMetrics(ctx context.Context, site string, scale5Min bool, opts ...RequestOption) (*GenericResponse, error) {
 	if scale5Min {
-		queryParams = []string{"scale", "5minutes"}
+		opts = append([]RequestOption{QueryParam("scale", "5minutes")}, opts...)
 	}
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/dashboard", nil, &resp, queryParams...)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/dashboard", nil, &resp, opts...)
 	return &resp, err
 }
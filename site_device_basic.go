@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -25,7 +26,10 @@ type SiteDeviceBasicResponse struct {
 // SiteDevicesBasic queries the basic device data
 // site - the site to query
 // typeFilter - the filter to query, if none, then it queries all devices
-func (c *Client) SiteDevicesBasic(site string, typeFilter string) (*SiteDeviceBasicResponse, error) {
+func (c *Client) SiteDevicesBasic(ctx context.Context, site string, typeFilter string, opts ...RequestOption) (*SiteDeviceBasicResponse, error) {
+	if c.usesAPIKey() {
+		return c.integrationSiteDevicesBasic(ctx, site, typeFilter)
+	}
 	var resp SiteDeviceBasicResponse
 	var sendBody io.Reader
 	if typeFilter != "" {
@@ -35,6 +39,6 @@ func (c *Client) SiteDevicesBasic(site string, typeFilter string) (*SiteDeviceBa
 		data, _ := json.Marshal(payload)
 		sendBody = bytes.NewReader(data)
 	}
-	err := c.doSiteRequest(http.MethodGet, site, "stat/device-basic", sendBody, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/device-basic", sendBody, &resp, opts...)
 	return &resp, err
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -17,6 +18,18 @@ type SuppressionContent struct {
 // note - not all fields are always provided
 type SiteDeviceDetailedData struct {
 	ID                          string             `json:"_id"`
+	MAC                         string             `json:"mac"`
+	Model                       string             `json:"model"`
+	Type                        string             `json:"type"`
+	Name                        string             `json:"name"`
+	Version                     string             `json:"version"`
+	Serial                      string             `json:"serial"`
+	Adopted                     bool               `json:"adopted"`
+	State                       int                `json:"state"`
+	IP                          string             `json:"ip"`
+	Uptime                      int64              `json:"uptime"`
+	LastSeen                    int64              `json:"last_seen"`
+	Upgradable                  bool               `json:"upgradable"`
 	Key                         string             `json:"key"`
 	NTPServer1                  string             `json:"ntp_server_1"`
 	NTPServer2                  string             `json:"ntp_server_2"`
@@ -81,7 +94,14 @@ type SiteDeviceDetailedResponse struct {
 // SiteDevicesDetailed queries for the detailed device data
 // site - the site to query
 // filterMACs - optional list of macs to get specific device data for
-func (c *Client) SiteDevicesDetailed(site string, filterMACs ...string) (*SiteDeviceDetailedResponse, error) {
+func (c *Client) SiteDevicesDetailed(ctx context.Context, site string, filterMACs ...string) (*SiteDeviceDetailedResponse, error) {
+	return c.SiteDevicesDetailedWithOptions(ctx, site, filterMACs)
+}
+
+// SiteDevicesDetailedWithOptions is SiteDevicesDetailed with request options
+// site - the site to query
+// filterMACs - optional list of macs to get specific device data for
+func (c *Client) SiteDevicesDetailedWithOptions(ctx context.Context, site string, filterMACs []string, opts ...RequestOption) (*SiteDeviceDetailedResponse, error) {
 	var resp SiteDeviceDetailedResponse
 	var sendBody io.Reader
 	method := http.MethodGet
@@ -94,6 +114,6 @@ func (c *Client) SiteDevicesDetailed(site string, filterMACs ...string) (*SiteDe
 		data, _ := json.Marshal(payload)
 		sendBody = bytes.NewReader(data)
 	}
-	err := c.doSiteRequest(method, site, "stat/device", sendBody, &resp)
+	err := c.doSiteRequest(ctx, method, site, "stat/device", sendBody, &resp, opts...)
 	return &resp, err
 }
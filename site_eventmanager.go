@@ -2,11 +2,12 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 )
 
 // ArchiveAllAlarms will archive all alarms
-func (c *Client) ArchiveAllAlarms(site string) error {
+func (c *Client) ArchiveAllAlarms(ctx context.Context, site string, opts ...RequestOption) error {
 	data := []byte(`{"cmd": "archive-all-alarms"}`)
-	return c.doSiteRequest(http.MethodPost, site, "cmd/evtmgt", bytes.NewReader(data), nil)
+	return c.doSiteRequest(ctx, http.MethodPost, site, "cmd/evtmgt", bytes.NewReader(data), nil, opts...)
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -61,7 +62,7 @@ type SiteEventsResponse struct {
 // offset - offset current search if previous request exceeded limit
 // limit - limit to number of events to return
 // order - how to order the ips/ids events.
-func (c *Client) SiteEvents(site string, historyHours int, offset int, limit int, order EventSortOrder) (*SiteEventsResponse, error) {
+func (c *Client) SiteEvents(ctx context.Context, site string, historyHours int, offset int, limit int, order EventSortOrder, opts ...RequestOption) (*SiteEventsResponse, error) {
 	if historyHours <= 0 {
 		historyHours = 720
 	}
@@ -86,7 +87,7 @@ func (c *Client) SiteEvents(site string, historyHours int, offset int, limit int
 	data, _ := json.Marshal(&payload)
 
 	var resp SiteEventsResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/event", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/event", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -97,7 +98,7 @@ func (c *Client) SiteEvents(site string, historyHours int, offset int, limit int
 // offset - offset current search if previous request exceeded limit
 // limit - limit to number of events to return
 // order - how to order the ips/ids events.
-func (c *Client) SiteIPSEvents(site string, startTime time.Time, endTime time.Time, offset int, limit int, order EventSortOrder) (*SiteEventsResponse, error) {
+func (c *Client) SiteIPSEvents(ctx context.Context, site string, startTime time.Time, endTime time.Time, offset int, limit int, order EventSortOrder, opts ...RequestOption) (*SiteEventsResponse, error) {
 	if startTime.IsZero() && endTime.IsZero() {
 		endTime = time.Now().UTC()
 		startTime = endTime.Add(-24 * time.Hour)
@@ -128,6 +129,6 @@ func (c *Client) SiteIPSEvents(site string, startTime time.Time, endTime time.Ti
 	data, _ := json.Marshal(&payload)
 
 	var resp SiteEventsResponse
-	err := c.doSiteRequest(http.MethodGet, site, "/stat/ips/event", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "/stat/ips/event", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -19,9 +20,9 @@ type SiteFirewallRuleResponse struct {
 
 // SiteFirewallRules queries the site firewall rules
 // site - the site to query
-func (c *Client) SiteFirewallRules(site string) (*SiteFirewallRuleResponse, error) {
+func (c *Client) SiteFirewallRules(ctx context.Context, site string, opts ...RequestOption) (*SiteFirewallRuleResponse, error) {
 	var resp SiteFirewallRuleResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/firewallrule", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/firewallrule", nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -37,14 +38,14 @@ type SiteFirewallGroupResponse struct {
 // SiteFirewallGroups will list firewall groups
 // site - the site to query
 // groupID - filter on the associated group, if zero-value it returns all for the entire site.
-func (c *Client) SiteFirewallGroups(site string, groupID string) (*SiteFirewallGroupResponse, error) {
+func (c *Client) SiteFirewallGroups(ctx context.Context, site string, groupID string, opts ...RequestOption) (*SiteFirewallGroupResponse, error) {
 	extPath := "rest/firewallgroup"
 	if groupID != "" {
 		extPath = extPath + "/" + strings.TrimSpace(groupID)
 	}
 
 	var resp SiteFirewallGroupResponse
-	err := c.doSiteRequest(http.MethodGet, site, extPath, nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, extPath, nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -81,7 +82,7 @@ type FirewallGroupMembers struct {
 // name - the name of the firewall group
 // groupType - the type of firewall group
 // groupMembers - the firewall group member configuration
-func (c *Client) CreateFirewallGroup(site string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers) (*GenericResponse, error) {
+func (c *Client) CreateFirewallGroup(ctx context.Context, site string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers, opts ...RequestOption) (*GenericResponse, error) {
 	if !groupType.IsValid() {
 		return nil, fmt.Errorf("invalid groupType specified: %s", groupType)
 	}
@@ -111,7 +112,7 @@ func (c *Client) CreateFirewallGroup(site string, name string, groupType Firewal
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "rest/firewallgroup", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "rest/firewallgroup", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -122,7 +123,7 @@ func (c *Client) CreateFirewallGroup(site string, name string, groupType Firewal
 // name - the name of the firewall group
 // groupType - the type of firewall group, note you cannot change a group type
 // groupMembers - the firewall group member configuration
-func (c *Client) UpdateFirewallGroup(site string, siteID string, groupID string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers) (*GenericResponse, error) {
+func (c *Client) UpdateFirewallGroup(ctx context.Context, site string, siteID string, groupID string, name string, groupType FirewallGroupType, groupMembers FirewallGroupMembers, opts ...RequestOption) (*GenericResponse, error) {
 	if !groupType.IsValid() {
 		return nil, fmt.Errorf("invalid groupType specified: %s", groupType)
 	}
@@ -156,7 +157,7 @@ func (c *Client) UpdateFirewallGroup(site string, siteID string, groupID string,
 	extPath := fmt.Sprintf("rest/firewallgroup/%s", strings.TrimSpace(groupID))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -164,10 +165,10 @@ func (c *Client) UpdateFirewallGroup(site string, siteID string, groupID string,
 // site - the site to modify
 // siteID - the ID of the site
 // groupID - the ID of the firewall group
-func (c *Client) DeleteFirewallGroup(site string, groupID string) (*GenericResponse, error) {
+func (c *Client) DeleteFirewallGroup(ctx context.Context, site string, groupID string, opts ...RequestOption) (*GenericResponse, error) {
 	extPath := fmt.Sprintf("rest/firewallgroup/%s", strings.TrimSpace(groupID))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodDelete, site, extPath, nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, extPath, nil, &resp, opts...)
 	return &resp, err
 }
@@ -0,0 +1,376 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FirewallRuleset is the chain a firewall rule belongs to
+type FirewallRuleset string
+
+// The firewall rulesets, IN filters traffic entering the router from the network, OUT traffic leaving towards it
+// and LOCAL traffic destined to the router itself
+const (
+	FirewallRulesetWANIn        FirewallRuleset = "WAN_IN"
+	FirewallRulesetWANOut       FirewallRuleset = "WAN_OUT"
+	FirewallRulesetWANLocal     FirewallRuleset = "WAN_LOCAL"
+	FirewallRulesetLANIn        FirewallRuleset = "LAN_IN"
+	FirewallRulesetLANOut       FirewallRuleset = "LAN_OUT"
+	FirewallRulesetLANLocal     FirewallRuleset = "LAN_LOCAL"
+	FirewallRulesetGuestIn      FirewallRuleset = "GUEST_IN"
+	FirewallRulesetGuestOut     FirewallRuleset = "GUEST_OUT"
+	FirewallRulesetGuestLocal   FirewallRuleset = "GUEST_LOCAL"
+	FirewallRulesetWANv6In      FirewallRuleset = "WANv6_IN"
+	FirewallRulesetWANv6Out     FirewallRuleset = "WANv6_OUT"
+	FirewallRulesetWANv6Local   FirewallRuleset = "WANv6_LOCAL"
+	FirewallRulesetLANv6In      FirewallRuleset = "LANv6_IN"
+	FirewallRulesetLANv6Out     FirewallRuleset = "LANv6_OUT"
+	FirewallRulesetLANv6Local   FirewallRuleset = "LANv6_LOCAL"
+	FirewallRulesetGuestv6In    FirewallRuleset = "GUESTv6_IN"
+	FirewallRulesetGuestv6Out   FirewallRuleset = "GUESTv6_OUT"
+	FirewallRulesetGuestv6Local FirewallRuleset = "GUESTv6_LOCAL"
+)
+
+// IsValid returns true if it's a valid firewall ruleset.
+func (r FirewallRuleset) IsValid() bool {
+	switch r {
+	case FirewallRulesetWANIn, FirewallRulesetWANOut, FirewallRulesetWANLocal,
+		FirewallRulesetLANIn, FirewallRulesetLANOut, FirewallRulesetLANLocal,
+		FirewallRulesetGuestIn, FirewallRulesetGuestOut, FirewallRulesetGuestLocal,
+		FirewallRulesetWANv6In, FirewallRulesetWANv6Out, FirewallRulesetWANv6Local,
+		FirewallRulesetLANv6In, FirewallRulesetLANv6Out, FirewallRulesetLANv6Local,
+		FirewallRulesetGuestv6In, FirewallRulesetGuestv6Out, FirewallRulesetGuestv6Local:
+		return true
+	default:
+		return false
+	}
+}
+
+// FirewallRuleAction is what happens to the traffic matched by a rule
+type FirewallRuleAction string
+
+// The firewall rule actions
+const (
+	FirewallRuleActionAccept FirewallRuleAction = "accept"
+	FirewallRuleActionDrop   FirewallRuleAction = "drop"
+	FirewallRuleActionReject FirewallRuleAction = "reject"
+)
+
+// IsValid returns true if it's a valid firewall rule action.
+func (a FirewallRuleAction) IsValid() bool {
+	switch a {
+	case FirewallRuleActionAccept, FirewallRuleActionDrop, FirewallRuleActionReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// The network types used to match the source or destination of a rule by network
+const (
+	FirewallNetworkConfTypeAddress = "ADDRv4" // the gateway address of the network
+	FirewallNetworkConfTypeNetwork = "NETv4"  // the whole subnet of the network
+)
+
+// FirewallRule is a typed firewall rule of rest/firewallrule
+type FirewallRule struct {
+	ID        string             `json:"_id,omitempty"`
+	SiteID    string             `json:"site_id,omitempty"`
+	Name      string             `json:"name"`
+	Enabled   bool               `json:"enabled"`
+	Ruleset   FirewallRuleset    `json:"ruleset"`
+	RuleIndex int                `json:"rule_index"` // evaluation order within the ruleset, user rules start at 2000
+	Action    FirewallRuleAction `json:"action"`
+	Logging   bool               `json:"logging"`
+
+	Protocol              string `json:"protocol"`                // all, tcp, udp, tcp_udp, icmp or a protocol name or number
+	ProtocolMatchExcepted bool   `json:"protocol_match_excepted"` // match every protocol but Protocol
+	ICMPTypename          string `json:"icmp_typename,omitempty"`
+
+	StateEstablished bool   `json:"state_established"`
+	StateInvalid     bool   `json:"state_invalid"`
+	StateNew         bool   `json:"state_new"`
+	StateRelated     bool   `json:"state_related"`
+	IPSec            string `json:"ipsec"` // empty, match-ipsec or match-none
+
+	SrcFirewallGroupIDs []string `json:"src_firewallgroup_ids"`
+	SrcMACAddress       string   `json:"src_mac_address"`
+	SrcAddress          string   `json:"src_address"`
+	SrcNetworkConfID    string   `json:"src_networkconf_id"`
+	SrcNetworkConfType  string   `json:"src_networkconf_type"`
+	SrcPort             string   `json:"src_port,omitempty"`
+
+	DstFirewallGroupIDs []string `json:"dst_firewallgroup_ids"`
+	DstAddress          string   `json:"dst_address"`
+	DstNetworkConfID    string   `json:"dst_networkconf_id"`
+	DstNetworkConfType  string   `json:"dst_networkconf_type"`
+	DstPort             string   `json:"dst_port,omitempty"`
+}
+
+// Validate checks the firewall rule before it is sent to the controller, which rejects it without a helpful message
+func (r *FirewallRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("firewall rule name is required")
+	}
+	if !r.Ruleset.IsValid() {
+		return fmt.Errorf("invalid firewall ruleset specified: %s", r.Ruleset)
+	}
+	if !r.Action.IsValid() {
+		return fmt.Errorf("invalid firewall rule action specified: %s", r.Action)
+	}
+	return nil
+}
+
+// payload returns the rule as sent to the controller, which expects empty lists instead of null
+func (r FirewallRule) payload() ([]byte, error) {
+	if r.SrcFirewallGroupIDs == nil {
+		r.SrcFirewallGroupIDs = []string{}
+	}
+	if r.DstFirewallGroupIDs == nil {
+		r.DstFirewallGroupIDs = []string{}
+	}
+	if r.Protocol == "" {
+		r.Protocol = "all"
+	}
+	return json.Marshal(r)
+}
+
+// FirewallRuleResponse contains the typed firewall rules response
+type FirewallRuleResponse struct {
+	Meta CommonMeta     `json:"meta"`
+	Data []FirewallRule `json:"data"`
+}
+
+// ListFirewallRules lists the firewall rules of the site, sorted by ruleset and rule index
+// site - the site to query
+func (c *Client) ListFirewallRules(ctx context.Context, site string, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	var resp FirewallRuleResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/firewallrule", nil, &resp, opts...)
+	if err != nil {
+		return &resp, err
+	}
+	sort.SliceStable(resp.Data, func(i, j int) bool {
+		if resp.Data[i].Ruleset != resp.Data[j].Ruleset {
+			return resp.Data[i].Ruleset < resp.Data[j].Ruleset
+		}
+		return resp.Data[i].RuleIndex < resp.Data[j].RuleIndex
+	})
+	return &resp, nil
+}
+
+// GetFirewallRule gets a single firewall rule
+// site - the site to query
+// ruleID - the ID of the firewall rule
+func (c *Client) GetFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRule, error) {
+	var resp FirewallRuleResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/firewallrule/"+strings.TrimSpace(ruleID), nil, &resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "firewall rule %s", ruleID)
+	}
+	return &resp.Data[0], nil
+}
+
+// CreateFirewallRule creates a new firewall rule, the ID of the rule is ignored
+// site - the site to modify
+// rule - the firewall rule
+func (c *Client) CreateFirewallRule(ctx context.Context, site string, rule FirewallRule, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	rule.ID = ""
+	data, err := rule.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp FirewallRuleResponse
+	err = c.doSiteRequest(ctx, http.MethodPost, site, "rest/firewallrule", bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// UpdateFirewallRule replaces an existing firewall rule
+// site - the site to modify
+// rule - the firewall rule, its ID selects the rule to update
+func (c *Client) UpdateFirewallRule(ctx context.Context, site string, rule FirewallRule, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	if rule.ID == "" {
+		return nil, fmt.Errorf("firewall rule ID is required")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := rule.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp FirewallRuleResponse
+	err = c.doSiteRequest(ctx, http.MethodPut, site, "rest/firewallrule/"+strings.TrimSpace(rule.ID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// DeleteFirewallRule deletes an existing firewall rule
+// site - the site to modify
+// ruleID - the ID of the firewall rule
+func (c *Client) DeleteFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*GenericResponse, error) {
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, "rest/firewallrule/"+strings.TrimSpace(ruleID), nil, &resp, opts...)
+	return &resp, err
+}
+
+// EnableFirewallRule enables a firewall rule
+// site - the site to modify
+// ruleID - the ID of the firewall rule
+func (c *Client) EnableFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	return c.updateFirewallRuleFields(ctx, site, ruleID, map[string]interface{}{"enabled": true}, opts...)
+}
+
+// DisableFirewallRule disables a firewall rule without deleting it
+// site - the site to modify
+// ruleID - the ID of the firewall rule
+func (c *Client) DisableFirewallRule(ctx context.Context, site string, ruleID string, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	return c.updateFirewallRuleFields(ctx, site, ruleID, map[string]interface{}{"enabled": false}, opts...)
+}
+
+// updateFirewallRuleFields updates only the given fields of a rule
+func (c *Client) updateFirewallRuleFields(ctx context.Context, site string, ruleID string, fields map[string]interface{}, opts ...RequestOption) (*FirewallRuleResponse, error) {
+	data, _ := json.Marshal(fields)
+
+	var resp FirewallRuleResponse
+	err := c.doSiteRequest(ctx, http.MethodPut, site, "rest/firewallrule/"+strings.TrimSpace(ruleID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// ReorderFirewallRules reorders the rules of a ruleset on a best effort basis, the first rule of ruleIDs is
+// evaluated first.
+// The reorder is NOT atomic: the controller has no call to reorder a ruleset, so it takes two updates per moved
+// rule and the ruleset is partially reordered in between, traffic may be matched by the rules in an intermediate
+// order and concurrent changes to the ruleset are not detected.
+// ruleIDs must list every rule of the ruleset exactly once, so a stale view of the rules is rejected.
+// The rules keep the rule indexes the ruleset already uses, they are moved through unused indexes since the
+// controller rejects duplicate indexes.
+// If an update fails the rules moved so far are restored, even when ctx is canceled, and restore failures
+// are returned with the cause, the rules listed there must then be fixed by hand.
+// site - the site to modify
+// ruleset - the ruleset to reorder
+// ruleIDs - the IDs of the rules of the ruleset in their new order
+// opts - applied to every request of the reorder, including the listing and the restore
+func (c *Client) ReorderFirewallRules(ctx context.Context, site string, ruleset FirewallRuleset, ruleIDs []string, opts ...RequestOption) error {
+	if !ruleset.IsValid() {
+		return fmt.Errorf("invalid firewall ruleset specified: %s", ruleset)
+	}
+	resp, err := c.ListFirewallRules(ctx, site, opts...)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]int)
+	indexes := make([]int, 0)
+	maxIndex := 0
+	for _, rule := range resp.Data {
+		if rule.Ruleset != ruleset {
+			continue
+		}
+		current[rule.ID] = rule.RuleIndex
+		indexes = append(indexes, rule.RuleIndex)
+		if rule.RuleIndex > maxIndex {
+			maxIndex = rule.RuleIndex
+		}
+	}
+	if len(ruleIDs) != len(current) {
+		return fmt.Errorf("ruleset %s has %d rules, %d given", ruleset, len(current), len(ruleIDs))
+	}
+	seen := make(map[string]bool, len(ruleIDs))
+	for _, id := range ruleIDs {
+		if _, ok := current[id]; !ok || seen[id] {
+			return fmt.Errorf("rule %s is not part of ruleset %s or listed twice", id, ruleset)
+		}
+		seen[id] = true
+	}
+	sort.Ints(indexes)
+
+	// only the rules that change position are touched
+	var moves []string
+	target := make(map[string]int)
+	for i, id := range ruleIDs {
+		if current[id] != indexes[i] {
+			moves = append(moves, id)
+			target[id] = indexes[i]
+		}
+	}
+
+	var done []string
+	restore := func(cause error) error {
+		// the rules must be restored even if the failure is ctx being canceled
+		restoreCtx, cancel := context.WithTimeout(detachedContext{ctx}, firewallRestoreTimeout)
+		defer cancel()
+
+		// best effort, move the rules back through the unused indexes to their original ones
+		var failures []string
+		for i, id := range done {
+			_, err := c.updateFirewallRuleFields(restoreCtx, site, id, map[string]interface{}{"rule_index": maxIndex + 1 + i}, opts...)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("rule %s: %v", id, err))
+			}
+		}
+		for _, id := range done {
+			_, err := c.updateFirewallRuleFields(restoreCtx, site, id, map[string]interface{}{"rule_index": current[id]}, opts...)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("rule %s to index %d: %v", id, current[id], err))
+			}
+		}
+		if len(failures) > 0 {
+			return errors.Wrapf(cause, "unable to reorder ruleset %s, restoring the moved rules failed (%s)", ruleset, strings.Join(failures, "; "))
+		}
+		return errors.Wrapf(cause, "unable to reorder ruleset %s", ruleset)
+	}
+	for i, id := range moves {
+		_, err = c.updateFirewallRuleFields(ctx, site, id, map[string]interface{}{"rule_index": maxIndex + 1 + i}, opts...)
+		if err != nil {
+			return restore(err)
+		}
+		done = append(done, id)
+	}
+	for _, id := range moves {
+		_, err = c.updateFirewallRuleFields(ctx, site, id, map[string]interface{}{"rule_index": target[id]}, opts...)
+		if err != nil {
+			return restore(err)
+		}
+	}
+	return nil
+}
+
+// firewallRestoreTimeout bounds restoring the rules after a failed reorder
+const firewallRestoreTimeout = 30 * time.Second
+
+// detachedContext keeps the values of its parent but is never canceled. It is a copy of context.WithoutCancel,
+// which is only available since Go 1.21 while go.mod declares go 1.14.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
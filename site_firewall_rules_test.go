@@ -0,0 +1,136 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+var errUpdateFailed = errors.New("update failed")
+
+func TestFirewallRuleValidate(t *testing.T) {
+	valid := unifi.FirewallRule{Name: "block", Ruleset: unifi.FirewallRulesetWANIn, Action: unifi.FirewallRuleActionDrop}
+	tests := []struct {
+		name    string
+		modify  func(r *unifi.FirewallRule)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(r *unifi.FirewallRule) {}},
+		{name: "blank name", modify: func(r *unifi.FirewallRule) { r.Name = " " }, wantErr: true},
+		{name: "invalid ruleset", modify: func(r *unifi.FirewallRule) { r.Ruleset = "WAN" }, wantErr: true},
+		{name: "invalid action", modify: func(r *unifi.FirewallRule) { r.Action = "allow" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid
+			tt.modify(&rule)
+			if err := rule.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReorderFirewallRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		failAt      int  // the rule update that fails, counted from 1, 0 never fails
+		cancel      bool // cancel the context at failAt instead of failing the update
+		failRestore bool // fail every update after failAt as well
+		want        map[string]int
+		wantErr     string
+	}{
+		{
+			name: "reordered",
+			want: map[string]int{"r3": 2000, "r1": 2001, "r2": 2002},
+		},
+		{
+			name:    "restored after a failed update",
+			failAt:  5,
+			want:    map[string]int{"r1": 2000, "r2": 2001, "r3": 2002},
+			wantErr: "unable to reorder ruleset WAN_IN",
+		},
+		{
+			name:    "restored after the context is canceled",
+			failAt:  5,
+			cancel:  true,
+			want:    map[string]int{"r1": 2000, "r2": 2001, "r3": 2002},
+			wantErr: "context canceled",
+		},
+		{
+			name:        "restore failures are reported",
+			failAt:      5,
+			failRestore: true,
+			wantErr:     "rule r3 to index 2002: update failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+			for i, id := range []string{"r1", "r2", "r3"} {
+				srv.Add("default", "firewallrule", unifitest.Object{"_id": id, "name": id, "ruleset": "WAN_IN", "rule_index": 2000 + i, "action": "drop", "enabled": true})
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			updates := 0
+			client, err := srv.Client(ctx, unifi.WithInterceptors(func(ctx context.Context, req *unifi.APIRequest, next unifi.Handler) (*unifi.APIResponse, error) {
+				if req.Method != http.MethodPut {
+					return next(ctx, req)
+				}
+				updates++
+				if tt.failAt == 0 || updates < tt.failAt || (updates > tt.failAt && !tt.failRestore) {
+					return next(ctx, req)
+				}
+				if tt.cancel {
+					cancel()
+					return nil, ctx.Err()
+				}
+				return nil, errUpdateFailed
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = client.ReorderFirewallRules(ctx, "default", unifi.FirewallRuleset("WAN_IN"), []string{"r3", "r1", "r2"}, unifi.Header("X-Change", "reorder"))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				cause := errUpdateFailed
+				if tt.cancel {
+					cause = context.Canceled
+				}
+				if !errors.Is(err, cause) {
+					t.Fatalf("expected the cause to be kept, got %v", err)
+				}
+			}
+
+			for _, req := range srv.Requests() {
+				if strings.Contains(req.Path, "/rest/firewallrule") && req.Header.Get("X-Change") != "reorder" {
+					t.Fatalf("the request options were not applied to %s %s", req.Method, req.Path)
+				}
+			}
+
+			if tt.want == nil {
+				return
+			}
+			for _, rule := range srv.Objects("default", "firewallrule") {
+				id := rule["_id"].(string)
+				if index := int(rule["rule_index"].(float64)); index != tt.want[id] {
+					t.Errorf("rule %s has index %d, want %d", id, index, tt.want[id])
+				}
+			}
+		})
+	}
+}
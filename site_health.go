@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -49,8 +50,8 @@ type SiteHealthResponse struct {
 
 // SiteHealth queries the site for its health
 // site - the site to query
-func (c *Client) SiteHealth(site string) (*SiteHealthResponse, error) {
+func (c *Client) SiteHealth(ctx context.Context, site string, opts ...RequestOption) (*SiteHealthResponse, error) {
 	var resp SiteHealthResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/health", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/health", nil, &resp, opts...)
 	return &resp, err
 }
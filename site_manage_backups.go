@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 )
@@ -10,18 +11,18 @@ import (
 // site - site this device currently registered to
 // mac - the device mac
 // firmwareURL - the firmware URL
-func (c *Client) ListBackups(site string) (*GenericResponse, error) {
+func (c *Client) ListBackups(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	data := []byte(`{"cmd": "list-backup"}`)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/backup", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/backup", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // DeleteBackup will delete a backup on the filesystem
 // site - site this device currently registered to
 // filename - the backup file to delete
-func (c *Client) DeleteBackup(site string, filename string) (*GenericResponse, error) {
+func (c *Client) DeleteBackup(ctx context.Context, site string, filename string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":      "delete-backup",
 		"filename": filename,
@@ -29,16 +30,16 @@ func (c *Client) DeleteBackup(site string, filename string) (*GenericResponse, e
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/backup", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/backup", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // CreateBackup will create a backup to a fixed location on the filesystem.
 // site - site this device currently registered to
-func (c *Client) CreateBackup(site string) (*GenericResponse, error) {
+func (c *Client) CreateBackup(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	data := []byte(`{"cmd": "backup"}`)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/system", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/system", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 )
@@ -9,7 +10,7 @@ import (
 // AdoptDevice will adopt a device onto the current site.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) AdoptDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) AdoptDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "adopt",
 		"mac": mac,
@@ -17,14 +18,14 @@ func (c *Client) AdoptDevice(site string, mac string) (*GenericResponse, error)
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // RestartDevice will restart a device.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) RestartDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) RestartDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "restart",
 		"mac": mac,
@@ -32,14 +33,14 @@ func (c *Client) RestartDevice(site string, mac string) (*GenericResponse, error
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ForceProvisionDevice will force-provision an existing device.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) ForceProvisionDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) ForceProvisionDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "force-provision",
 		"mac": mac,
@@ -47,7 +48,7 @@ func (c *Client) ForceProvisionDevice(site string, mac string) (*GenericResponse
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -55,7 +56,7 @@ func (c *Client) ForceProvisionDevice(site string, mac string) (*GenericResponse
 // site - site this device currently registered to
 // mac - the device mac
 // portIdx - PoE port to cycle
-func (c *Client) PowerCycleDevice(site string, mac string, portIdx int) (*GenericResponse, error) {
+func (c *Client) PowerCycleDevice(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":      "power-cycle",
 		"mac":      mac,
@@ -64,34 +65,34 @@ func (c *Client) PowerCycleDevice(site string, mac string, portIdx int) (*Generi
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // StartSpeedTest will start a speed test.
 // site - site this device currently registered to
-func (c *Client) StartSpeedTest(site string) (*GenericResponse, error) {
+func (c *Client) StartSpeedTest(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	data := []byte(`{"cmd": "speedtest"}`)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // SpeedTestStatus will get the current state of a speet test.
 // site - site this device currently registered to
-func (c *Client) SpeedTestStatus(site string) (*GenericResponse, error) {
+func (c *Client) SpeedTestStatus(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	data := []byte(`{"cmd": "speedtest-status"}`)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // SetLocateDevice will blink a device unit to locate it.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) SetLocateDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) SetLocateDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "set-locate",
 		"mac": mac,
@@ -99,14 +100,14 @@ func (c *Client) SetLocateDevice(site string, mac string) (*GenericResponse, err
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // UnsetLocateDevice will return a blinking device led to normal state.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) UnsetLocateDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) UnsetLocateDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "unset-locate",
 		"mac": mac,
@@ -114,14 +115,14 @@ func (c *Client) UnsetLocateDevice(site string, mac string) (*GenericResponse, e
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // UpgradeDevice will trigger a firmware upgrade for the device
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) UpgradeDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) UpgradeDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "upgrade",
 		"mac": mac,
@@ -129,7 +130,7 @@ func (c *Client) UpgradeDevice(site string, mac string) (*GenericResponse, error
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -137,7 +138,7 @@ func (c *Client) UpgradeDevice(site string, mac string) (*GenericResponse, error
 // site - site this device currently registered to
 // mac - the device mac
 // firmwareURL - the firmware URL
-func (c *Client) UpgradeExternalDevice(site string, mac string, firmwareURL string) (*GenericResponse, error) {
+func (c *Client) UpgradeExternalDevice(ctx context.Context, site string, mac string, firmwareURL string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "upgrade-external",
 		"mac": mac,
@@ -146,7 +147,7 @@ func (c *Client) UpgradeExternalDevice(site string, mac string, firmwareURL stri
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -154,7 +155,7 @@ func (c *Client) UpgradeExternalDevice(site string, mac string, firmwareURL stri
 // site - site this device currently registered to
 // mac - the device mac
 // firmwareURL - the firmware URL
-func (c *Client) SpectrumScanDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) SpectrumScanDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "spectrum-scan",
 		"mac": mac,
@@ -162,7 +163,7 @@ func (c *Client) SpectrumScanDevice(site string, mac string) (*GenericResponse,
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/devmgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -172,7 +173,7 @@ func (c *Client) SpectrumScanDevice(site string, mac string) (*GenericResponse,
 //               use ListUserGroups to obtain this.
 // name - optional name to provide the user/client device
 // note - optional note to provide the user/client device
-func (c *Client) CreateNewUserClientDevice(site string, mac string, userGroupID string, name string, note string) (*GenericResponse, error) {
+func (c *Client) CreateNewUserClientDevice(ctx context.Context, site string, mac string, userGroupID string, name string, note string, opts ...RequestOption) (*GenericResponse, error) {
 	userPayload := map[string]interface{}{
 		"mac":          mac,
 		"usergroup_id": userGroupID,
@@ -195,7 +196,7 @@ func (c *Client) CreateNewUserClientDevice(site string, mac string, userGroupID
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "group/user", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "group/user", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -203,7 +204,7 @@ func (c *Client) CreateNewUserClientDevice(site string, mac string, userGroupID
 // userID - client user ID obtained from SiteDevicesDetailed
 // note - optional note to provide the user/client device
 //        when note is empty, the existing note for the client-device will be removed
-func (c *Client) SetUserClientDeviceNote(site string, userID string, note string) (*GenericResponse, error) {
+func (c *Client) SetUserClientDeviceNote(ctx context.Context, site string, userID string, note string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"noted": note != "",
 		"note":  note,
@@ -212,7 +213,7 @@ func (c *Client) SetUserClientDeviceNote(site string, userID string, note string
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "upd/user", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "upd/user", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -220,7 +221,7 @@ func (c *Client) SetUserClientDeviceNote(site string, userID string, note string
 // userID - client user ID obtained from SiteDevicesDetailed
 // name - optional name to provide the user/client device
 //        when note is empty, the existing note for the client-device will be removed
-func (c *Client) SetUserClientDeviceName(site string, userID string, name string) (*GenericResponse, error) {
+func (c *Client) SetUserClientDeviceName(ctx context.Context, site string, userID string, name string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"name": name,
 	}
@@ -228,6 +229,6 @@ func (c *Client) SetUserClientDeviceName(site string, userID string, name string
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "upd/user", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "upd/user", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,7 +14,7 @@ import (
 // site - the current site context
 // name - the new site name
 // description - the description of the site
-func (c *Client) AddSite(site string, name string, description string) (*GenericResponse, error) {
+func (c *Client) AddSite(ctx context.Context, site string, name string, description string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":  "add-site",
 		"name": name,
@@ -22,14 +23,14 @@ func (c *Client) AddSite(site string, name string, description string) (*Generic
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // UpdateSite will update an existing site with a new description.
 // site - the site to update
 // description - the new site description
-func (c *Client) UpdateSite(site string, description string) (*GenericResponse, error) {
+func (c *Client) UpdateSite(ctx context.Context, site string, description string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":  "update-site",
 		"desc": description,
@@ -37,13 +38,13 @@ func (c *Client) UpdateSite(site string, description string) (*GenericResponse,
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // DeleteSite will delete an existing site
 // site - the site to delete
-func (c *Client) DeleteSite(site string) (*GenericResponse, error) {
+func (c *Client) DeleteSite(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":  "delete-site",
 		"name": site,
@@ -51,7 +52,7 @@ func (c *Client) DeleteSite(site string) (*GenericResponse, error) {
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -60,7 +61,7 @@ func (c *Client) DeleteSite(site string) (*GenericResponse, error) {
 // siteID - the site's controller id
 // configID - the existing country _id configuration - available from SiteDetailedSettings
 // country - the country code returned by SiteCountryCodes
-func (c *Client) SetSiteCountry(site string, siteID string, configID string, country SiteCountryCode) (*GenericResponse, error) {
+func (c *Client) SetSiteCountry(ctx context.Context, site string, siteID string, configID string, country SiteCountryCode, opts ...RequestOption) (*GenericResponse, error) {
 	payload := []map[string]interface{}{
 		{
 			"site_id": siteID,
@@ -71,7 +72,7 @@ func (c *Client) SetSiteCountry(site string, siteID string, configID string, cou
 	data, _ := json.Marshal(payload)
 	extPath := path.Join("rest/setting/country/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -80,7 +81,7 @@ func (c *Client) SetSiteCountry(site string, siteID string, configID string, cou
 // siteID - the site's controller id
 // configID - the existing timezone (locale) _id configuration - available from SiteDetailedSettings
 // timezone - the timezone - available from SiteDetailedSettings
-func (c *Client) SetSiteTimezone(site string, siteID string, configID string, timezone string) (*GenericResponse, error) {
+func (c *Client) SetSiteTimezone(ctx context.Context, site string, siteID string, configID string, timezone string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := []map[string]interface{}{
 		{
 			"site_id":  siteID,
@@ -91,7 +92,7 @@ func (c *Client) SetSiteTimezone(site string, siteID string, configID string, ti
 	data, _ := json.Marshal(payload)
 	extPath := path.Join("rest/setting/locale/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -100,7 +101,7 @@ func (c *Client) SetSiteTimezone(site string, siteID string, configID string, ti
 // siteID - the site's controller id
 // configID - the existing SNMP _id configuration - available from SiteDetailedSettings
 // community - the SNMP community setting
-func (c *Client) SetSiteSNMP(site string, siteID string, configID string, community string) (*GenericResponse, error) {
+func (c *Client) SetSiteSNMP(ctx context.Context, site string, siteID string, configID string, community string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := []map[string]interface{}{
 		{
 			"site_id":   siteID,
@@ -111,7 +112,7 @@ func (c *Client) SetSiteSNMP(site string, siteID string, configID string, commun
 	data, _ := json.Marshal(payload)
 	extPath := path.Join("rest/setting/snmp/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -129,7 +130,7 @@ type SiteManagementConfig struct {
 // siteID - the site's controller id
 // configID - the existing mgmt _id configuration - available from SiteDetailedSettings
 // config - the SiteManagementConfig settings
-func (c *Client) SetSiteManagementConfig(site string, siteID string, configID string, config SiteManagementConfig) (*GenericResponse, error) {
+func (c *Client) SetSiteManagementConfig(ctx context.Context, site string, siteID string, configID string, config SiteManagementConfig, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"site_id": siteID,
 		"key":     "mgmt",
@@ -153,7 +154,7 @@ func (c *Client) SetSiteManagementConfig(site string, siteID string, configID st
 	data, _ := json.Marshal(payloads)
 	extPath := path.Join("rest/setting/mgmt/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -173,7 +174,7 @@ type SiteGuestAccessConfig struct {
 // siteID - the site's controller id
 // configID - the existing guest_access _id configuration - available from SiteDetailedSettings
 // config - the SiteGuessAccessConfig settings
-func (c *Client) SetSiteGuestAccessConfig(site string, siteID string, configID string, config SiteGuestAccessConfig) (*GenericResponse, error) {
+func (c *Client) SetSiteGuestAccessConfig(ctx context.Context, site string, siteID string, configID string, config SiteGuestAccessConfig, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"site_id": siteID,
 		"key":     "guest_access",
@@ -204,7 +205,7 @@ func (c *Client) SetSiteGuestAccessConfig(site string, siteID string, configID s
 	data, _ := json.Marshal(payloads)
 	extPath := path.Join("rest/setting/guest_access/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -221,7 +222,7 @@ type SiteNTPConfig struct {
 // siteID - the site's controller id
 // configID - the existing guest_access _id configuration - available from SiteDetailedSettings
 // config - the SiteNTPConfig settings
-func (c *Client) SetSiteNTPConfig(site string, siteID string, configID string, config SiteNTPConfig) (*GenericResponse, error) {
+func (c *Client) SetSiteNTPConfig(ctx context.Context, site string, siteID string, configID string, config SiteNTPConfig, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"site_id": siteID,
 		"key":     "ntp",
@@ -243,7 +244,7 @@ func (c *Client) SetSiteNTPConfig(site string, siteID string, configID string, c
 	data, _ := json.Marshal(payloads)
 	extPath := path.Join("rest/setting/connectivity/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -252,7 +253,7 @@ func (c *Client) SetSiteNTPConfig(site string, siteID string, configID string, c
 // siteID - the site's controller id
 // configID - the existing guest_access _id configuration - available from SiteDetailedSettings
 // uplinkType - the uplink type (e.g. "gateway")
-func (c *Client) SetSiteConnectivityConfig(site string, siteID string, configID string, uplinkType string) (*GenericResponse, error) {
+func (c *Client) SetSiteConnectivityConfig(ctx context.Context, site string, siteID string, configID string, uplinkType string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"site_id":     siteID,
 		"key":         "connectivity",
@@ -263,17 +264,17 @@ func (c *Client) SetSiteConnectivityConfig(site string, siteID string, configID
 	data, _ := json.Marshal(payloads)
 	extPath := path.Join("rest/setting/connectivity/", strings.TrimSpace(configID))
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // GetSiteAdmins will return the current site admins
 // site - the site to query
-func (c *Client) GetSiteAdmins(site string) (*GenericResponse, error) {
+func (c *Client) GetSiteAdmins(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	data := []byte(`{"cmd": "get-admins"}`)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -289,7 +290,7 @@ func (c *Client) GetSiteAdmins(site string) (*GenericResponse, error) {
 // notes:
 //   - after issuing a valid request, an invite will be sent to the email address provided
 //   - issuing this command against an existing admin will trigger a "re-invite"
-func (c *Client) InviteSiteAdmin(site string, name string, email string, disableSSO bool, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool) (*GenericResponse, error) {
+func (c *Client) InviteSiteAdmin(ctx context.Context, site string, name string, email string, disableSSO bool, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...RequestOption) (*GenericResponse, error) {
 	permissions := make([]string, 0)
 	if deviceAdoptPermission {
 		permissions = append(permissions, "API_DEVICE_ADOPT")
@@ -311,7 +312,7 @@ func (c *Client) InviteSiteAdmin(site string, name string, email string, disable
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -321,7 +322,7 @@ func (c *Client) InviteSiteAdmin(site string, name string, email string, disable
 // readOnly - set to true to make the admin user read-only
 // deviceAdoptPermission - set to true to allow the new admin permissions to adopt devices.
 // deviceRestartPermission - set to true to allow the new admin permissions to restart devices.
-func (c *Client) AssignExistingSiteAdmin(site string, adminID string, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool) (*GenericResponse, error) {
+func (c *Client) AssignExistingSiteAdmin(ctx context.Context, site string, adminID string, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...RequestOption) (*GenericResponse, error) {
 	permissions := make([]string, 0)
 	if deviceAdoptPermission {
 		permissions = append(permissions, "API_DEVICE_ADOPT")
@@ -341,14 +342,14 @@ func (c *Client) AssignExistingSiteAdmin(site string, adminID string, readOnly b
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // RevokeSiteAdmin will revoke a site admin access
 // site - the site to invite the admin to
 // adminID - 24-char string _id of the site admin - from GetSiteAdmins
-func (c *Client) RevokeSiteAdmin(site string, adminID string) (*GenericResponse, error) {
+func (c *Client) RevokeSiteAdmin(ctx context.Context, site string, adminID string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"admin": strings.TrimSpace(adminID),
 		"cmd":   "revoke-admin",
@@ -356,7 +357,7 @@ func (c *Client) RevokeSiteAdmin(site string, adminID string) (*GenericResponse,
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -364,7 +365,7 @@ func (c *Client) RevokeSiteAdmin(site string, adminID string) (*GenericResponse,
 // site - site this device currently registered to
 // mac - the device mac
 // newSiteID - the new 24 digit site ID to move this device to.
-func (c *Client) MoveDevice(site string, mac string, newSiteID string) (*GenericResponse, error) {
+func (c *Client) MoveDevice(ctx context.Context, site string, mac string, newSiteID string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd":     "move-device",
 		"mac":     mac,
@@ -373,14 +374,14 @@ func (c *Client) MoveDevice(site string, mac string, newSiteID string) (*Generic
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // DeleteDevice will remove a device from the current site
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) DeleteDevice(site string, mac string) (*GenericResponse, error) {
+func (c *Client) DeleteDevice(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "delete-device",
 		"mac": mac,
@@ -388,14 +389,14 @@ func (c *Client) DeleteDevice(site string, mac string) (*GenericResponse, error)
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // BlockSTA will block a STA from the current site.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) BlockSTA(site string, mac string) (*GenericResponse, error) {
+func (c *Client) BlockSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "block-sta",
 		"mac": mac,
@@ -403,14 +404,14 @@ func (c *Client) BlockSTA(site string, mac string) (*GenericResponse, error) {
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // UnblockSTA will unblock a STA from the current site.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) UnblockSTA(site string, mac string) (*GenericResponse, error) {
+func (c *Client) UnblockSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "unblock-sta",
 		"mac": mac,
@@ -418,14 +419,14 @@ func (c *Client) UnblockSTA(site string, mac string) (*GenericResponse, error) {
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // KickSTA will kick a STA from the current site.
 // site - site this device currently registered to
 // mac - the device mac
-func (c *Client) KickSTA(site string, mac string) (*GenericResponse, error) {
+func (c *Client) KickSTA(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "kick-sta",
 		"mac": mac,
@@ -433,14 +434,21 @@ func (c *Client) KickSTA(site string, mac string) (*GenericResponse, error) {
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ForgetSTA will forget a STA from the current site.
 // site - site this device currently registered to
-// mac - the device mac
-func (c *Client) ForgetSTA(site string, macs ...string) (*GenericResponse, error) {
+// macs - the device macs
+func (c *Client) ForgetSTA(ctx context.Context, site string, macs ...string) (*GenericResponse, error) {
+	return c.ForgetSTAWithOptions(ctx, site, macs)
+}
+
+// ForgetSTAWithOptions is ForgetSTA with request options
+// site - site this device currently registered to
+// macs - the device macs
+func (c *Client) ForgetSTAWithOptions(ctx context.Context, site string, macs []string, opts ...RequestOption) (*GenericResponse, error) {
 	if len(macs) == 0 {
 		return nil, fmt.Errorf("must specify at least one mac")
 	}
@@ -451,6 +459,6 @@ func (c *Client) ForgetSTA(site string, macs ...string) (*GenericResponse, error
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/sitemgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
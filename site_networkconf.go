@@ -0,0 +1,332 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkPurpose is the kind of network
+type NetworkPurpose string
+
+// The network purposes
+const (
+	NetworkPurposeCorporate     NetworkPurpose = "corporate"       // routed LAN, optionally on a VLAN
+	NetworkPurposeVLANOnly      NetworkPurpose = "vlan-only"       // VLAN not routed by the gateway
+	NetworkPurposeGuest         NetworkPurpose = "guest"           // routed LAN with the guest policies applied
+	NetworkPurposeWAN           NetworkPurpose = "wan"             // uplink of the gateway
+	NetworkPurposeRemoteUserVPN NetworkPurpose = "remote-user-vpn" // L2TP or OpenVPN server for remote users
+	NetworkPurposeSiteVPN       NetworkPurpose = "site-vpn"        // site to site VPN
+)
+
+// IsValid returns true if it's a valid network purpose.
+func (p NetworkPurpose) IsValid() bool {
+	switch p {
+	case NetworkPurposeCorporate, NetworkPurposeVLANOnly, NetworkPurposeGuest, NetworkPurposeWAN,
+		NetworkPurposeRemoteUserVPN, NetworkPurposeSiteVPN:
+		return true
+	default:
+		return false
+	}
+}
+
+// The VLAN IDs accepted by the controller, 1 is the untagged default network
+const (
+	MinVLANID = 2
+	MaxVLANID = 4009
+)
+
+// NetworkConf is a typed network of rest/networkconf
+type NetworkConf struct {
+	ID           string         `json:"_id,omitempty"`
+	SiteID       string         `json:"site_id,omitempty"`
+	Name         string         `json:"name"`
+	Purpose      NetworkPurpose `json:"purpose"`
+	Enabled      bool           `json:"enabled"`
+	NetworkGroup string         `json:"networkgroup,omitempty"` // LAN, LAN2, ...
+	VLANEnabled  bool           `json:"vlan_enabled"`
+	VLAN         int            `json:"vlan,omitempty"`
+	IPSubnet     string         `json:"ip_subnet,omitempty"` // gateway address and prefix, like 192.168.1.1/24
+	DomainName   string         `json:"domain_name,omitempty"`
+	IGMPSnooping bool           `json:"igmp_snooping"`
+
+	// DHCP server
+	DHCPDEnabled        bool   `json:"dhcpd_enabled"`
+	DHCPDStart          string `json:"dhcpd_start,omitempty"`
+	DHCPDStop           string `json:"dhcpd_stop,omitempty"`
+	DHCPDLeaseTime      int    `json:"dhcpd_leasetime,omitempty"` // seconds
+	DHCPDDNSEnabled     bool   `json:"dhcpd_dns_enabled"`
+	DHCPDDNS1           string `json:"dhcpd_dns_1,omitempty"`
+	DHCPDDNS2           string `json:"dhcpd_dns_2,omitempty"`
+	DHCPDDNS3           string `json:"dhcpd_dns_3,omitempty"`
+	DHCPDDNS4           string `json:"dhcpd_dns_4,omitempty"`
+	DHCPDGatewayEnabled bool   `json:"dhcpd_gateway_enabled"`
+	DHCPDGateway        string `json:"dhcpd_gateway,omitempty"`
+	DHCPDNTPEnabled     bool   `json:"dhcpd_ntp_enabled"`
+	DHCPDNTP1           string `json:"dhcpd_ntp_1,omitempty"`
+	DHCPDNTP2           string `json:"dhcpd_ntp_2,omitempty"`
+	DHCPDBootEnabled    bool   `json:"dhcpd_boot_enabled"`
+	DHCPDBootServer     string `json:"dhcpd_boot_server,omitempty"`
+	DHCPDBootFilename   string `json:"dhcpd_boot_filename,omitempty"`
+	DHCPDTFTPServer     string `json:"dhcpd_tftp_server,omitempty"`
+	DHCPRelayEnabled    bool   `json:"dhcp_relay_enabled"`
+
+	// IPv6
+	IPV6InterfaceType string `json:"ipv6_interface_type,omitempty"` // none, static or pd
+	IPV6Subnet        string `json:"ipv6_subnet,omitempty"`         // static gateway address and prefix
+	IPV6PDInterface   string `json:"ipv6_pd_interface,omitempty"`   // wan interface delegating the prefix
+	IPV6PDPrefixID    string `json:"ipv6_pd_prefixid,omitempty"`
+	IPV6RAEnabled     bool   `json:"ipv6_ra_enabled"`
+	DHCPDV6Enabled    bool   `json:"dhcpdv6_enabled"`
+	DHCPDV6Start      string `json:"dhcpdv6_start,omitempty"`
+	DHCPDV6Stop       string `json:"dhcpdv6_stop,omitempty"`
+
+	// WAN
+	WANNetworkGroup string `json:"wan_networkgroup,omitempty"` // WAN or WAN2
+	WANType         string `json:"wan_type,omitempty"`         // dhcp, static or pppoe
+	WANIP           string `json:"wan_ip,omitempty"`
+	WANNetmask      string `json:"wan_netmask,omitempty"`
+	WANGateway      string `json:"wan_gateway,omitempty"`
+	WANDNS1         string `json:"wan_dns1,omitempty"`
+	WANDNS2         string `json:"wan_dns2,omitempty"`
+	WANUsername     string `json:"wan_username,omitempty"`
+	WANPassword     string `json:"x_wan_password,omitempty"`
+	WANVLANEnabled  bool   `json:"wan_vlan_enabled"`
+	WANVLAN         int    `json:"wan_vlan,omitempty"`
+
+	// VPN
+	VPNType           string   `json:"vpn_type,omitempty"` // l2tp-server, openvpn-server, ipsec-vpn, openvpn-vpn, ...
+	RADIUSProfileID   string   `json:"radiusprofile_id,omitempty"`
+	IPSecPreSharedKey string   `json:"x_ipsec_pre_shared_key,omitempty"`
+	IPSecPeerIP       string   `json:"ipsec_peer_ip,omitempty"`
+	IPSecLocalIP      string   `json:"ipsec_local_ip,omitempty"`
+	RemoteSubnets     []string `json:"remote_site_subnets,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, some controller versions send the VLAN IDs as strings
+func (n *NetworkConf) UnmarshalJSON(data []byte) error {
+	type networkConf NetworkConf
+	aux := struct {
+		*networkConf
+		VLAN    interface{} `json:"vlan"`
+		WANVLAN interface{} `json:"wan_vlan"`
+	}{networkConf: (*networkConf)(n)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	n.VLAN, err = flexibleInt(aux.VLAN)
+	if err != nil {
+		return errors.Wrap(err, "invalid vlan")
+	}
+	n.WANVLAN, err = flexibleInt(aux.WANVLAN)
+	if err != nil {
+		return errors.Wrap(err, "invalid wan_vlan")
+	}
+	return nil
+}
+
+// flexibleInt converts a decoded json number or numeric string
+func flexibleInt(v interface{}) (int, error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int(val), nil
+	case string:
+		if val == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(val)
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+}
+
+// Validate checks the network before it is sent to the controller
+func (n *NetworkConf) Validate() error {
+	if strings.TrimSpace(n.Name) == "" {
+		return fmt.Errorf("network name is required")
+	}
+	if !n.Purpose.IsValid() {
+		return fmt.Errorf("invalid network purpose specified: %s", n.Purpose)
+	}
+	if n.VLANEnabled || n.Purpose == NetworkPurposeVLANOnly {
+		if err := validateVLAN(n.VLAN); err != nil {
+			return err
+		}
+	}
+
+	switch n.Purpose {
+	case NetworkPurposeCorporate, NetworkPurposeGuest:
+		subnet, err := validateGatewaySubnet(n.IPSubnet)
+		if err != nil {
+			return err
+		}
+		if n.DHCPDEnabled {
+			if err := validateDHCPRange(subnet, n.DHCPDStart, n.DHCPDStop); err != nil {
+				return err
+			}
+		}
+	case NetworkPurposeRemoteUserVPN:
+		if _, err := validateGatewaySubnet(n.IPSubnet); err != nil {
+			return err
+		}
+	case NetworkPurposeSiteVPN:
+		for _, remote := range n.RemoteSubnets {
+			if _, _, err := net.ParseCIDR(remote); err != nil {
+				return fmt.Errorf("invalid remote subnet %q", remote)
+			}
+		}
+	case NetworkPurposeWAN:
+		if n.WANVLANEnabled {
+			if err := validateVLAN(n.WANVLAN); err != nil {
+				return err
+			}
+		}
+		if n.WANType == "static" {
+			for _, addr := range []string{n.WANIP, n.WANNetmask, n.WANGateway} {
+				if net.ParseIP(addr).To4() == nil {
+					return fmt.Errorf("static wan requires valid wan_ip, wan_netmask and wan_gateway, got %q", addr)
+				}
+			}
+		}
+	}
+
+	if n.IPV6InterfaceType == "static" {
+		ip, _, err := net.ParseCIDR(n.IPV6Subnet)
+		if err != nil || ip.To4() != nil {
+			return fmt.Errorf("invalid ipv6 subnet %q", n.IPV6Subnet)
+		}
+	}
+	return nil
+}
+
+// validateVLAN checks the VLAN ID is within the range accepted by the controller
+func validateVLAN(vlan int) error {
+	if vlan < MinVLANID || vlan > MaxVLANID {
+		return fmt.Errorf("invalid vlan %d, must be between %d and %d", vlan, MinVLANID, MaxVLANID)
+	}
+	return nil
+}
+
+// validateGatewaySubnet checks the subnet is an IPv4 gateway address with its prefix, like 192.168.1.1/24
+func validateGatewaySubnet(subnet string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid subnet %q, expected the gateway address and prefix like 192.168.1.1/24", subnet)
+	}
+	ones, _ := ipNet.Mask.Size()
+	if ones < 8 || ones > 30 {
+		return nil, fmt.Errorf("invalid subnet %q, the prefix must be between /8 and /30", subnet)
+	}
+	ip = ip.To4()
+	network := ipNet.IP.To4()
+	broadcast := make(net.IP, len(network))
+	for i := range network {
+		broadcast[i] = network[i] | ^ipNet.Mask[i]
+	}
+	if ip.Equal(network) || ip.Equal(broadcast) {
+		return nil, fmt.Errorf("invalid subnet %q, the gateway can not be the network or broadcast address", subnet)
+	}
+	return ipNet, nil
+}
+
+// validateDHCPRange checks the range lies within the subnet and is ordered
+func validateDHCPRange(subnet *net.IPNet, start string, stop string) error {
+	startIP := net.ParseIP(start).To4()
+	stopIP := net.ParseIP(stop).To4()
+	if startIP == nil || stopIP == nil {
+		return fmt.Errorf("invalid dhcp range %q - %q", start, stop)
+	}
+	if !subnet.Contains(startIP) || !subnet.Contains(stopIP) {
+		return fmt.Errorf("dhcp range %s - %s is outside of the subnet %s", start, stop, subnet)
+	}
+	if bytes.Compare(startIP, stopIP) > 0 {
+		return fmt.Errorf("dhcp range start %s is after its stop %s", start, stop)
+	}
+	return nil
+}
+
+// NetworkConfResponse contains the networks response
+type NetworkConfResponse struct {
+	Meta CommonMeta    `json:"meta"`
+	Data []NetworkConf `json:"data"`
+}
+
+// ListNetworks lists the networks of the site, their IDs are used by UpdateClientFixedIP
+// site - the site to query
+func (c *Client) ListNetworks(ctx context.Context, site string, opts ...RequestOption) (*NetworkConfResponse, error) {
+	var resp NetworkConfResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/networkconf", nil, &resp, opts...)
+	return &resp, err
+}
+
+// GetNetwork gets a single network
+// site - the site to query
+// networkID - the ID of the network
+func (c *Client) GetNetwork(ctx context.Context, site string, networkID string, opts ...RequestOption) (*NetworkConf, error) {
+	var resp NetworkConfResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/networkconf/"+strings.TrimSpace(networkID), nil, &resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "network %s", networkID)
+	}
+	return &resp.Data[0], nil
+}
+
+// CreateNetwork validates and creates a new network, the ID of the network is ignored
+// site - the site to modify
+// network - the network configuration
+func (c *Client) CreateNetwork(ctx context.Context, site string, network NetworkConf, opts ...RequestOption) (*NetworkConfResponse, error) {
+	if err := network.Validate(); err != nil {
+		return nil, err
+	}
+	network.ID = ""
+	data, err := json.Marshal(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp NetworkConfResponse
+	err = c.doSiteRequest(ctx, http.MethodPost, site, "rest/networkconf", bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// UpdateNetwork validates and replaces an existing network
+// site - the site to modify
+// network - the network configuration, its ID selects the network to update
+func (c *Client) UpdateNetwork(ctx context.Context, site string, network NetworkConf, opts ...RequestOption) (*NetworkConfResponse, error) {
+	if network.ID == "" {
+		return nil, fmt.Errorf("network ID is required")
+	}
+	if err := network.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp NetworkConfResponse
+	err = c.doSiteRequest(ctx, http.MethodPut, site, "rest/networkconf/"+strings.TrimSpace(network.ID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// DeleteNetwork deletes an existing network
+// site - the site to modify
+// networkID - the ID of the network
+func (c *Client) DeleteNetwork(ctx context.Context, site string, networkID string, opts ...RequestOption) (*GenericResponse, error) {
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, "rest/networkconf/"+strings.TrimSpace(networkID), nil, &resp, opts...)
+	return &resp, err
+}
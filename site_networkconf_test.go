@@ -0,0 +1,135 @@
+package unifi
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+func TestValidateGatewaySubnet(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		network string
+		wantErr bool
+	}{
+		{subnet: "192.168.1.1/24", network: "192.168.1.0/24"},
+		{subnet: "192.168.1.254/24", network: "192.168.1.0/24"},
+		{subnet: "10.0.0.1/8", network: "10.0.0.0/8"},
+		{subnet: "172.16.0.1/30", network: "172.16.0.0/30"},
+		{subnet: "192.168.1.0/24", wantErr: true},   // network address
+		{subnet: "192.168.1.255/24", wantErr: true}, // broadcast address
+		{subnet: "172.16.0.3/30", wantErr: true},    // broadcast address
+		{subnet: "10.0.0.1/7", wantErr: true},
+		{subnet: "172.16.0.1/31", wantErr: true},
+		{subnet: "192.168.1.1", wantErr: true},
+		{subnet: "fd00::1/64", wantErr: true},
+		{subnet: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subnet, func(t *testing.T) {
+			ipNet, err := validateGatewaySubnet(tt.subnet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateGatewaySubnet(%q) error = %v, wantErr %v", tt.subnet, err, tt.wantErr)
+			}
+			if !tt.wantErr && ipNet.String() != tt.network {
+				t.Fatalf("validateGatewaySubnet(%q) = %s, want %s", tt.subnet, ipNet, tt.network)
+			}
+		})
+	}
+}
+
+func TestValidateDHCPRange(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.1.0/24")
+	tests := []struct {
+		name        string
+		start, stop string
+		wantErr     bool
+	}{
+		{name: "within subnet", start: "192.168.1.6", stop: "192.168.1.254"},
+		{name: "single address", start: "192.168.1.100", stop: "192.168.1.100"},
+		{name: "reversed", start: "192.168.1.200", stop: "192.168.1.100", wantErr: true},
+		{name: "start outside", start: "192.168.0.6", stop: "192.168.1.254", wantErr: true},
+		{name: "stop outside", start: "192.168.1.6", stop: "192.168.2.1", wantErr: true},
+		{name: "missing stop", start: "192.168.1.6", wantErr: true},
+		{name: "ipv6", start: "fd00::6", stop: "fd00::ff", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDHCPRange(subnet, tt.start, tt.stop); (err != nil) != tt.wantErr {
+				t.Fatalf("validateDHCPRange(%q, %q) error = %v, wantErr %v", tt.start, tt.stop, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNetworkConfValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		network NetworkConf
+		wantErr bool
+	}{
+		{name: "corporate", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24",
+			DHCPDEnabled: true, DHCPDStart: "192.168.1.6", DHCPDStop: "192.168.1.254"}},
+		{name: "missing name", network: NetworkConf{Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24"}, wantErr: true},
+		{name: "invalid purpose", network: NetworkConf{Name: "LAN", Purpose: "lan"}, wantErr: true},
+		{name: "gateway on broadcast", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.255/24"}, wantErr: true},
+		{name: "guest dhcp outside of subnet", network: NetworkConf{Name: "Guest", Purpose: NetworkPurposeGuest, IPSubnet: "192.168.2.1/24",
+			DHCPDEnabled: true, DHCPDStart: "192.168.1.6", DHCPDStop: "192.168.1.254"}, wantErr: true},
+		{name: "dhcp range ignored when disabled", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24",
+			DHCPDStart: "10.0.0.6"}},
+		{name: "vlan", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.20.1/24", VLANEnabled: true, VLAN: 20}},
+		{name: "lowest vlan", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeVLANOnly, VLAN: MinVLANID}},
+		{name: "highest vlan", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeVLANOnly, VLAN: MaxVLANID}},
+		{name: "default vlan", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.20.1/24", VLANEnabled: true, VLAN: 1}, wantErr: true},
+		{name: "vlan above range", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeVLANOnly, VLAN: 4010}, wantErr: true},
+		{name: "vlan only without vlan", network: NetworkConf{Name: "IoT", Purpose: NetworkPurposeVLANOnly}, wantErr: true},
+		{name: "vlan ignored when disabled", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24", VLAN: 5000}},
+		{name: "remote user vpn", network: NetworkConf{Name: "VPN", Purpose: NetworkPurposeRemoteUserVPN, IPSubnet: "192.168.3.1/24"}},
+		{name: "remote user vpn on network address", network: NetworkConf{Name: "VPN", Purpose: NetworkPurposeRemoteUserVPN, IPSubnet: "192.168.3.0/24"}, wantErr: true},
+		{name: "site vpn", network: NetworkConf{Name: "Office", Purpose: NetworkPurposeSiteVPN, RemoteSubnets: []string{"10.1.0.0/16", "10.2.0.0/16"}}},
+		{name: "site vpn invalid remote", network: NetworkConf{Name: "Office", Purpose: NetworkPurposeSiteVPN, RemoteSubnets: []string{"10.1.0.0"}}, wantErr: true},
+		{name: "static wan", network: NetworkConf{Name: "WAN", Purpose: NetworkPurposeWAN, WANType: "static",
+			WANIP: "203.0.113.2", WANNetmask: "255.255.255.0", WANGateway: "203.0.113.1"}},
+		{name: "static wan without gateway", network: NetworkConf{Name: "WAN", Purpose: NetworkPurposeWAN, WANType: "static",
+			WANIP: "203.0.113.2", WANNetmask: "255.255.255.0"}, wantErr: true},
+		{name: "wan vlan", network: NetworkConf{Name: "WAN", Purpose: NetworkPurposeWAN, WANType: "dhcp", WANVLANEnabled: true, WANVLAN: 0}, wantErr: true},
+		{name: "static ipv6", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24",
+			IPV6InterfaceType: "static", IPV6Subnet: "fd00::1/64"}},
+		{name: "static ipv6 with ipv4 subnet", network: NetworkConf{Name: "LAN", Purpose: NetworkPurposeCorporate, IPSubnet: "192.168.1.1/24",
+			IPV6InterfaceType: "static", IPV6Subnet: "192.168.1.1/24"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.network.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNetworkConfUnmarshalVLAN(t *testing.T) {
+	tests := []struct {
+		data    string
+		vlan    int
+		wanVLAN int
+		wantErr bool
+	}{
+		{data: `{"vlan":20,"wan_vlan":"10"}`, vlan: 20, wanVLAN: 10},
+		{data: `{"vlan":"20","wan_vlan":""}`, vlan: 20},
+		{data: `{}`},
+		{data: `{"vlan":"twenty"}`, wantErr: true},
+		{data: `{"vlan":true}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var n NetworkConf
+			err := json.Unmarshal([]byte(tt.data), &n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (n.VLAN != tt.vlan || n.WANVLAN != tt.wanVLAN) {
+				t.Fatalf("got vlan %d and wan vlan %d, want %d and %d", n.VLAN, n.WANVLAN, tt.vlan, tt.wanVLAN)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PortForwarding selects the networks a switch port carries
+type PortForwarding string
+
+// The port forwarding modes
+const (
+	PortForwardingAll       PortForwarding = "all"       // native network untagged, every other network tagged
+	PortForwardingNative    PortForwarding = "native"    // only the native network
+	PortForwardingCustomize PortForwarding = "customize" // native network untagged, the tagged networks of the profile
+	PortForwardingDisabled  PortForwarding = "disabled"  // the port is disabled
+)
+
+// IsValid returns true if it's a valid port forwarding mode.
+func (f PortForwarding) IsValid() bool {
+	switch f {
+	case PortForwardingAll, PortForwardingNative, PortForwardingCustomize, PortForwardingDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// PoEMode is the power over ethernet mode of a switch port
+type PoEMode string
+
+// The PoE modes
+const (
+	PoEModeAuto        PoEMode = "auto"        // 802.3af/at negotiated power
+	PoEModePassive24V  PoEMode = "pasv24"      // passive 24V
+	PoEModePassthrough PoEMode = "passthrough" // passes the PoE input through
+	PoEModeOff         PoEMode = "off"
+)
+
+// IsValid returns true if it's a valid PoE mode.
+func (m PoEMode) IsValid() bool {
+	switch m {
+	case PoEModeAuto, PoEModePassive24V, PoEModePassthrough, PoEModeOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dot1xControl is the 802.1X port based access control of a switch port
+type Dot1xControl string
+
+// The 802.1X control modes
+const (
+	Dot1xControlForceAuthorized   Dot1xControl = "force_authorized" // no authentication
+	Dot1xControlAuto              Dot1xControl = "auto"             // a single authenticated client unlocks the port
+	Dot1xControlForceUnauthorized Dot1xControl = "force_unauthorized"
+	Dot1xControlMACBased          Dot1xControl = "mac_based"  // every client authenticates by mac
+	Dot1xControlMultiHost         Dot1xControl = "multi_host" // every client authenticates with 802.1X
+)
+
+// IsValid returns true if it's a valid 802.1X control mode.
+func (d Dot1xControl) IsValid() bool {
+	switch d {
+	case Dot1xControlForceAuthorized, Dot1xControlAuto, Dot1xControlForceUnauthorized, Dot1xControlMACBased, Dot1xControlMultiHost:
+		return true
+	default:
+		return false
+	}
+}
+
+// PortProfile is a typed switch port profile of rest/portconf
+type PortProfile struct {
+	ID                    string         `json:"_id,omitempty"`
+	SiteID                string         `json:"site_id,omitempty"`
+	Name                  string         `json:"name"`
+	Forward               PortForwarding `json:"forward"`
+	NativeNetworkID       string         `json:"native_networkconf_id,omitempty"`
+	TaggedNetworkIDs      []string       `json:"tagged_networkconf_ids,omitempty"` // used with PortForwardingCustomize
+	VoiceNetworkID        string         `json:"voice_networkconf_id,omitempty"`
+	POEMode               PoEMode        `json:"poe_mode,omitempty"`
+	Dot1xCtrl             Dot1xControl   `json:"dot1x_ctrl,omitempty"`
+	Dot1xIdleTimeout      int            `json:"dot1x_idle_timeout,omitempty"` // seconds
+	Isolation             bool           `json:"isolation"`
+	STPPortMode           bool           `json:"stp_port_mode"`
+	Autoneg               bool           `json:"autoneg"`
+	Speed                 int            `json:"speed,omitempty"` // Mbps when autoneg is disabled
+	FullDuplex            bool           `json:"full_duplex"`
+	LLDPMedEnabled        bool           `json:"lldpmed_enabled"`
+	LLDPMedNotifyEnabled  bool           `json:"lldpmed_notify_enabled"`
+	StormctrlBcastEnabled bool           `json:"stormctrl_bcast_enabled"`
+	StormctrlBcastRate    int            `json:"stormctrl_bcast_rate,omitempty"` // percent of the port speed
+	StormctrlMcastEnabled bool           `json:"stormctrl_mcast_enabled"`
+	StormctrlMcastRate    int            `json:"stormctrl_mcast_rate,omitempty"`
+	StormctrlUcastEnabled bool           `json:"stormctrl_ucast_enabled"`
+	StormctrlUcastRate    int            `json:"stormctrl_ucast_rate,omitempty"`
+	AttrNoDelete          bool           `json:"attr_no_delete,omitempty"` // built-in profile
+}
+
+// Validate checks the port profile before it is sent to the controller
+func (p *PortProfile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("port profile name is required")
+	}
+	if !p.Forward.IsValid() {
+		return fmt.Errorf("invalid port forwarding specified: %s", p.Forward)
+	}
+	if p.POEMode != "" && !p.POEMode.IsValid() {
+		return fmt.Errorf("invalid poe mode specified: %s", p.POEMode)
+	}
+	if p.Dot1xCtrl != "" && !p.Dot1xCtrl.IsValid() {
+		return fmt.Errorf("invalid 802.1X control specified: %s", p.Dot1xCtrl)
+	}
+	if p.Forward != PortForwardingCustomize && len(p.TaggedNetworkIDs) > 0 {
+		return fmt.Errorf("tagged networks require the %s port forwarding", PortForwardingCustomize)
+	}
+	for name, rate := range map[string]int{"broadcast": p.StormctrlBcastRate, "multicast": p.StormctrlMcastRate, "unicast": p.StormctrlUcastRate} {
+		if rate < 0 || rate > 100 {
+			return fmt.Errorf("invalid %s storm control rate %d, must be between 0 and 100", name, rate)
+		}
+	}
+	return nil
+}
+
+// PortProfileResponse contains the port profiles response
+type PortProfileResponse struct {
+	Meta CommonMeta    `json:"meta"`
+	Data []PortProfile `json:"data"`
+}
+
+// ListPortProfiles lists the switch port profiles of the site, including the built-in ones
+// site - the site to query
+func (c *Client) ListPortProfiles(ctx context.Context, site string, opts ...RequestOption) (*PortProfileResponse, error) {
+	var resp PortProfileResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/portconf", nil, &resp, opts...)
+	return &resp, err
+}
+
+// GetPortProfile gets a single switch port profile
+// site - the site to query
+// profileID - the ID of the port profile
+func (c *Client) GetPortProfile(ctx context.Context, site string, profileID string, opts ...RequestOption) (*PortProfile, error) {
+	var resp PortProfileResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/portconf/"+strings.TrimSpace(profileID), nil, &resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "port profile %s", profileID)
+	}
+	return &resp.Data[0], nil
+}
+
+// CreatePortProfile validates and creates a new switch port profile, the ID of the profile is ignored
+// site - the site to modify
+// profile - the port profile
+func (c *Client) CreatePortProfile(ctx context.Context, site string, profile PortProfile, opts ...RequestOption) (*PortProfileResponse, error) {
+	profile.ID = ""
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PortProfileResponse
+	err = c.doSiteRequest(ctx, http.MethodPost, site, "rest/portconf", bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// UpdatePortProfile validates and replaces an existing switch port profile
+// site - the site to modify
+// profile - the port profile, its ID selects the profile to update
+func (c *Client) UpdatePortProfile(ctx context.Context, site string, profile PortProfile, opts ...RequestOption) (*PortProfileResponse, error) {
+	if profile.ID == "" {
+		return nil, fmt.Errorf("port profile ID is required")
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PortProfileResponse
+	err = c.doSiteRequest(ctx, http.MethodPut, site, "rest/portconf/"+strings.TrimSpace(profile.ID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// DeletePortProfile deletes an existing switch port profile, the built-in profiles can not be deleted
+// site - the site to modify
+// profileID - the ID of the port profile
+func (c *Client) DeletePortProfile(ctx context.Context, site string, profileID string, opts ...RequestOption) (*GenericResponse, error) {
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, "rest/portconf/"+strings.TrimSpace(profileID), nil, &resp, opts...)
+	return &resp, err
+}
+
+// PortOverride overrides the configuration of a single switch port.
+// Empty fields are left unchanged when the override is applied with SetPortOverride.
+type PortOverride struct {
+	PortIdx       int     `json:"port_idx"`
+	Name          string  `json:"name,omitempty"`
+	PortProfileID string  `json:"portconf_id,omitempty"`
+	POEMode       PoEMode `json:"poe_mode,omitempty"`
+	OpMode        string  `json:"op_mode,omitempty"` // switch, mirror or aggregate
+}
+
+// devicePortOverrides is the part of a device needed to update its port overrides
+type devicePortOverrides struct {
+	ID            string                   `json:"_id"`
+	MAC           string                   `json:"mac"`
+	PortOverrides []map[string]interface{} `json:"port_overrides"`
+}
+
+// loadPortOverrides returns the device with its raw port overrides, so fields unknown to PortOverride are kept
+func (c *Client) loadPortOverrides(ctx context.Context, site string, mac string) (*devicePortOverrides, error) {
+	var resp struct {
+		Meta CommonMeta            `json:"meta"`
+		Data []devicePortOverrides `json:"data"`
+	}
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/device/"+strings.ToLower(strings.TrimSpace(mac)), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Data {
+		if strings.EqualFold(resp.Data[i].MAC, mac) {
+			return &resp.Data[i], nil
+		}
+	}
+	return nil, errors.Wrapf(ErrNotFound, "device %s", mac)
+}
+
+// storePortOverrides replaces the port overrides of the device, sorted by port
+func (c *Client) storePortOverrides(ctx context.Context, site string, device *devicePortOverrides, opts ...RequestOption) (*GenericResponse, error) {
+	sort.SliceStable(device.PortOverrides, func(i, j int) bool {
+		return portIndex(device.PortOverrides[i]) < portIndex(device.PortOverrides[j])
+	})
+	overrides := device.PortOverrides
+	if overrides == nil {
+		overrides = []map[string]interface{}{}
+	}
+	data, _ := json.Marshal(map[string]interface{}{"port_overrides": overrides})
+
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodPut, site, "rest/device/"+device.ID, bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// portIndex returns the port_idx of a raw port override
+func portIndex(override map[string]interface{}) int {
+	idx, _ := override["port_idx"].(float64)
+	return int(idx)
+}
+
+// updatePortOverride applies update to the raw override of the port, creating it if needed
+func (c *Client) updatePortOverride(ctx context.Context, site string, mac string, portIdx int, update func(override map[string]interface{}), opts ...RequestOption) (*GenericResponse, error) {
+	if portIdx < 1 {
+		return nil, fmt.Errorf("invalid port index %d", portIdx)
+	}
+	device, err := c.loadPortOverrides(ctx, site, mac)
+	if err != nil {
+		return nil, err
+	}
+	var override map[string]interface{}
+	for _, o := range device.PortOverrides {
+		if portIndex(o) == portIdx {
+			override = o
+			break
+		}
+	}
+	if override == nil {
+		override = map[string]interface{}{"port_idx": float64(portIdx)}
+		device.PortOverrides = append(device.PortOverrides, override)
+	}
+	update(override)
+	return c.storePortOverrides(ctx, site, device, opts...)
+}
+
+// SetPortOverride merges the override into the port overrides of a switch, the other ports are left unchanged.
+// The overrides are read and written back as a whole, concurrent changes to the same switch may be lost.
+// The request options of the port methods apply to writing the overrides back only.
+// site - the site of the switch
+// mac - the switch mac
+// override - the override, PortIdx selects the port
+func (c *Client) SetPortOverride(ctx context.Context, site string, mac string, override PortOverride, opts ...RequestOption) (*GenericResponse, error) {
+	if override.POEMode != "" && !override.POEMode.IsValid() {
+		return nil, fmt.Errorf("invalid poe mode specified: %s", override.POEMode)
+	}
+	data, _ := json.Marshal(override)
+	var fields map[string]interface{}
+	_ = json.Unmarshal(data, &fields)
+	return c.updatePortOverride(ctx, site, mac, override.PortIdx, func(o map[string]interface{}) {
+		for k, v := range fields {
+			o[k] = v
+		}
+	}, opts...)
+}
+
+// ClearPortOverride removes the override of a switch port, the port reverts to the switch defaults
+// site - the site of the switch
+// mac - the switch mac
+// portIdx - the port to reset
+func (c *Client) ClearPortOverride(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error) {
+	device, err := c.loadPortOverrides(ctx, site, mac)
+	if err != nil {
+		return nil, err
+	}
+	overrides := device.PortOverrides[:0]
+	for _, o := range device.PortOverrides {
+		if portIndex(o) != portIdx {
+			overrides = append(overrides, o)
+		}
+	}
+	device.PortOverrides = overrides
+	return c.storePortOverrides(ctx, site, device, opts...)
+}
+
+// SetPortProfile assigns a port profile to a switch port
+// site - the site of the switch
+// mac - the switch mac
+// portIdx - the port to configure
+// profileID - the ID of the port profile
+func (c *Client) SetPortProfile(ctx context.Context, site string, mac string, portIdx int, profileID string, opts ...RequestOption) (*GenericResponse, error) {
+	return c.SetPortOverride(ctx, site, mac, PortOverride{PortIdx: portIdx, PortProfileID: profileID}, opts...)
+}
+
+// SetPortPoEMode sets the PoE mode of a switch port, like PoEModeOff or PoEModeAuto
+// site - the site of the switch
+// mac - the switch mac
+// portIdx - the port to configure
+// mode - the PoE mode
+func (c *Client) SetPortPoEMode(ctx context.Context, site string, mac string, portIdx int, mode PoEMode, opts ...RequestOption) (*GenericResponse, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid poe mode specified: %s", mode)
+	}
+	return c.SetPortOverride(ctx, site, mac, PortOverride{PortIdx: portIdx, POEMode: mode}, opts...)
+}
+
+// isBuiltinDisabledProfile returns true for the built-in Disabled profile, user profiles that disable the port
+// forwarding may set other fields, like the PoE mode, and are never picked
+func isBuiltinDisabledProfile(profile PortProfile) bool {
+	return profile.AttrNoDelete && profile.Forward == PortForwardingDisabled
+}
+
+// DisablePort disables a switch port by assigning the built-in Disabled profile, the profile with the disabled
+// port forwarding that can not be deleted
+// site - the site of the switch
+// mac - the switch mac
+// portIdx - the port to disable
+func (c *Client) DisablePort(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error) {
+	profiles, err := c.ListPortProfiles(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles.Data {
+		if isBuiltinDisabledProfile(profile) {
+			return c.SetPortProfile(ctx, site, mac, portIdx, profile.ID, opts...)
+		}
+	}
+	return nil, errors.Wrap(ErrNotFound, "no built-in port profile with disabled port forwarding")
+}
+
+// EnablePort enables a switch port disabled with DisablePort, the port reverts to the default profile.
+// A port assigned a user profile is left unchanged, even if the profile disables the port forwarding.
+// site - the site of the switch
+// mac - the switch mac
+// portIdx - the port to enable
+func (c *Client) EnablePort(ctx context.Context, site string, mac string, portIdx int, opts ...RequestOption) (*GenericResponse, error) {
+	profiles, err := c.ListPortProfiles(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool)
+	for _, profile := range profiles.Data {
+		if isBuiltinDisabledProfile(profile) {
+			disabled[profile.ID] = true
+		}
+	}
+	return c.updatePortOverride(ctx, site, mac, portIdx, func(o map[string]interface{}) {
+		if id, _ := o["portconf_id"].(string); disabled[id] {
+			delete(o, "portconf_id")
+		}
+	}, opts...)
+}
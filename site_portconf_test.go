@@ -0,0 +1,252 @@
+package unifi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// switchOverrides are the port overrides of the test switch, with fields unknown to unifi.PortOverride
+const switchOverrides = `[
+	{"port_idx": 1, "name": "uplink", "portconf_id": "all", "stp_port_mode": false, "lldpmed": {"notify": true}},
+	{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+]`
+
+func TestPortOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		apply   func(ctx context.Context, client *unifi.Client) error
+		want    string
+		wantErr string
+	}{
+		{
+			name: "poe mode keeps unknown fields",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.SetPortPoEMode(ctx, "default", "F0:9F:C2:00:00:01", 1, unifi.PoEModeOff)
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "all", "stp_port_mode": false, "lldpmed": {"notify": true}, "poe_mode": "off"},
+				{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "override merges only the set fields",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.SetPortOverride(ctx, "default", "f0:9f:c2:00:00:01", unifi.PortOverride{PortIdx: 3, Name: "camera", OpMode: "switch"})
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "all", "stp_port_mode": false, "lldpmed": {"notify": true}},
+				{"port_idx": 3, "name": "camera", "op_mode": "switch", "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "new override is sorted by port",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.SetPortProfile(ctx, "default", "f0:9f:c2:00:00:01", 2, "all")
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "all", "stp_port_mode": false, "lldpmed": {"notify": true}},
+				{"port_idx": 2, "portconf_id": "all"},
+				{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "clear removes only the port",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.ClearPortOverride(ctx, "default", "f0:9f:c2:00:00:01", 1)
+				return err
+			},
+			want: `[
+				{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "disable assigns the disabled profile",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.DisablePort(ctx, "default", "f0:9f:c2:00:00:01", 1)
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "disabled", "stp_port_mode": false, "lldpmed": {"notify": true}},
+				{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "enable removes only the disabled profile",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.EnablePort(ctx, "default", "f0:9f:c2:00:00:01", 3)
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "all", "stp_port_mode": false, "lldpmed": {"notify": true}},
+				{"port_idx": 3, "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "enable keeps user profiles disabling the port",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				if _, err := client.SetPortProfile(ctx, "default", "f0:9f:c2:00:00:01", 1, "poe-off"); err != nil {
+					return err
+				}
+				_, err := client.EnablePort(ctx, "default", "f0:9f:c2:00:00:01", 1)
+				return err
+			},
+			want: `[
+				{"port_idx": 1, "name": "uplink", "portconf_id": "poe-off", "stp_port_mode": false, "lldpmed": {"notify": true}},
+				{"port_idx": 3, "portconf_id": "disabled", "poe_mode": "auto", "aggregate_num_ports": 2}
+			]`,
+		},
+		{
+			name: "enable keeps other profiles",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.EnablePort(ctx, "default", "f0:9f:c2:00:00:01", 1)
+				return err
+			},
+			want: switchOverrides,
+		},
+		{
+			name: "invalid poe mode",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.SetPortPoEMode(ctx, "default", "f0:9f:c2:00:00:01", 1, "48v")
+				return err
+			},
+			want:    switchOverrides,
+			wantErr: "invalid poe mode specified: 48v",
+		},
+		{
+			name: "unknown switch",
+			apply: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.SetPortPoEMode(ctx, "default", "f0:9f:c2:00:00:02", 1, unifi.PoEModeOff)
+				return err
+			},
+			want:    switchOverrides,
+			wantErr: "device f0:9f:c2:00:00:02: not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+			srv.Add("default", "portconf", unifitest.Object{"_id": "all", "name": "All", "forward": "all", "attr_no_delete": true})
+			// a user profile disabling the port forwarding, listed before the built-in one
+			srv.Add("default", "portconf", unifitest.Object{"_id": "poe-off", "name": "PoE off", "forward": "disabled", "poe_mode": "off"})
+			srv.Add("default", "portconf", unifitest.Object{"_id": "disabled", "name": "Disabled", "forward": "disabled", "attr_no_delete": true})
+			srv.AddDevice("default", unifitest.Object{
+				"mac": "f0:9f:c2:00:00:01", "type": "usw", "port_overrides": decodeJSON(t, switchOverrides),
+			})
+
+			ctx := context.Background()
+			client, err := srv.Client(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.apply(ctx, client)
+			if (err != nil || tt.wantErr != "") && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+
+			devices := srv.Objects("default", unifitest.CollectionDevices)
+			got := decodeJSON(t, encodeJSON(t, devices[0]["port_overrides"]))
+			if want := decodeJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected port overrides\ngot  %s\nwant %s", encodeJSON(t, got), encodeJSON(t, want))
+			}
+		})
+	}
+}
+
+func TestPortOverrideRequestOptions(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.Add("default", "portconf", unifitest.Object{"_id": "disabled", "name": "Disabled", "forward": "disabled", "attr_no_delete": true})
+	srv.AddDevice("default", unifitest.Object{"mac": "f0:9f:c2:00:00:01", "type": "usw", "port_overrides": decodeJSON(t, switchOverrides)})
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		apply func(opt unifi.RequestOption) error
+	}{
+		{name: "override", apply: func(opt unifi.RequestOption) error {
+			_, err := client.SetPortOverride(ctx, "default", "f0:9f:c2:00:00:01", unifi.PortOverride{PortIdx: 2, Name: "camera"}, opt)
+			return err
+		}},
+		{name: "clear", apply: func(opt unifi.RequestOption) error {
+			_, err := client.ClearPortOverride(ctx, "default", "f0:9f:c2:00:00:01", 2, opt)
+			return err
+		}},
+		{name: "profile", apply: func(opt unifi.RequestOption) error {
+			_, err := client.SetPortProfile(ctx, "default", "f0:9f:c2:00:00:01", 2, "disabled", opt)
+			return err
+		}},
+		{name: "poe mode", apply: func(opt unifi.RequestOption) error {
+			_, err := client.SetPortPoEMode(ctx, "default", "f0:9f:c2:00:00:01", 2, unifi.PoEModeOff, opt)
+			return err
+		}},
+		{name: "disable", apply: func(opt unifi.RequestOption) error {
+			_, err := client.DisablePort(ctx, "default", "f0:9f:c2:00:00:01", 2, opt)
+			return err
+		}},
+		{name: "enable", apply: func(opt unifi.RequestOption) error {
+			_, err := client.EnablePort(ctx, "default", "f0:9f:c2:00:00:01", 2, opt)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.apply(unifi.Header("X-Change", tt.name)); err != nil {
+				t.Fatal(err)
+			}
+			requests := srv.Requests()
+			last := requests[len(requests)-1]
+			if last.Method != http.MethodPut || last.Header.Get("X-Change") != tt.name {
+				t.Fatalf("expected the options to apply to the update, got %s %s with %q", last.Method, last.Path, last.Header.Get("X-Change"))
+			}
+		})
+	}
+}
+
+func TestDisablePortRequiresBuiltinProfile(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	srv.Add("default", "portconf", unifitest.Object{"_id": "poe-off", "name": "PoE off", "forward": "disabled", "poe_mode": "off"})
+	srv.AddDevice("default", unifitest.Object{"mac": "f0:9f:c2:00:00:01", "type": "usw", "port_overrides": decodeJSON(t, switchOverrides)})
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DisablePort(ctx, "default", "f0:9f:c2:00:00:01", 1); !errors.Is(err, unifi.ErrNotFound) {
+		t.Fatalf("expected the user profile to be ignored, got %v", err)
+	}
+}
+
+func encodeJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func decodeJSON(t *testing.T, data string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,349 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPortForwardConflict indicates a port forward overlaps the external ports of an enabled port forward
+var ErrPortForwardConflict = fmt.Errorf("conflicting port forward")
+
+// PortForwardProtocol is the protocol forwarded by a port forward
+type PortForwardProtocol string
+
+// The port forward protocols
+const (
+	PortForwardProtocolTCPUDP PortForwardProtocol = "tcp_udp"
+	PortForwardProtocolTCP    PortForwardProtocol = "tcp"
+	PortForwardProtocolUDP    PortForwardProtocol = "udp"
+)
+
+// IsValid returns true if it's a valid port forward protocol.
+func (p PortForwardProtocol) IsValid() bool {
+	switch p {
+	case PortForwardProtocolTCPUDP, PortForwardProtocolTCP, PortForwardProtocolUDP:
+		return true
+	default:
+		return false
+	}
+}
+
+// overlaps returns true if both protocols forward tcp or udp
+func (p PortForwardProtocol) overlaps(other PortForwardProtocol) bool {
+	return p == other || p == PortForwardProtocolTCPUDP || other == PortForwardProtocolTCPUDP
+}
+
+// The WAN interfaces a port forward listens on
+const (
+	PortForwardInterfaceWAN  = "wan"
+	PortForwardInterfaceWAN2 = "wan2"
+	PortForwardInterfaceBoth = "both"
+)
+
+// PortForward is a typed port forward of rest/portforward
+type PortForward struct {
+	ID            string              `json:"_id,omitempty"`
+	SiteID        string              `json:"site_id,omitempty"`
+	Name          string              `json:"name"`
+	Enabled       bool                `json:"enabled"`
+	Src           string              `json:"src"`      // any, or the address or subnet allowed to connect
+	DstPort       string              `json:"dst_port"` // external port, range or list like 80, 8000-8080 or 80,443
+	Fwd           string              `json:"fwd"`      // internal address to forward to
+	FwdPort       string              `json:"fwd_port"` // internal port, a range must be as long as the external range
+	Protocol      PortForwardProtocol `json:"proto"`
+	WANInterface  string              `json:"pfwd_interface,omitempty"` // wan, wan2 or both, the controller default is wan
+	Logging       bool                `json:"log"`
+	DestinationIP string              `json:"destination_ip,omitempty"` // the wan address to listen on, any by default
+}
+
+// portRange is an inclusive port range
+type portRange struct {
+	from int
+	to   int
+}
+
+// parsePorts parses a port, range or comma separated list of both
+func parsePorts(spec string) ([]portRange, error) {
+	var ranges []portRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", part)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid port range %q", part)
+			}
+		}
+		if from < 1 || to > 65535 || from > to {
+			return nil, fmt.Errorf("invalid port range %q, ports must be ordered and between 1 and 65535", part)
+		}
+		ranges = append(ranges, portRange{from: from, to: to})
+	}
+	return ranges, nil
+}
+
+// portsOverlap returns true if any of the ranges overlap
+func portsOverlap(a []portRange, b []portRange) bool {
+	for _, ra := range a {
+		for _, rb := range b {
+			if ra.from <= rb.to && rb.from <= ra.to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSource returns the network of a source restriction, nil for any
+func parseSource(src string) (*net.IPNet, error) {
+	if src == "" || src == "any" {
+		return nil, nil
+	}
+	if !strings.Contains(src, "/") {
+		ip := net.ParseIP(src)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid source %q, expected any, an address or a subnet", src)
+		}
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source %q, expected any, an address or a subnet", src)
+	}
+	return ipNet, nil
+}
+
+// Validate checks the port forward before it is sent to the controller
+func (p *PortForward) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("port forward name is required")
+	}
+	if !p.Protocol.IsValid() {
+		return fmt.Errorf("invalid port forward protocol specified: %s", p.Protocol)
+	}
+	switch p.WANInterface {
+	case "", PortForwardInterfaceWAN, PortForwardInterfaceWAN2, PortForwardInterfaceBoth:
+	default:
+		return fmt.Errorf("invalid port forward interface specified: %s", p.WANInterface)
+	}
+	if _, err := parseSource(p.Src); err != nil {
+		return err
+	}
+	if ip := net.ParseIP(p.Fwd); ip == nil || ip.To4() == nil {
+		return fmt.Errorf("invalid forward address %q", p.Fwd)
+	}
+
+	dst, err := parsePorts(p.DstPort)
+	if err != nil {
+		return errors.Wrap(err, "invalid external port")
+	}
+	fwd, err := parsePorts(p.FwdPort)
+	if err != nil {
+		return errors.Wrap(err, "invalid forward port")
+	}
+	if len(fwd) != 1 && len(fwd) != len(dst) {
+		return fmt.Errorf("forward port %q does not match the external port %q", p.FwdPort, p.DstPort)
+	}
+	for i := range fwd {
+		if fwd[i].to-fwd[i].from == 0 {
+			// a single port receives every external port
+			continue
+		}
+		if len(fwd) != len(dst) || fwd[i].to-fwd[i].from != dst[i].to-dst[i].from {
+			return fmt.Errorf("forward port range %q must be as long as the external range %q", p.FwdPort, p.DstPort)
+		}
+	}
+	for i := range dst {
+		for j := i + 1; j < len(dst); j++ {
+			if portsOverlap(dst[i:i+1], dst[j:j+1]) {
+				return fmt.Errorf("external port %q overlaps itself", p.DstPort)
+			}
+		}
+	}
+	return nil
+}
+
+// conflicts returns true if both port forwards are enabled and forward the same external ports
+func (p *PortForward) conflicts(other *PortForward) bool {
+	if !p.Enabled || !other.Enabled || (p.ID != "" && p.ID == other.ID) {
+		return false
+	}
+	if !p.Protocol.overlaps(other.Protocol) {
+		return false
+	}
+	wan, otherWAN := p.WANInterface, other.WANInterface
+	if wan == "" {
+		wan = PortForwardInterfaceWAN
+	}
+	if otherWAN == "" {
+		otherWAN = PortForwardInterfaceWAN
+	}
+	if wan != otherWAN && wan != PortForwardInterfaceBoth && otherWAN != PortForwardInterfaceBoth {
+		return false
+	}
+	if p.DestinationIP != "" && other.DestinationIP != "" && p.DestinationIP != other.DestinationIP {
+		return false
+	}
+	src, _ := parseSource(p.Src)
+	otherSrc, _ := parseSource(other.Src)
+	if src != nil && otherSrc != nil && !src.Contains(otherSrc.IP) && !otherSrc.Contains(src.IP) {
+		return false
+	}
+	dst, err := parsePorts(p.DstPort)
+	if err != nil {
+		return false
+	}
+	otherDst, err := parsePorts(other.DstPort)
+	if err != nil {
+		// unparsable rules from the controller can not be checked
+		return false
+	}
+	return portsOverlap(dst, otherDst)
+}
+
+// checkPortForwardConflicts rejects the port forward if it overlaps an enabled port forward of the site
+func (c *Client) checkPortForwardConflicts(ctx context.Context, site string, forward *PortForward) error {
+	if !forward.Enabled {
+		return nil
+	}
+	resp, err := c.ListPortForwards(ctx, site)
+	if err != nil {
+		return errors.Wrap(err, "unable to check for conflicting port forwards")
+	}
+	for i := range resp.Data {
+		existing := &resp.Data[i]
+		if forward.conflicts(existing) {
+			return errors.Wrapf(ErrPortForwardConflict, "%s port %s overlaps port forward %q (%s port %s)",
+				forward.Protocol, forward.DstPort, existing.Name, existing.Protocol, existing.DstPort)
+		}
+	}
+	return nil
+}
+
+// PortForwardResponse contains the port forwards response
+type PortForwardResponse struct {
+	Meta CommonMeta    `json:"meta"`
+	Data []PortForward `json:"data"`
+}
+
+// ListPortForwards lists the port forwards of the site
+// site - the site to query
+func (c *Client) ListPortForwards(ctx context.Context, site string, opts ...RequestOption) (*PortForwardResponse, error) {
+	var resp PortForwardResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/portforward", nil, &resp, opts...)
+	return &resp, err
+}
+
+// GetPortForward gets a single port forward
+// site - the site to query
+// forwardID - the ID of the port forward
+func (c *Client) GetPortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForward, error) {
+	var resp PortForwardResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/portforward/"+strings.TrimSpace(forwardID), nil, &resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "port forward %s", forwardID)
+	}
+	return &resp.Data[0], nil
+}
+
+// CreatePortForward validates and creates a new port forward, the ID of the port forward is ignored.
+// It fails with ErrPortForwardConflict if an enabled port forward already uses the external ports.
+// site - the site to modify
+// forward - the port forward
+func (c *Client) CreatePortForward(ctx context.Context, site string, forward PortForward, opts ...RequestOption) (*PortForwardResponse, error) {
+	forward.ID = ""
+	if err := forward.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkPortForwardConflicts(ctx, site, &forward); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(forward)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PortForwardResponse
+	err = c.doSiteRequest(ctx, http.MethodPost, site, "rest/portforward", bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// UpdatePortForward validates and replaces an existing port forward.
+// It fails with ErrPortForwardConflict if another enabled port forward already uses the external ports.
+// site - the site to modify
+// forward - the port forward, its ID selects the port forward to update
+func (c *Client) UpdatePortForward(ctx context.Context, site string, forward PortForward, opts ...RequestOption) (*PortForwardResponse, error) {
+	if forward.ID == "" {
+		return nil, fmt.Errorf("port forward ID is required")
+	}
+	if err := forward.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkPortForwardConflicts(ctx, site, &forward); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(forward)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PortForwardResponse
+	err = c.doSiteRequest(ctx, http.MethodPut, site, "rest/portforward/"+strings.TrimSpace(forward.ID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// DeletePortForward deletes an existing port forward
+// site - the site to modify
+// forwardID - the ID of the port forward
+func (c *Client) DeletePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*GenericResponse, error) {
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, "rest/portforward/"+strings.TrimSpace(forwardID), nil, &resp, opts...)
+	return &resp, err
+}
+
+// EnablePortForward enables a port forward, it fails with ErrPortForwardConflict if another enabled port forward
+// uses the external ports in the meantime
+// site - the site to modify
+// forwardID - the ID of the port forward
+func (c *Client) EnablePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForwardResponse, error) {
+	forward, err := c.GetPortForward(ctx, site, forwardID)
+	if err != nil {
+		return nil, err
+	}
+	forward.Enabled = true
+	if err := c.checkPortForwardConflicts(ctx, site, forward); err != nil {
+		return nil, err
+	}
+	return c.setPortForwardEnabled(ctx, site, forwardID, true, opts...)
+}
+
+// DisablePortForward disables a port forward without deleting it
+// site - the site to modify
+// forwardID - the ID of the port forward
+func (c *Client) DisablePortForward(ctx context.Context, site string, forwardID string, opts ...RequestOption) (*PortForwardResponse, error) {
+	return c.setPortForwardEnabled(ctx, site, forwardID, false, opts...)
+}
+
+func (c *Client) setPortForwardEnabled(ctx context.Context, site string, forwardID string, enabled bool, opts ...RequestOption) (*PortForwardResponse, error) {
+	data, _ := json.Marshal(map[string]interface{}{"enabled": enabled})
+
+	var resp PortForwardResponse
+	err := c.doSiteRequest(ctx, http.MethodPut, site, "rest/portforward/"+strings.TrimSpace(forwardID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
@@ -0,0 +1,125 @@
+package unifi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePorts(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []portRange
+		wantErr bool
+	}{
+		{spec: "80", want: []portRange{{80, 80}}},
+		{spec: "8000-8080", want: []portRange{{8000, 8080}}},
+		{spec: "80,443", want: []portRange{{80, 80}, {443, 443}}},
+		{spec: " 80 , 8000 - 8010 ", want: []portRange{{80, 80}, {8000, 8010}}},
+		{spec: "1-65535", want: []portRange{{1, 65535}}},
+		{spec: "", wantErr: true},
+		{spec: "http", wantErr: true},
+		{spec: "0", wantErr: true},
+		{spec: "65536", wantErr: true},
+		{spec: "8080-80", wantErr: true},
+		{spec: "80-", wantErr: true},
+		{spec: "80,,443", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parsePorts(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePorts(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parsePorts(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortForwardValidate(t *testing.T) {
+	valid := PortForward{Name: "web", Src: "any", DstPort: "443", Fwd: "192.168.1.10", FwdPort: "443", Protocol: PortForwardProtocolTCP}
+	tests := []struct {
+		name    string
+		modify  func(p *PortForward)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *PortForward) {}},
+		{name: "missing name", modify: func(p *PortForward) { p.Name = " " }, wantErr: true},
+		{name: "invalid protocol", modify: func(p *PortForward) { p.Protocol = "icmp" }, wantErr: true},
+		{name: "wan2", modify: func(p *PortForward) { p.WANInterface = PortForwardInterfaceWAN2 }},
+		{name: "both wans", modify: func(p *PortForward) { p.WANInterface = PortForwardInterfaceBoth }},
+		{name: "invalid interface", modify: func(p *PortForward) { p.WANInterface = "wan3" }, wantErr: true},
+		{name: "source address", modify: func(p *PortForward) { p.Src = "198.51.100.7" }},
+		{name: "source subnet", modify: func(p *PortForward) { p.Src = "198.51.100.0/24" }},
+		{name: "invalid source", modify: func(p *PortForward) { p.Src = "example.com" }, wantErr: true},
+		{name: "ipv6 forward address", modify: func(p *PortForward) { p.Fwd = "fd00::10" }, wantErr: true},
+		{name: "ranges of equal length", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010", "9000-9010" }},
+		{name: "ranges of different length", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010", "9000-9005" }, wantErr: true},
+		{name: "single forward port for a range", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010", "80" }},
+		{name: "single forward port for a list", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "80,443", "8443" }},
+		{name: "single forward port for a multi range list", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010,9000-9010", "80" }},
+		{name: "forward range for a multi range list", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010,9000-9010", "7000-7010" }, wantErr: true},
+		{name: "lists of matching ranges", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010,443", "7000-7010,8443" }},
+		{name: "lists of different length", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "80,443,8080", "80,443" }, wantErr: true},
+		{name: "external ports overlap themselves", modify: func(p *PortForward) { p.DstPort, p.FwdPort = "8000-8010,8005", "80" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPortForwardConflicts(t *testing.T) {
+	existing := PortForward{ID: "a", Name: "web", Enabled: true, Src: "any", DstPort: "8000-8010", Fwd: "192.168.1.10", FwdPort: "8000-8010", Protocol: PortForwardProtocolTCP}
+	tests := []struct {
+		name   string
+		modify func(existing *PortForward, p *PortForward)
+		want   bool
+	}{
+		{name: "same port", modify: func(e *PortForward, p *PortForward) { p.DstPort = "8005" }, want: true},
+		{name: "range overlaps a list", modify: func(e *PortForward, p *PortForward) { p.DstPort = "80,8010,9000" }, want: true},
+		{name: "list between the range", modify: func(e *PortForward, p *PortForward) { p.DstPort = "80,7999,8011" }},
+		{name: "ranges overlap", modify: func(e *PortForward, p *PortForward) { p.DstPort = "7990-8000" }, want: true},
+		{name: "adjacent ranges", modify: func(e *PortForward, p *PortForward) { p.DstPort = "8011-8020" }},
+		{name: "tcp against udp", modify: func(e *PortForward, p *PortForward) { p.Protocol = PortForwardProtocolUDP }},
+		{name: "tcp against tcp_udp", modify: func(e *PortForward, p *PortForward) { p.Protocol = PortForwardProtocolTCPUDP }, want: true},
+		{name: "udp against tcp_udp", modify: func(e *PortForward, p *PortForward) {
+			e.Protocol, p.Protocol = PortForwardProtocolTCPUDP, PortForwardProtocolUDP
+		}, want: true},
+		{name: "wan against default wan", modify: func(e *PortForward, p *PortForward) { p.WANInterface = PortForwardInterfaceWAN }, want: true},
+		{name: "wan against wan2", modify: func(e *PortForward, p *PortForward) { p.WANInterface = PortForwardInterfaceWAN2 }},
+		{name: "both against wan", modify: func(e *PortForward, p *PortForward) { p.WANInterface = PortForwardInterfaceBoth }, want: true},
+		{name: "wan2 against both", modify: func(e *PortForward, p *PortForward) {
+			e.WANInterface, p.WANInterface = PortForwardInterfaceBoth, PortForwardInterfaceWAN2
+		}, want: true},
+		{name: "different destination addresses", modify: func(e *PortForward, p *PortForward) {
+			e.DestinationIP, p.DestinationIP = "198.51.100.1", "198.51.100.2"
+		}},
+		{name: "any destination address", modify: func(e *PortForward, p *PortForward) { p.DestinationIP = "198.51.100.2" }, want: true},
+		{name: "disjoint sources", modify: func(e *PortForward, p *PortForward) { e.Src, p.Src = "10.0.0.0/24", "10.0.1.0/24" }},
+		{name: "nested sources", modify: func(e *PortForward, p *PortForward) { e.Src, p.Src = "10.0.0.0/16", "10.0.1.0/24" }, want: true},
+		{name: "source address in subnet", modify: func(e *PortForward, p *PortForward) { e.Src, p.Src = "10.0.0.0/24", "10.0.0.7" }, want: true},
+		{name: "any source against a subnet", modify: func(e *PortForward, p *PortForward) { e.Src = "10.0.0.0/24" }, want: true},
+		{name: "disabled existing", modify: func(e *PortForward, p *PortForward) { e.Enabled = false }},
+		{name: "disabled new", modify: func(e *PortForward, p *PortForward) { p.Enabled = false }},
+		{name: "same port forward", modify: func(e *PortForward, p *PortForward) { p.ID = e.ID }},
+		{name: "unparsable existing", modify: func(e *PortForward, p *PortForward) { e.DstPort = "web" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := existing
+			p := existing
+			p.ID, p.Name = "", "new"
+			tt.modify(&e, &p)
+			if got := p.conflicts(&e); got != tt.want {
+				t.Fatalf("conflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
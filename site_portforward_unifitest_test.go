@@ -0,0 +1,78 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+func TestPortForwardRoundTrip(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forward := unifi.PortForward{
+		Name: "game", Enabled: true, Src: "198.51.100.0/24", DstPort: "27015-27030,27036", Fwd: "192.168.1.57",
+		FwdPort: "27015-27030,27036", Protocol: unifi.PortForwardProtocolTCPUDP, WANInterface: unifi.PortForwardInterfaceBoth, Logging: true,
+	}
+	created, err := client.CreatePortForward(ctx, "default", forward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Data) != 1 || created.Data[0].ID == "" {
+		t.Fatalf("unexpected create response %+v", created.Data)
+	}
+	id := created.Data[0].ID
+
+	got, err := client.GetPortForward(ctx, "default", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forward.ID, forward.SiteID = got.ID, got.SiteID
+	if *got != forward {
+		t.Fatalf("port forward did not round trip:\ngot  %+v\nwant %+v", *got, forward)
+	}
+
+	// udp on wan2 overlaps the tcp_udp forward on both wans
+	_, err = client.CreatePortForward(ctx, "default", unifi.PortForward{
+		Name: "voice", Enabled: true, Src: "any", DstPort: "27036", Fwd: "192.168.1.58", FwdPort: "27036",
+		Protocol: unifi.PortForwardProtocolUDP, WANInterface: unifi.PortForwardInterfaceWAN2,
+	})
+	if !errors.Is(err, unifi.ErrPortForwardConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	got.DstPort, got.FwdPort = "27015-27030", "27015-27030"
+	if _, err := client.UpdatePortForward(ctx, "default", *got); err != nil {
+		t.Fatalf("updating a port forward must not conflict with itself: %v", err)
+	}
+	if _, err := client.DisablePortForward(ctx, "default", id); err != nil {
+		t.Fatal(err)
+	}
+	got, err = client.GetPortForward(ctx, "default", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled || got.DstPort != "27015-27030" || got.Name != "game" {
+		t.Fatalf("unexpected port forward after update %+v", *got)
+	}
+
+	if _, err := client.DeletePortForward(ctx, "default", id); err != nil {
+		t.Fatal(err)
+	}
+	forwards, err := client.ListPortForwards(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forwards.Data) != 0 {
+		t.Fatalf("expected the port forward to be deleted, got %+v", forwards.Data)
+	}
+}
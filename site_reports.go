@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -137,12 +138,17 @@ func (r ReportAttribute) IsValid() bool {
 
 // MarshalJSON implements json.Marshaler
 func (r ReportAttribute) MarshalJSON() ([]byte, error) {
-	return []byte(string(r)), nil
+	return json.Marshal(string(r))
 }
 
 // UnmarshalJSON implements json.Unmarshaler
 func (r *ReportAttribute) UnmarshalJSON(data []byte) error {
-	*r = ReportAttribute(bytes.NewBuffer(data).String())
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	*r = ReportAttribute(s)
 	return nil
 }
 
@@ -154,9 +160,14 @@ func (r *ReportAttribute) UnmarshalJSON(data []byte) error {
 // reportType - the report type requested
 // attributes - attributes to return, see AllReportAttributes for default behavior
 // filterMacs - optional list of macs to filter stats.
-func (c *Client) SiteReport(site string, startTime time.Time, endTime time.Time, interval ReportInterval, reportType ReportType, attributes []ReportAttribute, filterMacs ...string) (*SiteReportsResponse, error) {
+func (c *Client) SiteReport(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval ReportInterval, reportType ReportType, attributes []ReportAttribute, filterMacs ...string) (*SiteReportsResponse, error) {
+	return c.SiteReportWithOptions(ctx, site, startTime, endTime, interval, reportType, attributes, filterMacs)
+}
+
+// SiteReportWithOptions is SiteReport with request options, see SiteReport for the arguments
+func (c *Client) SiteReportWithOptions(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval ReportInterval, reportType ReportType, attributes []ReportAttribute, filterMacs []string, opts ...RequestOption) (*SiteReportsResponse, error) {
 	if startTime.IsZero() && endTime.IsZero() {
-		endTime := time.Now().UTC()
+		endTime = time.Now().UTC()
 		switch interval {
 		case ReportInterval5Min:
 			// set default to last 1h
@@ -166,7 +177,7 @@ func (c *Client) SiteReport(site string, startTime time.Time, endTime time.Time,
 			startTime = endTime.Add(-24 * time.Hour)
 		case ReportIntervalDaily:
 			// set default to last 7 days
-			startTime = endTime.Add(7 * 24 * time.Hour)
+			startTime = endTime.Add(-7 * 24 * time.Hour)
 		}
 	}
 
@@ -202,7 +213,7 @@ func (c *Client) SiteReport(site string, startTime time.Time, endTime time.Time,
 	payload := map[string]interface{}{
 		"attributes": attributes,
 		"start":      startTime.UTC().Unix() * 1000,
-		"end":        startTime.UTC().Unix() * 1000,
+		"end":        endTime.UTC().Unix() * 1000,
 	}
 	if len(filterMacs) > 0 {
 		payload["macs"] = filterMacs
@@ -211,6 +222,6 @@ func (c *Client) SiteReport(site string, startTime time.Time, endTime time.Time,
 	data, _ := json.Marshal(payload)
 
 	var resp SiteReportsResponse
-	err := c.doSiteRequest(http.MethodGet, site, fmt.Sprintf("stat/report/%s.%s", interval, reportType), bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, fmt.Sprintf("stat/report/%s.%s", interval, reportType), bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 )
@@ -43,7 +44,7 @@ type SiteRougeAccessPointResponse struct {
 // SiteRougeAccessPoints will list rouge/neighboring access points
 // site - site to query
 // withinHours - search within the last defined hours, defaults to 24 hours
-func (c *Client) SiteRougeAccessPoints(site string, seenWithinHours int) (*SiteRougeAccessPointResponse, error) {
+func (c *Client) SiteRougeAccessPoints(ctx context.Context, site string, seenWithinHours int, opts ...RequestOption) (*SiteRougeAccessPointResponse, error) {
 	if seenWithinHours < 0 {
 		seenWithinHours = 24
 	}
@@ -54,14 +55,14 @@ func (c *Client) SiteRougeAccessPoints(site string, seenWithinHours int) (*SiteR
 	data, _ := json.Marshal(payload)
 
 	var resp SiteRougeAccessPointResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/rogueap", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/rogueap", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // SiteRougeKnownAccessPoints will list known rouge access points
 // site - site to query
-func (c *Client) SiteRougeKnownAccessPoints(site string) (*SiteRougeAccessPointResponse, error) {
+func (c *Client) SiteRougeKnownAccessPoints(ctx context.Context, site string, opts ...RequestOption) (*SiteRougeAccessPointResponse, error) {
 	var resp SiteRougeAccessPointResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/rougeknown", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/rogueknown", nil, &resp, opts...)
 	return &resp, err
 }
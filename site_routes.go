@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -21,9 +22,9 @@ type SiteActiveRoutesResponse struct {
 
 // SiteActiveRoutes lists active routes for the site
 // site - the site to query
-func (c *Client) SiteActiveRoutes(site string) (*SiteActiveRoutesResponse, error) {
+func (c *Client) SiteActiveRoutes(ctx context.Context, site string, opts ...RequestOption) (*SiteActiveRoutesResponse, error) {
 	var resp SiteActiveRoutesResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/routing", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/routing", nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -38,8 +39,8 @@ type SiteUserDefinedRoutesResponse struct {
 
 // SiteUserDefinedRoutes queries the user defines routes
 // site - the site to query
-func (c *Client) SiteUserDefinedRoutes(site string) (*SiteUserDefinedRoutesResponse, error) {
+func (c *Client) SiteUserDefinedRoutes(ctx context.Context, site string, opts ...RequestOption) (*SiteUserDefinedRoutesResponse, error) {
 	var resp SiteUserDefinedRoutesResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/routing", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/routing", nil, &resp, opts...)
 	return &resp, err
 }
@@ -0,0 +1,77 @@
+package unifi_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+func TestSessionsDefaultToLastHour(t *testing.T) {
+	tests := []struct {
+		name string
+		list func(ctx context.Context, client *unifi.Client) error
+	}{
+		{
+			name: "login sessions",
+			list: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.ListLoginSessions(ctx, "default", unifi.SessionTypeAll, time.Time{}, time.Time{}, "")
+				return err
+			},
+		},
+		{
+			name: "authorizations",
+			list: func(ctx context.Context, client *unifi.Client) error {
+				_, err := client.ListAuthorizations(ctx, "default", time.Time{}, time.Time{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := unifitest.NewServer()
+			defer srv.Close()
+			ctx := context.Background()
+			client, err := srv.Client(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.list(ctx, client); err != nil {
+				t.Fatal(err)
+			}
+			requests := srv.Requests()
+			var payload struct {
+				Start int64 `json:"start"`
+				End   int64 `json:"end"`
+			}
+			if err := json.Unmarshal(requests[len(requests)-1].Body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload.End-payload.Start != time.Hour.Milliseconds() {
+				t.Fatalf("expected an hour between start and end, got %d and %d", payload.Start, payload.End)
+			}
+			if end := time.Unix(payload.End/1000, 0); time.Since(end) > time.Minute {
+				t.Fatalf("expected the range to end now, got %s", end)
+			}
+		})
+	}
+}
+
+func TestSessionsRejectInvertedRange(t *testing.T) {
+	srv := unifitest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	client, err := srv.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if _, err := client.ListAuthorizations(ctx, "default", now, now.Add(-time.Hour)); err == nil {
+		t.Fatal("expected an end before the start to be rejected")
+	}
+}
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -57,8 +58,8 @@ type SiteSysInfoResponse struct {
 
 // SiteSysInfo returns the site system info
 // site - the site to query
-func (c *Client) SiteSysInfo(site string) (*SiteSysInfoResponse, error) {
+func (c *Client) SiteSysInfo(ctx context.Context, site string, opts ...RequestOption) (*SiteSysInfoResponse, error) {
 	var resp SiteSysInfoResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/sysinfo", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/sysinfo", nil, &resp, opts...)
 	return &resp, err
 }
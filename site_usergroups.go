@@ -2,6 +2,7 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,9 +11,9 @@ import (
 
 // ListUserGroups will list all user groups
 // site - site to query
-func (c *Client) ListUserGroups(site string) (*GenericResponse, error) {
+func (c *Client) ListUserGroups(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, "list/usergroup", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "list/usergroup", nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -22,7 +23,7 @@ func (c *Client) ListUserGroups(site string) (*GenericResponse, error) {
 // name - name of the user group
 // downloadBandwidth - limit download bandwidth in Kbps (default -1 == unlimited)
 // uploadBandwidth - limit upload bandwidth in Kbps (default -1 == unlimited)
-func (c *Client) CreateUserGroup(site string, siteID string, name string, downloadBandwidth int, uploadBandwidth int) (*GenericResponse, error) {
+func (c *Client) CreateUserGroup(ctx context.Context, site string, siteID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...RequestOption) (*GenericResponse, error) {
 	if downloadBandwidth <= 0 {
 		downloadBandwidth = -1 // unlimited
 	}
@@ -38,7 +39,7 @@ func (c *Client) CreateUserGroup(site string, siteID string, name string, downlo
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "rest/usergroup", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "rest/usergroup", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
@@ -49,7 +50,7 @@ func (c *Client) CreateUserGroup(site string, siteID string, name string, downlo
 // name - name of the user group
 // downloadBandwidth - limit download bandwidth in Kbps (default -1 == unlimited)
 // uploadBandwidth - limit upload bandwidth in Kbps (default -1 == unlimited)
-func (c *Client) UpdateUserGroup(site string, siteID string, groupID string, name string, downloadBandwidth int, uploadBandwidth int) (*GenericResponse, error) {
+func (c *Client) UpdateUserGroup(ctx context.Context, site string, siteID string, groupID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...RequestOption) (*GenericResponse, error) {
 	if downloadBandwidth <= 0 {
 		downloadBandwidth = -1 // unlimited
 	}
@@ -69,18 +70,18 @@ func (c *Client) UpdateUserGroup(site string, siteID string, groupID string, nam
 	extPath := fmt.Sprintf("rest/usergroup/%s", strings.TrimSpace(groupID))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPut, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPut, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // DeleteUserGroup will delete an existing user group
 // site - site to modify
 // groupID - groupID to modify
-func (c *Client) DeleteUserGroup(site string, groupID string) (*GenericResponse, error) {
+func (c *Client) DeleteUserGroup(ctx context.Context, site string, groupID string, opts ...RequestOption) (*GenericResponse, error) {
 	extPath := fmt.Sprintf("rest/usergroup/%s", strings.TrimSpace(groupID))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodDelete, site, extPath, nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, extPath, nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -88,7 +89,7 @@ func (c *Client) DeleteUserGroup(site string, groupID string) (*GenericResponse,
 // site - the site to modify
 // clientID - the ID of the user/client device to be modified
 // groupID - the ID of the group to assign the user/client device to.
-func (c *Client) AssignClientUserGroup(site string, clientID string, groupID string) (*GenericResponse, error) {
+func (c *Client) AssignClientUserGroup(ctx context.Context, site string, clientID string, groupID string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"usergroup_id": groupID,
 	}
@@ -97,6 +98,6 @@ func (c *Client) AssignClientUserGroup(site string, clientID string, groupID str
 	extPath := fmt.Sprintf("upd/user/%s", strings.TrimSpace(strings.ToLower(clientID)))
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, extPath, bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, extPath, bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
@@ -2,9 +2,10 @@ package unifi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,7 +23,7 @@ type WifiGuestConfig struct {
 // mac - client mac to authorize
 // duration - time for wifi authorization, if <=0 , then it will default to 1hr
 // wifiGuestConfig - optional parameters to limit the client
-func (c *Client) AuthorizeWiFiGuest(site string, mac string, duration time.Duration, wifiGuestConfig *WifiGuestConfig) (*GenericResponse, error) {
+func (c *Client) AuthorizeWiFiGuest(ctx context.Context, site string, mac string, duration time.Duration, wifiGuestConfig *WifiGuestConfig, opts ...RequestOption) (*GenericResponse, error) {
 	if duration.Minutes() <= 0 {
 		duration = time.Hour * 1
 	}
@@ -54,14 +55,14 @@ func (c *Client) AuthorizeWiFiGuest(site string, mac string, duration time.Durat
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/stamgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/stamgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // UnAuthorizeWiFiGuest will unauthorize a WiFi guest
 // site - site to allow the guest
 // mac - client mac to unauthorize
-func (c *Client) UnAuthorizeWiFiGuest(site string, mac string) (*GenericResponse, error) {
+func (c *Client) UnAuthorizeWiFiGuest(ctx context.Context, site string, mac string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "unauthorize-guest",
 		"mac": strings.ToLower(mac),
@@ -70,14 +71,14 @@ func (c *Client) UnAuthorizeWiFiGuest(site string, mac string) (*GenericResponse
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/stamgr", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/stamgr", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ListWiFiGuests will list guest devices with valid access
 // site - site to query
 // withinHours - time frame in hours to list guest devices, default value if zero is 24 hours
-func (c *Client) ListWiFiGuests(site string, withinHours int) (*GenericResponse, error) {
+func (c *Client) ListWiFiGuests(ctx context.Context, site string, withinHours int, opts ...RequestOption) (*GenericResponse, error) {
 	if withinHours <= 0 {
 		withinHours = 24
 	}
@@ -89,14 +90,14 @@ func (c *Client) ListWiFiGuests(site string, withinHours int) (*GenericResponse,
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/guest", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/guest", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ListWiFiGuestVouchers will list wifi guest vouchers
 // site - the site to query
 // createdTime - the create time of the voucher, if zero-value, then it will return all
-func (c *Client) ListWiFiGuestVouchers(site string, createTime time.Time) (*GenericResponse, error) {
+func (c *Client) ListWiFiGuestVouchers(ctx context.Context, site string, createTime time.Time, opts ...RequestOption) (*GenericResponse, error) {
 
 	payload := map[string]interface{}{}
 	if !createTime.IsZero() {
@@ -106,21 +107,19 @@ func (c *Client) ListWiFiGuestVouchers(site string, createTime time.Time) (*Gene
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, "stat/voucher", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/voucher", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ListWiFiGuestPayments will list wifi guest payments
 // site - the site to query
 // withinHours - number of hours to search for history, if zero, then use default 24 hours
-func (c *Client) ListWiFiGuestPayments(site string, withinHours int) (*GenericResponse, error) {
+func (c *Client) ListWiFiGuestPayments(ctx context.Context, site string, withinHours int, opts ...RequestOption) (*GenericResponse, error) {
 	if withinHours <= 0 {
 		withinHours = 24
 	}
-	extPath := fmt.Sprintf("stat/payment?within=%d", withinHours)
-
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, extPath, nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "stat/payment", nil, &resp, append([]RequestOption{QueryParam("within", strconv.Itoa(withinHours))}, opts...)...)
 	return &resp, err
 }
 
@@ -129,7 +128,7 @@ func (c *Client) ListWiFiGuestPayments(site string, withinHours int) (*GenericRe
 // name - the name the new wifi guest operator
 // password - the clear text password for the wifi guest operator
 // note - optional note to attach to the wifi guest operator
-func (c *Client) CreateWifiGuestOperator(site string, name string, password string, note string) (*GenericResponse, error) {
+func (c *Client) CreateWifiGuestOperator(ctx context.Context, site string, name string, password string, note string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"name":     strings.TrimSpace(name),
 		"password": password,
@@ -142,15 +141,15 @@ func (c *Client) CreateWifiGuestOperator(site string, name string, password stri
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "rest/hotspotop", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "rest/hotspotop", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ListWiFiGuestOperators will list wifi guest operators
 // site - the site to query
-func (c *Client) ListWiFiGuestOperators(site string) (*GenericResponse, error) {
+func (c *Client) ListWiFiGuestOperators(ctx context.Context, site string, opts ...RequestOption) (*GenericResponse, error) {
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/hotspotop", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/hotspotop", nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -168,7 +167,7 @@ type VoucherConfig struct {
 // CreateWifiGuestVoucher will create a wifi guest voucher
 // site - the site to create a new wifi guest voucher
 // cfg - voucher creation config
-func (c *Client) CreateWifiGuestVoucher(site string, cfg VoucherConfig) (*GenericResponse, error) {
+func (c *Client) CreateWifiGuestVoucher(ctx context.Context, site string, cfg VoucherConfig, opts ...RequestOption) (*GenericResponse, error) {
 	count := uint(1)
 	if cfg.Count != nil {
 		count = *cfg.Count
@@ -197,14 +196,14 @@ func (c *Client) CreateWifiGuestVoucher(site string, cfg VoucherConfig) (*Generi
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // RevokeWifiGuestVoucher will revoke a guest wifi voucher
 // site - the site to create a revoke wifi guest voucher
 // voucherID - the voucher _id to revoke
-func (c *Client) RevokeWifiGuestVoucher(site string, voucherID string) (*GenericResponse, error) {
+func (c *Client) RevokeWifiGuestVoucher(ctx context.Context, site string, voucherID string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "delete-voucher",
 		"_id": strings.TrimSpace(voucherID),
@@ -212,14 +211,14 @@ func (c *Client) RevokeWifiGuestVoucher(site string, voucherID string) (*Generic
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
 
 // ExtendWifiGuestValidity will extend a guest wifi client
 // site - the site to create a revoke wifi guest voucher
 // guestID - the guest _id to extend validity
-func (c *Client) ExtendWifiGuestValidity(site string, guestID string) (*GenericResponse, error) {
+func (c *Client) ExtendWifiGuestValidity(ctx context.Context, site string, guestID string, opts ...RequestOption) (*GenericResponse, error) {
 	payload := map[string]interface{}{
 		"cmd": "extend",
 		"_id": strings.TrimSpace(guestID),
@@ -227,6 +226,6 @@ func (c *Client) ExtendWifiGuestValidity(site string, guestID string) (*GenericR
 	data, _ := json.Marshal(payload)
 
 	var resp GenericResponse
-	err := c.doSiteRequest(http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp)
+	err := c.doSiteRequest(ctx, http.MethodPost, site, "cmd/hotspot", bytes.NewReader(data), &resp, opts...)
 	return &resp, err
 }
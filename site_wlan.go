@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -15,9 +16,9 @@ type SiteWLANConfigResponse struct {
 
 // SiteWLANConfigs will query the site for WLAN configurations
 // site - the site to query
-func (c *Client) SiteWLANConfigs(site string) (*SiteWLANConfigResponse, error) {
+func (c *Client) SiteWLANConfigs(ctx context.Context, site string, opts ...RequestOption) (*SiteWLANConfigResponse, error) {
 	var resp SiteWLANConfigResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/wlanconf", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/wlanconf", nil, &resp, opts...)
 	return &resp, err
 }
 
@@ -32,8 +33,8 @@ type SiteWLANGroupResponse struct {
 
 // SiteWLANGroups will query the site for WLAN groups
 // site - the site to query
-func (c *Client) SiteWLANGroups(site string) (*SiteWLANGroupResponse, error) {
+func (c *Client) SiteWLANGroups(ctx context.Context, site string, opts ...RequestOption) (*SiteWLANGroupResponse, error) {
 	var resp SiteWLANGroupResponse
-	err := c.doSiteRequest(http.MethodGet, site, "rest/wlangroup", nil, &resp)
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/wlangroup", nil, &resp, opts...)
 	return &resp, err
 }
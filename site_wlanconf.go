@@ -0,0 +1,311 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WLANSecurity is the authentication of a wireless network
+type WLANSecurity string
+
+// The WLAN security modes
+const (
+	WLANSecurityOpen          WLANSecurity = "open"
+	WLANSecurityWPAPersonal   WLANSecurity = "wpapsk" // pre-shared passphrase
+	WLANSecurityWPAEnterprise WLANSecurity = "wpaeap" // 802.1X against a RADIUS profile
+)
+
+// IsValid returns true if it's a valid WLAN security mode.
+func (s WLANSecurity) IsValid() bool {
+	switch s {
+	case WLANSecurityOpen, WLANSecurityWPAPersonal, WLANSecurityWPAEnterprise:
+		return true
+	default:
+		return false
+	}
+}
+
+// The WPA modes
+const (
+	WPAModeWPA2 = "wpa2"
+	WPAModeAuto = "auto" // WPA and WPA2
+)
+
+// The protected management frames modes
+const (
+	PMFModeDisabled = "disabled"
+	PMFModeOptional = "optional"
+	PMFModeRequired = "required"
+)
+
+// The MAC filter policies
+const (
+	MACFilterPolicyAllow = "allow" // only the listed clients may connect
+	MACFilterPolicyDeny  = "deny"  // the listed clients may not connect
+)
+
+// WLANConf is a typed wireless network of rest/wlanconf
+type WLANConf struct {
+	ID      string `json:"_id,omitempty"`
+	SiteID  string `json:"site_id,omitempty"`
+	Name    string `json:"name"` // the SSID
+	Enabled bool   `json:"enabled"`
+
+	Security       WLANSecurity `json:"security"`
+	WPAMode        string       `json:"wpa_mode,omitempty"`
+	WPAEnc         string       `json:"wpa_enc,omitempty"` // ccmp
+	WPA3Support    bool         `json:"wpa3_support"`
+	WPA3Transition bool         `json:"wpa3_transition"` // accept WPA2 clients next to WPA3 ones
+	PMFMode        string       `json:"pmf_mode,omitempty"`
+	Passphrase     string       `json:"x_passphrase,omitempty"`
+	RADIUSProfile  string       `json:"radiusprofile_id,omitempty"` // required for WLANSecurityWPAEnterprise
+
+	NetworkID   string `json:"networkconf_id,omitempty"` // the network the clients join
+	VLANEnabled bool   `json:"vlan_enabled"`             // older controllers bind the VLAN directly
+	VLAN        int    `json:"vlan,omitempty"`
+	IsGuest     bool   `json:"is_guest"`
+	UserGroupID string `json:"usergroup_id,omitempty"`
+
+	WLANGroupID   string   `json:"wlangroup_id,omitempty"` // AP group of older controllers
+	APGroupIDs    []string `json:"ap_group_ids,omitempty"` // AP groups of newer controllers
+	WLANBand      string   `json:"wlan_band,omitempty"`    // both, 2g or 5g, band steering needs both
+	HideSSID      bool     `json:"hide_ssid"`
+	L2Isolation   bool     `json:"l2_isolation"`
+	FastRoaming   bool     `json:"fast_roaming_enabled"`
+	BSSTransition bool     `json:"bss_transition"`
+
+	MinrateNGEnabled      bool `json:"minrate_ng_enabled"` // minimum 2.4GHz data rate
+	MinrateNGDataRateKbps int  `json:"minrate_ng_data_rate_kbps,omitempty"`
+	MinrateNAEnabled      bool `json:"minrate_na_enabled"` // minimum 5GHz data rate
+	MinrateNADataRateKbps int  `json:"minrate_na_data_rate_kbps,omitempty"`
+
+	MACFilterEnabled bool     `json:"mac_filter_enabled"`
+	MACFilterPolicy  string   `json:"mac_filter_policy,omitempty"`
+	MACFilterList    []string `json:"mac_filter_list,omitempty"`
+
+	ScheduleEnabled bool     `json:"schedule_enabled"`
+	Schedule        []string `json:"schedule,omitempty"` // when the WLAN is up, like mon|0800-1700
+}
+
+// UnmarshalJSON implements json.Unmarshaler, older controllers send the VLAN ID as a string
+func (w *WLANConf) UnmarshalJSON(data []byte) error {
+	type wlanConf WLANConf
+	aux := struct {
+		*wlanConf
+		VLAN interface{} `json:"vlan"`
+	}{wlanConf: (*wlanConf)(w)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	w.VLAN, err = flexibleInt(aux.VLAN)
+	if err != nil {
+		return errors.Wrap(err, "invalid vlan")
+	}
+	return nil
+}
+
+// Validate checks the WLAN before it is sent to the controller
+func (w *WLANConf) Validate() error {
+	if w.Name == "" || len(w.Name) > 32 {
+		return fmt.Errorf("invalid SSID %q, must be 1 to 32 bytes", w.Name)
+	}
+	if !w.Security.IsValid() {
+		return fmt.Errorf("invalid WLAN security specified: %s", w.Security)
+	}
+
+	switch w.Security {
+	case WLANSecurityOpen:
+		if w.Passphrase != "" {
+			return fmt.Errorf("open WLAN can not have a passphrase")
+		}
+		if w.WPA3Support {
+			return fmt.Errorf("WPA3 requires %s or %s security", WLANSecurityWPAPersonal, WLANSecurityWPAEnterprise)
+		}
+	case WLANSecurityWPAPersonal:
+		if err := validatePassphrase(w.Passphrase); err != nil {
+			return err
+		}
+	case WLANSecurityWPAEnterprise:
+		if w.RADIUSProfile == "" {
+			return fmt.Errorf("%s security requires a RADIUS profile", WLANSecurityWPAEnterprise)
+		}
+	}
+	switch w.WPAMode {
+	case "", WPAModeWPA2, WPAModeAuto:
+	default:
+		return fmt.Errorf("invalid WPA mode specified: %s", w.WPAMode)
+	}
+	switch w.PMFMode {
+	case "", PMFModeDisabled, PMFModeOptional, PMFModeRequired:
+	default:
+		return fmt.Errorf("invalid PMF mode specified: %s", w.PMFMode)
+	}
+	if w.WPA3Support {
+		// WPA3 mandates protected management frames, the transition mode keeps them optional for WPA2 clients
+		if w.WPA3Transition && w.PMFMode == PMFModeDisabled {
+			return fmt.Errorf("WPA3 transition requires the %s or %s PMF mode", PMFModeOptional, PMFModeRequired)
+		}
+		if !w.WPA3Transition && w.PMFMode != PMFModeRequired {
+			return fmt.Errorf("WPA3 requires the %s PMF mode", PMFModeRequired)
+		}
+		if w.WPAMode == WPAModeAuto {
+			return fmt.Errorf("WPA3 can not be combined with the %s WPA mode", WPAModeAuto)
+		}
+	} else if w.WPA3Transition {
+		return fmt.Errorf("WPA3 transition requires WPA3 support")
+	}
+
+	if w.VLANEnabled {
+		if err := validateVLAN(w.VLAN); err != nil {
+			return err
+		}
+	}
+	switch w.WLANBand {
+	case "", "both", "2g", "5g":
+	default:
+		return fmt.Errorf("invalid WLAN band specified: %s", w.WLANBand)
+	}
+	if (w.MinrateNGEnabled && w.MinrateNGDataRateKbps <= 0) || (w.MinrateNAEnabled && w.MinrateNADataRateKbps <= 0) {
+		return fmt.Errorf("minimum data rate requires a positive rate")
+	}
+	if w.MACFilterEnabled {
+		if w.MACFilterPolicy != MACFilterPolicyAllow && w.MACFilterPolicy != MACFilterPolicyDeny {
+			return fmt.Errorf("invalid MAC filter policy specified: %s", w.MACFilterPolicy)
+		}
+		for _, mac := range w.MACFilterList {
+			if _, err := net.ParseMAC(mac); err != nil {
+				return fmt.Errorf("invalid MAC filter address %q", mac)
+			}
+		}
+	}
+	if w.ScheduleEnabled && len(w.Schedule) == 0 {
+		return fmt.Errorf("WLAN schedule is enabled without any entry")
+	}
+	return nil
+}
+
+// validatePassphrase checks a WPA passphrase, 8 to 63 printable ASCII characters or 64 hex digits
+func validatePassphrase(passphrase string) error {
+	if len(passphrase) == 64 {
+		if _, err := hex.DecodeString(passphrase); err != nil {
+			return fmt.Errorf("invalid passphrase, 64 characters must be a hex key")
+		}
+		return nil
+	}
+	if len(passphrase) < 8 || len(passphrase) > 63 {
+		return fmt.Errorf("invalid passphrase, must be 8 to 63 characters")
+	}
+	for _, r := range passphrase {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("invalid passphrase, only printable ASCII characters are allowed")
+		}
+	}
+	return nil
+}
+
+// WLANConfResponse contains the typed WLAN configuration response
+type WLANConfResponse struct {
+	Meta CommonMeta `json:"meta"`
+	Data []WLANConf `json:"data"`
+}
+
+// ListWLANs lists the wireless networks of the site
+// site - the site to query
+func (c *Client) ListWLANs(ctx context.Context, site string, opts ...RequestOption) (*WLANConfResponse, error) {
+	var resp WLANConfResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/wlanconf", nil, &resp, opts...)
+	return &resp, err
+}
+
+// GetWLAN gets a single wireless network
+// site - the site to query
+// wlanID - the ID of the wireless network
+func (c *Client) GetWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConf, error) {
+	var resp WLANConfResponse
+	err := c.doSiteRequest(ctx, http.MethodGet, site, "rest/wlanconf/"+strings.TrimSpace(wlanID), nil, &resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "wlan %s", wlanID)
+	}
+	return &resp.Data[0], nil
+}
+
+// CreateWLAN validates and creates a new wireless network, the ID of the wireless network is ignored
+// site - the site to modify
+// wlan - the wireless network
+func (c *Client) CreateWLAN(ctx context.Context, site string, wlan WLANConf, opts ...RequestOption) (*WLANConfResponse, error) {
+	wlan.ID = ""
+	if err := wlan.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(wlan)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp WLANConfResponse
+	err = c.doSiteRequest(ctx, http.MethodPost, site, "rest/wlanconf", bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// UpdateWLAN validates and replaces an existing wireless network
+// site - the site to modify
+// wlan - the wireless network, its ID selects the wireless network to update
+func (c *Client) UpdateWLAN(ctx context.Context, site string, wlan WLANConf, opts ...RequestOption) (*WLANConfResponse, error) {
+	if wlan.ID == "" {
+		return nil, fmt.Errorf("wlan ID is required")
+	}
+	if err := wlan.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(wlan)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp WLANConfResponse
+	err = c.doSiteRequest(ctx, http.MethodPut, site, "rest/wlanconf/"+strings.TrimSpace(wlan.ID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
+
+// DeleteWLAN deletes an existing wireless network
+// site - the site to modify
+// wlanID - the ID of the wireless network
+func (c *Client) DeleteWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*GenericResponse, error) {
+	var resp GenericResponse
+	err := c.doSiteRequest(ctx, http.MethodDelete, site, "rest/wlanconf/"+strings.TrimSpace(wlanID), nil, &resp, opts...)
+	return &resp, err
+}
+
+// EnableWLAN enables a wireless network
+// site - the site to modify
+// wlanID - the ID of the wireless network
+func (c *Client) EnableWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConfResponse, error) {
+	return c.setWLANEnabled(ctx, site, wlanID, true, opts...)
+}
+
+// DisableWLAN disables a wireless network without deleting it
+// site - the site to modify
+// wlanID - the ID of the wireless network
+func (c *Client) DisableWLAN(ctx context.Context, site string, wlanID string, opts ...RequestOption) (*WLANConfResponse, error) {
+	return c.setWLANEnabled(ctx, site, wlanID, false, opts...)
+}
+
+func (c *Client) setWLANEnabled(ctx context.Context, site string, wlanID string, enabled bool, opts ...RequestOption) (*WLANConfResponse, error) {
+	data, _ := json.Marshal(map[string]interface{}{"enabled": enabled})
+
+	var resp WLANConfResponse
+	err := c.doSiteRequest(ctx, http.MethodPut, site, "rest/wlanconf/"+strings.TrimSpace(wlanID), bytes.NewReader(data), &resp, opts...)
+	return &resp, err
+}
@@ -0,0 +1,98 @@
+package unifi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePassphrase(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		wantErr    bool
+	}{
+		{name: "8 characters", passphrase: "abcdefgh"},
+		{name: "63 characters", passphrase: strings.Repeat("a", 63)},
+		{name: "64 hex digits", passphrase: strings.Repeat("0f", 32)},
+		{name: "upper case hex digits", passphrase: strings.Repeat("AB", 32)},
+		{name: "printable symbols", passphrase: "p@ss w0rd~!"},
+		{name: "empty", passphrase: "", wantErr: true},
+		{name: "7 characters", passphrase: "abcdefg", wantErr: true},
+		{name: "64 characters not hex", passphrase: strings.Repeat("g", 64), wantErr: true},
+		{name: "65 characters", passphrase: strings.Repeat("a", 65), wantErr: true},
+		{name: "control character", passphrase: "abcd\tefgh", wantErr: true},
+		{name: "non ascii", passphrase: "passwörter", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePassphrase(tt.passphrase); (err != nil) != tt.wantErr {
+				t.Fatalf("validatePassphrase(%q) error = %v, wantErr %v", tt.passphrase, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWLANConfValidate(t *testing.T) {
+	valid := WLANConf{Name: "home", Enabled: true, Security: WLANSecurityWPAPersonal, WPAMode: WPAModeWPA2, Passphrase: "correct horse"}
+	tests := []struct {
+		name    string
+		modify  func(w *WLANConf)
+		wantErr bool
+	}{
+		{name: "wpa2 personal", modify: func(w *WLANConf) {}},
+		{name: "missing SSID", modify: func(w *WLANConf) { w.Name = "" }, wantErr: true},
+		{name: "32 byte SSID", modify: func(w *WLANConf) { w.Name = strings.Repeat("s", 32) }},
+		{name: "33 byte SSID", modify: func(w *WLANConf) { w.Name = strings.Repeat("s", 33) }, wantErr: true},
+		{name: "invalid security", modify: func(w *WLANConf) { w.Security = "wep" }, wantErr: true},
+		{name: "short passphrase", modify: func(w *WLANConf) { w.Passphrase = "short" }, wantErr: true},
+		{name: "open", modify: func(w *WLANConf) { w.Security, w.Passphrase, w.WPAMode = WLANSecurityOpen, "", "" }},
+		{name: "open with passphrase", modify: func(w *WLANConf) { w.Security = WLANSecurityOpen }, wantErr: true},
+		{name: "open with wpa3", modify: func(w *WLANConf) {
+			w.Security, w.Passphrase, w.WPA3Support, w.PMFMode = WLANSecurityOpen, "", true, PMFModeRequired
+		}, wantErr: true},
+		{name: "enterprise", modify: func(w *WLANConf) { w.Security, w.Passphrase, w.RADIUSProfile = WLANSecurityWPAEnterprise, "", "radius" }},
+		{name: "enterprise without radius profile", modify: func(w *WLANConf) { w.Security = WLANSecurityWPAEnterprise }, wantErr: true},
+		{name: "invalid wpa mode", modify: func(w *WLANConf) { w.WPAMode = "wpa1" }, wantErr: true},
+		{name: "invalid pmf mode", modify: func(w *WLANConf) { w.PMFMode = "always" }, wantErr: true},
+		{name: "wpa3 with required pmf", modify: func(w *WLANConf) { w.WPA3Support, w.PMFMode = true, PMFModeRequired }},
+		{name: "wpa3 with optional pmf", modify: func(w *WLANConf) { w.WPA3Support, w.PMFMode = true, PMFModeOptional }, wantErr: true},
+		{name: "wpa3 without pmf mode", modify: func(w *WLANConf) { w.WPA3Support = true }, wantErr: true},
+		{name: "wpa3 with auto wpa mode", modify: func(w *WLANConf) {
+			w.WPA3Support, w.PMFMode, w.WPAMode = true, PMFModeRequired, WPAModeAuto
+		}, wantErr: true},
+		{name: "wpa3 transition with optional pmf", modify: func(w *WLANConf) {
+			w.WPA3Support, w.WPA3Transition, w.PMFMode = true, true, PMFModeOptional
+		}},
+		{name: "wpa3 transition with required pmf", modify: func(w *WLANConf) {
+			w.WPA3Support, w.WPA3Transition, w.PMFMode = true, true, PMFModeRequired
+		}},
+		{name: "wpa3 transition with disabled pmf", modify: func(w *WLANConf) {
+			w.WPA3Support, w.WPA3Transition, w.PMFMode = true, true, PMFModeDisabled
+		}, wantErr: true},
+		{name: "wpa3 transition without wpa3", modify: func(w *WLANConf) { w.WPA3Transition, w.PMFMode = true, PMFModeOptional }, wantErr: true},
+		{name: "lowest vlan", modify: func(w *WLANConf) { w.VLANEnabled, w.VLAN = true, MinVLANID }},
+		{name: "highest vlan", modify: func(w *WLANConf) { w.VLANEnabled, w.VLAN = true, MaxVLANID }},
+		{name: "default vlan", modify: func(w *WLANConf) { w.VLANEnabled, w.VLAN = true, 1 }, wantErr: true},
+		{name: "vlan above range", modify: func(w *WLANConf) { w.VLANEnabled, w.VLAN = true, 4010 }, wantErr: true},
+		{name: "vlan ignored when disabled", modify: func(w *WLANConf) { w.VLAN = 4010 }},
+		{name: "invalid band", modify: func(w *WLANConf) { w.WLANBand = "6g" }, wantErr: true},
+		{name: "minimum rate without rate", modify: func(w *WLANConf) { w.MinrateNAEnabled = true }, wantErr: true},
+		{name: "mac filter", modify: func(w *WLANConf) {
+			w.MACFilterEnabled, w.MACFilterPolicy, w.MACFilterList = true, MACFilterPolicyDeny, []string{"00:11:22:33:44:55"}
+		}},
+		{name: "mac filter invalid address", modify: func(w *WLANConf) {
+			w.MACFilterEnabled, w.MACFilterPolicy, w.MACFilterList = true, MACFilterPolicyAllow, []string{"00:11:22"}
+		}, wantErr: true},
+		{name: "mac filter without policy", modify: func(w *WLANConf) { w.MACFilterEnabled = true }, wantErr: true},
+		{name: "schedule without entries", modify: func(w *WLANConf) { w.ScheduleEnabled = true }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid
+			tt.modify(&w)
+			if err := w.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package unifi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifitest"
+)
+
+// rfc6238Seed is the base32 encoding of the SHA1 key of the RFC 6238 test vectors, 12345678901234567890
+const rfc6238Seed = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPGeneratorRFC6238(t *testing.T) {
+	// the RFC lists 8 digit codes, the controller uses their last 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+	for _, seed := range []string{rfc6238Seed, "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", rfc6238Seed + "===="} {
+		generator, err := unifi.NewTOTPGenerator(seed)
+		if err != nil {
+			t.Fatalf("NewTOTPGenerator(%q) failed: %v", seed, err)
+		}
+		for _, tt := range tests {
+			if code := generator.CodeAt(time.Unix(tt.unix, 0)); code != tt.code {
+				t.Errorf("CodeAt(%d) with seed %q = %s, want %s", tt.unix, seed, code, tt.code)
+			}
+		}
+	}
+
+	if _, err := unifi.NewTOTPGenerator("not base32!"); err == nil {
+		t.Fatal("expected an invalid seed to be rejected")
+	}
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	generator, err := unifi.NewTOTPGenerator(rfc6238Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flavors := []struct {
+		name string
+		opts []unifitest.ServerOption
+	}{
+		{name: "classic"},
+		{name: "unifi os", opts: []unifitest.ServerOption{unifitest.WithUniFiOS()}},
+	}
+	tests := []struct {
+		name        string
+		totp        unifi.TOTPProvider
+		rejected    bool // a wrong code is a plain rejection, only a missing one asks for the second factor
+		loginErr    error
+		reloginErr  error
+		reloginSent bool
+	}{
+		{name: "missing code", rejected: true, loginErr: unifi.ErrTwoFactorRequired},
+		{name: "wrong code", rejected: true, totp: unifi.StaticTOTP("000000")},
+		{name: "generator", totp: generator, reloginSent: true},
+		{name: "static code", totp: unifi.StaticTOTP(generator.CodeAt(time.Now())), reloginErr: unifi.ErrTwoFactorRequired},
+	}
+	for _, flavor := range flavors {
+		for _, tt := range tests {
+			t.Run(flavor.name+" "+tt.name, func(t *testing.T) {
+				srv := unifitest.NewServer(append(flavor.opts, unifitest.WithTOTPSeed(rfc6238Seed))...)
+				defer srv.Close()
+
+				var opts []unifi.ClientOption
+				if tt.totp != nil {
+					opts = append(opts, unifi.WithTOTPProvider(tt.totp))
+				}
+				ctx := context.Background()
+				client, err := srv.Client(ctx, opts...)
+				if tt.rejected {
+					if err == nil || errors.Is(err, unifi.ErrTwoFactorRequired) != (tt.loginErr == unifi.ErrTwoFactorRequired) {
+						t.Fatalf("expected the login to be rejected with %v, got %v", tt.loginErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("login failed: %v", err)
+				}
+
+				srv.ExpireSessions()
+				before := len(srv.Requests())
+				_, err = client.SiteHealth(ctx, "default")
+				if tt.reloginErr == nil && err != nil {
+					t.Fatalf("request after the session expired failed: %v", err)
+				}
+				if tt.reloginErr != nil && !errors.Is(err, tt.reloginErr) {
+					t.Fatalf("expected re-login error %v, got %v", tt.reloginErr, err)
+				}
+				logins := 0
+				for _, req := range srv.Requests()[before:] {
+					if req.Path == "/api/login" || req.Path == "/api/auth/login" {
+						logins++
+					}
+				}
+				if tt.reloginSent != (logins == 1) {
+					t.Fatalf("expected a re-login to be sent: %v, got %d logins", tt.reloginSent, logins)
+				}
+			})
+		}
+	}
+}
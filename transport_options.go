@@ -0,0 +1,212 @@
+package unifi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// DialContextFunc dials the connections to the controller, or to the proxy when one is configured
+type DialContextFunc func(ctx context.Context, network string, addr string) (net.Conn, error)
+
+// transport returns the http transport of the client to configure, a copy of the default transport if none is set.
+// The transport options must be passed before options that wrap the transport, like WithRecorder.
+func (c *Client) transport() (*http.Transport, error) {
+	switch tr := c.HTTPClient.Transport.(type) {
+	case nil:
+		clone := http.DefaultTransport.(*http.Transport).Clone()
+		c.HTTPClient.Transport = clone
+		return clone, nil
+	case *http.Transport:
+		return tr, nil
+	default:
+		return nil, fmt.Errorf("unable to configure transport %T, pass the transport options first", tr)
+	}
+}
+
+// WithProxy sends the requests through a proxy instead of the proxy from the environment
+// proxyURL - http://, https:// or socks5:// url of the proxy, credentials may be set as user info.
+// An https proxy is verified with the system roots, see WithProxyTLSConfig.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %v", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q, use http, https or socks5", u.Scheme)
+		}
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		tr.Proxy = http.ProxyURL(u)
+		d := c.tlsDialer(tr)
+		d.proxyAddr = ""
+		if u.Scheme == "https" {
+			d.proxyAddr = hostPort(u)
+		}
+		return nil
+	}
+}
+
+// WithProxyTLSConfig configures the TLS handshake with an https proxy, like the roots to verify a proxy with a private CA.
+// It is independent of the controller, the CertificationConfig and the client certificates never apply to the proxy.
+func WithProxyTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) error {
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		c.tlsDialer(tr).proxyTLS = config
+		return nil
+	}
+}
+
+// WithSOCKS5Proxy sends the requests through a SOCKS5 proxy
+// address - host:port of the proxy
+// username - optional username, the password is only used with a username
+func WithSOCKS5Proxy(address string, username string, password string) ClientOption {
+	u := &url.URL{Scheme: "socks5", Host: address}
+	if username != "" {
+		u.User = url.UserPassword(username, password)
+	}
+	return WithProxy(u.String())
+}
+
+// WithDialContext dials the connections with dial, for example to tunnel them over ssh
+func WithDialContext(dial DialContextFunc) ClientOption {
+	return func(c *Client) error {
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		tr.DialContext = dial
+		return nil
+	}
+}
+
+// WithClientCertificate presents the certificate to controllers, or reverse proxies in front of them, that require mutual TLS
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *Client) error {
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		tr.TLSClientConfig.Certificates = append(tr.TLSClientConfig.Certificates, cert)
+		return nil
+	}
+}
+
+// WithClientCertificateFile presents the PEM encoded certificate and key for mutual TLS, see WithClientCertificate
+// certFile - path to the PEM certificate, it may hold the intermediate chain after the leaf
+// keyFile - path to the PEM private key
+func WithClientCertificateFile(certFile string, keyFile string) ClientOption {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load client certificate: %v", err)
+		}
+		return WithClientCertificate(cert)(c)
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2, by default it is negotiated when the controller supports it
+func WithHTTP2(enabled bool) ClientOption {
+	return func(c *Client) error {
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		tr.ForceAttemptHTTP2 = enabled
+		if enabled {
+			tr.TLSNextProto = nil
+		} else {
+			// a non-nil empty map disables HTTP/2
+			tr.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		}
+		return nil
+	}
+}
+
+// tlsDialer performs the TLS handshakes the transport dials itself: the https proxy, and the controller when
+// connecting directly. The proxy handshake gets its own configuration, so the certificate checks of the controller
+// like the pinned fingerprints never run on it, and the controller certificate is checked against the dialed address.
+type tlsDialer struct {
+	transport *http.Transport
+	verifier  *fingerprintVerifier
+	proxyAddr string      // host:port of the https proxy, empty without one
+	proxyTLS  *tls.Config // configuration of the proxy handshake, nil for the system defaults
+}
+
+// tlsDialer returns the TLS dialer of the transport, installing it on first use
+func (c *Client) tlsDialer(tr *http.Transport) *tlsDialer {
+	if c.tlsDial == nil || c.tlsDial.transport != tr {
+		c.tlsDial = &tlsDialer{transport: tr, verifier: c.verifier}
+		tr.DialTLSContext = c.tlsDial.dial
+	}
+	return c.tlsDial
+}
+
+// dial implements http.Transport.DialTLSContext
+func (d *tlsDialer) dial(ctx context.Context, network string, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var config *tls.Config
+	if addr == d.proxyAddr {
+		// the proxy is verified independently of the controller
+		config = d.proxyTLS.Clone()
+		if config == nil {
+			config = &tls.Config{}
+		}
+	} else {
+		config = d.transport.TLSClientConfig.Clone()
+		if config == nil {
+			config = &tls.Config{}
+		}
+		if d.verifier != nil {
+			config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				return d.verifier.verifyHost(addr, rawCerts)
+			}
+		}
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+
+	dial := d.transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, config)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- tlsConn.Handshake()
+	}()
+	select {
+	case <-ctx.Done():
+		conn.Close()
+		return nil, ctx.Err()
+	case err := <-errc:
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return tlsConn, nil
+}
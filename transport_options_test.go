@@ -0,0 +1,406 @@
+package unifi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+// pinnedClient returns a client of the controller pinning its certificate
+func pinnedClient(t *testing.T, srv *httptest.Server, cert tls.Certificate, opts ...unifi.ClientOption) *unifi.Client {
+	t.Helper()
+	certConfig := &unifi.CertificationConfig{PinnedFingerprints: []string{unifi.CertificateFingerprint(cert.Leaf)}}
+	client, err := unifi.NewClient(srv.URL, certConfig, 5*time.Second, append([]unifi.ClientOption{unifi.WithRetryPolicy(unifi.NoRetryPolicy())}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newClientCertificate returns a self-signed certificate for mutual TLS
+func newClientCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "unifi-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// socks5Proxy is a SOCKS5 proxy with username and password authentication, counting the connections it opened
+type socks5Proxy struct {
+	listener    net.Listener
+	username    string
+	password    string
+	connections int32
+}
+
+// newSOCKS5Proxy starts a SOCKS5 proxy requiring the credentials
+func newSOCKS5Proxy(t *testing.T, username string, password string) *socks5Proxy {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &socks5Proxy{listener: listener, username: username, password: password}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go p.serve(conn)
+		}
+	}()
+	return p
+}
+
+// Close stops the proxy
+func (p *socks5Proxy) Close() {
+	p.listener.Close()
+}
+
+// serve handles a client connection, see RFC 1928 and RFC 1929
+func (p *socks5Proxy) serve(conn net.Conn) {
+	defer conn.Close()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil || header[0] != 5 {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	// only the username/password method is offered
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return
+	}
+	if !p.authenticate(conn) {
+		return
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil || request[1] != 1 {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return
+		}
+		host = string(name)
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	atomic.AddInt32(&p.connections, 1)
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+	go func() {
+		_, _ = io.Copy(target, conn)
+		target.Close()
+	}()
+	_, _ = io.Copy(conn, target)
+}
+
+// authenticate checks the username and password of the client
+func (p *socks5Proxy) authenticate(conn net.Conn) bool {
+	read := func() (string, bool) {
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", false
+		}
+		value := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, value); err != nil {
+			return "", false
+		}
+		return string(value), true
+	}
+	version := make([]byte, 1)
+	if _, err := io.ReadFull(conn, version); err != nil || version[0] != 1 {
+		return false
+	}
+	username, ok := read()
+	if !ok {
+		return false
+	}
+	password, ok := read()
+	if !ok {
+		return false
+	}
+	if username != p.username || password != p.password {
+		_, _ = conn.Write([]byte{1, 1})
+		return false
+	}
+	_, err := conn.Write([]byte{1, 0})
+	return err == nil
+}
+
+func TestWithProxy(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+
+	httpProxy := &connectProxy{}
+	httpProxySrv := httptest.NewServer(httpProxy)
+	defer httpProxySrv.Close()
+	httpsProxySrv, httpsProxy, httpsProxyOpts := newHTTPSProxy(t)
+	defer httpsProxySrv.Close()
+
+	tests := []struct {
+		name  string
+		proxy *connectProxy
+		opts  []unifi.ClientOption
+	}{
+		{name: "http", proxy: httpProxy, opts: []unifi.ClientOption{unifi.WithProxy(httpProxySrv.URL)}},
+		{name: "https", proxy: httpsProxy, opts: httpsProxyOpts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := pinnedClient(t, srv, cert, tt.opts...)
+			if _, err := client.ControllerStatus(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if atomic.LoadInt32(&tt.proxy.tunnels) == 0 {
+				t.Fatal("the request did not go through the proxy")
+			}
+		})
+	}
+
+	t.Run("untrusted https proxy", func(t *testing.T) {
+		// the proxy certificate is not signed by the system roots
+		client := pinnedClient(t, srv, cert, unifi.WithProxy(httpsProxySrv.URL))
+		if _, err := client.ControllerStatus(context.Background()); err == nil {
+			t.Fatal("expected the proxy certificate to be rejected")
+		}
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		if _, err := unifi.NewClient(srv.URL, nil, 0, unifi.WithProxy("ftp://127.0.0.1:21")); err == nil {
+			t.Fatal("expected the ftp proxy to be rejected")
+		}
+	})
+}
+
+func TestWithSOCKS5Proxy(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+	proxy := newSOCKS5Proxy(t, "user", "secret")
+	defer proxy.Close()
+
+	client := pinnedClient(t, srv, cert, unifi.WithSOCKS5Proxy(proxy.listener.Addr().String(), "user", "secret"))
+	if _, err := client.ControllerStatus(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxy.connections) == 0 {
+		t.Fatal("the request did not go through the proxy")
+	}
+
+	client = pinnedClient(t, srv, cert, unifi.WithSOCKS5Proxy(proxy.listener.Addr().String(), "user", "wrong"))
+	if _, err := client.ControllerStatus(context.Background()); err == nil {
+		t.Fatal("expected the proxy to reject the credentials")
+	}
+}
+
+func TestWithDialContext(t *testing.T) {
+	cert := newCertificate(t)
+	srv := newController(t, cert)
+	defer srv.Close()
+
+	// the controller is reached through the dialer whatever address the client connects to
+	var dials int32
+	dialer := &net.Dialer{}
+	client := pinnedClient(t, srv, cert, unifi.WithDialContext(func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return dialer.DialContext(ctx, network, srv.Listener.Addr().String())
+	}))
+	if _, err := client.ControllerStatus(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&dials) == 0 {
+		t.Fatal("the connection was not dialed with the dialer")
+	}
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	cert := newCertificate(t)
+	clientCert := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	var peer int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 && r.TLS.PeerCertificates[0].Equal(clientCert.Leaf) {
+			atomic.AddInt32(&peer, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"rc":"ok","up":true},"data":[]}`))
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "unifi-client-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	key, err := x509.MarshalECPrivateKey(clientCert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Certificate[0]}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []unifi.ClientOption
+		wantErr bool
+	}{
+		{name: "certificate", opts: []unifi.ClientOption{unifi.WithClientCertificate(clientCert)}},
+		{name: "certificate file", opts: []unifi.ClientOption{unifi.WithClientCertificateFile(certFile, keyFile)}},
+		{name: "without certificate", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&peer)
+			client := pinnedClient(t, srv, cert, tt.opts...)
+			_, err := client.ControllerStatus(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the controller to require a client certificate")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if atomic.LoadInt32(&peer) == before {
+				t.Fatal("the controller did not receive the client certificate")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := unifi.NewClient(srv.URL, nil, 0, unifi.WithClientCertificateFile(filepath.Join(dir, "missing.crt"), keyFile)); err == nil {
+			t.Fatal("expected the missing certificate to be rejected")
+		}
+	})
+}
+
+func TestWithHTTP2(t *testing.T) {
+	cert := newCertificate(t)
+	var proto int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt32(&proto, int32(r.ProtoMajor))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"rc":"ok","up":true},"data":[]}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    int32
+	}{
+		{name: "enabled", enabled: true, want: 2},
+		{name: "disabled", enabled: false, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := pinnedClient(t, srv, cert, unifi.WithHTTP2(tt.enabled))
+			if _, err := client.ControllerStatus(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := atomic.LoadInt32(&proto); got != tt.want {
+				t.Fatalf("expected HTTP/%d, got HTTP/%d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransportOptionsAfterWrappingTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unifi-recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the recorder wraps the transport, which can not be configured anymore
+	_, err = unifi.NewClient("https://127.0.0.1:8443", nil, 0,
+		unifi.WithRecorder(unifi.NewRecorder(dir, unifi.RecordOptions{})), unifi.WithHTTP2(false))
+	if err == nil {
+		t.Fatal("expected the transport option to be rejected after the recorder")
+	}
+}
@@ -0,0 +1,26 @@
+package unifimock_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/platinummonkey/unifi"
+	"github.com/platinummonkey/unifi/unifimock"
+)
+
+func ExampleSiteReader() {
+	m := &unifimock.SiteReader{
+		SiteHealthFunc: func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteHealthResponse, error) {
+			return &unifi.SiteHealthResponse{}, nil
+		},
+	}
+
+	var reader unifi.SiteReader = m
+	_, err := reader.SiteHealth(context.Background(), "default")
+	fmt.Println(err)
+	_, err = reader.SiteSysInfo(context.Background(), "default")
+	fmt.Println(err)
+	// Output:
+	// <nil>
+	// unifimock: method not mocked
+}
@@ -0,0 +1,219 @@
+// Command mockgen generates the unifimock mocks from the interfaces declared in the unifi package.
+//
+// Every interface becomes a struct with a <Method>Func field per method, calling a method without its
+// func set returns unifimock.ErrNotMocked, or the zero values for methods without an error result.
+// Interfaces made only of other interfaces embed their mocks.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+)
+
+const unifiImport = "github.com/platinummonkey/unifi"
+
+func main() {
+	source := flag.String("source", "api.go", "file declaring the interfaces")
+	out := flag.String("out", "unifimock/mocks.go", "generated file")
+	pkg := flag.String("pkg", "unifimock", "package of the generated file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *source, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{fset: fset}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.IsExported() {
+				g.generateInterface(ts.Name.Name, it)
+			}
+		}
+	}
+
+	var imports []string
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if bytes.Contains(g.body.Bytes(), []byte(name+".")) {
+			imports = append(imports, strconv.Quote(path))
+		}
+	}
+	imports = append(imports, "", strconv.Quote(unifiImport))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\n", *source)
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n%s\n)\n", *pkg, strings.Join(imports, "\n"))
+	buf.Write(g.body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("generated invalid code: %v\n%s", err, buf.String())
+	}
+	err = ioutil.WriteFile(*out, src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	fset *token.FileSet
+	body bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+// generateInterface writes the mock of an interface
+func (g *generator) generateInterface(name string, it *ast.InterfaceType) {
+	g.printf("\n// %s is a mock of unifi.%s\n", name, name)
+	g.printf("type %s struct {\n", name)
+	var methods []*ast.Field
+	for _, field := range it.Methods.List {
+		if _, ok := field.Type.(*ast.FuncType); ok {
+			methods = append(methods, field)
+			continue
+		}
+		// embedded interface, embed its mock
+		g.printf("%s\n", g.expr(field.Type, false))
+	}
+	for _, m := range methods {
+		g.printf("%sFunc func%s\n", m.Names[0].Name, g.funcType(m.Type.(*ast.FuncType), false))
+	}
+	g.printf("}\n\nvar _ unifi.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range methods {
+		g.generateMethod(name, m.Names[0].Name, m.Type.(*ast.FuncType))
+	}
+}
+
+// generateMethod writes a mock method forwarding to its func field
+func (g *generator) generateMethod(mock string, method string, ft *ast.FuncType) {
+	var args []string
+	for i, p := range ft.Params.List {
+		names := p.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+		}
+		for _, n := range names {
+			arg := n.Name
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+
+	g.printf("\n// %s calls %sFunc\n", method, method)
+	g.printf("func (m *%s) %s%s {\n", mock, method, g.funcType(ft, true))
+	g.printf("if m.%sFunc == nil {\n", method)
+	if ft.Results == nil {
+		// nothing to report the missing mock with
+		g.printf("return\n}\n")
+		g.printf("m.%sFunc(%s)\n}\n", method, strings.Join(args, ", "))
+		return
+	}
+	if hasError(ft) {
+		g.printf("err = ErrNotMocked\n")
+	}
+	g.printf("return\n}\n")
+	g.printf("return m.%sFunc(%s)\n}\n", method, strings.Join(args, ", "))
+}
+
+// hasError returns true if one of the results of the func type is an error
+func hasError(ft *ast.FuncType) bool {
+	for _, r := range ft.Results.List {
+		if ident, ok := r.Type.(*ast.Ident); ok && ident.Name == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// funcType prints the signature of a func type, with named results when named is set
+func (g *generator) funcType(ft *ast.FuncType, named bool) string {
+	var params []string
+	for i, p := range ft.Params.List {
+		typ := g.expr(p.Type, true)
+		if len(p.Names) == 0 {
+			params = append(params, fmt.Sprintf("p%d %s", i, typ))
+		}
+		for _, n := range p.Names {
+			params = append(params, n.Name+" "+typ)
+		}
+	}
+	sig := "(" + strings.Join(params, ", ") + ")"
+	if ft.Results == nil {
+		return sig
+	}
+
+	var results []string
+	for i, r := range ft.Results.List {
+		typ := g.expr(r.Type, true)
+		if !named {
+			results = append(results, typ)
+			continue
+		}
+		name := fmt.Sprintf("r%d", i)
+		if typ == "error" {
+			name = "err"
+		}
+		results = append(results, name+" "+typ)
+	}
+	if len(results) == 1 && !named {
+		return sig + " " + results[0]
+	}
+	return sig + " (" + strings.Join(results, ", ") + ")"
+}
+
+// expr prints a type expression, qualifying the unifi types when qualify is set
+func (g *generator) expr(e ast.Expr, qualify bool) string {
+	if qualify {
+		e = qualifyExpr(e)
+	}
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, e)
+	return buf.String()
+}
+
+// qualifyExpr returns a copy of the type expression with exported identifiers qualified with the unifi package
+func qualifyExpr(e ast.Expr) ast.Expr {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if t.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("unifi"), Sel: ast.NewIdent(t.Name)}
+		}
+		return t
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualifyExpr(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualifyExpr(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualifyExpr(t.Key), Value: qualifyExpr(t.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualifyExpr(t.Elt)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: t.Dir, Value: qualifyExpr(t.Value)}
+	default:
+		// selector expressions like context.Context are already qualified
+		return e
+	}
+}
@@ -0,0 +1,1516 @@
+// Code generated by mockgen from api.go. DO NOT EDIT.
+
+package unifimock
+
+import (
+	"context"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+// Session is a mock of unifi.Session
+type Session struct {
+	LoginFunc             func(ctx context.Context, username string, password string, remember bool) error
+	LoginWithProviderFunc func(ctx context.Context, provider unifi.CredentialProvider, remember bool) error
+	LogoutFunc            func(ctx context.Context) error
+	SelfFunc              func(ctx context.Context, opts ...unifi.RequestOption) (*unifi.SelfResponse, error)
+	ExportSessionFunc     func() (*unifi.SessionState, error)
+	ImportSessionFunc     func(state *unifi.SessionState) error
+}
+
+var _ unifi.Session = (*Session)(nil)
+
+// Login calls LoginFunc
+func (m *Session) Login(ctx context.Context, username string, password string, remember bool) (err error) {
+	if m.LoginFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.LoginFunc(ctx, username, password, remember)
+}
+
+// LoginWithProvider calls LoginWithProviderFunc
+func (m *Session) LoginWithProvider(ctx context.Context, provider unifi.CredentialProvider, remember bool) (err error) {
+	if m.LoginWithProviderFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.LoginWithProviderFunc(ctx, provider, remember)
+}
+
+// Logout calls LogoutFunc
+func (m *Session) Logout(ctx context.Context) (err error) {
+	if m.LogoutFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.LogoutFunc(ctx)
+}
+
+// Self calls SelfFunc
+func (m *Session) Self(ctx context.Context, opts ...unifi.RequestOption) (r0 *unifi.SelfResponse, err error) {
+	if m.SelfFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SelfFunc(ctx, opts...)
+}
+
+// ExportSession calls ExportSessionFunc
+func (m *Session) ExportSession() (r0 *unifi.SessionState, err error) {
+	if m.ExportSessionFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ExportSessionFunc()
+}
+
+// ImportSession calls ImportSessionFunc
+func (m *Session) ImportSession(state *unifi.SessionState) (err error) {
+	if m.ImportSessionFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ImportSessionFunc(state)
+}
+
+// SiteReader is a mock of unifi.SiteReader
+type SiteReader struct {
+	ControllerStatusFunc           func(ctx context.Context, opts ...unifi.RequestOption) (*unifi.ControllerStatus, error)
+	AvailableSitesFunc             func(ctx context.Context, opts ...unifi.RequestOption) (*unifi.SitesResponse, error)
+	AvailableSitesVerboseFunc      func(ctx context.Context, opts ...unifi.RequestOption) (*unifi.SitesVerboseResponse, error)
+	SiteAdminsFunc                 func(ctx context.Context, opts ...unifi.RequestOption) (*unifi.SiteAdminsResponse, error)
+	SiteHealthFunc                 func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteHealthResponse, error)
+	SiteSysInfoFunc                func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteSysInfoResponse, error)
+	SiteCountryCodesFunc           func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteCountryCodesResponse, error)
+	SiteCurrentChannelsFunc        func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteCurrentChannelsResponse, error)
+	SiteDetailedSettingsFunc       func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteDetailedSettingsResponse, error)
+	SiteActiveRoutesFunc           func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteActiveRoutesResponse, error)
+	SiteUserDefinedRoutesFunc      func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteUserDefinedRoutesResponse, error)
+	SiteTaggedMACsFunc             func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteTaggedMACResponse, error)
+	SiteWLANConfigsFunc            func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteWLANConfigResponse, error)
+	SiteWLANGroupsFunc             func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteWLANGroupResponse, error)
+	SiteRougeAccessPointsFunc      func(ctx context.Context, site string, seenWithinHours int, opts ...unifi.RequestOption) (*unifi.SiteRougeAccessPointResponse, error)
+	SiteRougeKnownAccessPointsFunc func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteRougeAccessPointResponse, error)
+	ListDashboardMetricsFunc       func(ctx context.Context, site string, scale5Min bool, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.SiteReader = (*SiteReader)(nil)
+
+// ControllerStatus calls ControllerStatusFunc
+func (m *SiteReader) ControllerStatus(ctx context.Context, opts ...unifi.RequestOption) (r0 *unifi.ControllerStatus, err error) {
+	if m.ControllerStatusFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ControllerStatusFunc(ctx, opts...)
+}
+
+// AvailableSites calls AvailableSitesFunc
+func (m *SiteReader) AvailableSites(ctx context.Context, opts ...unifi.RequestOption) (r0 *unifi.SitesResponse, err error) {
+	if m.AvailableSitesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AvailableSitesFunc(ctx, opts...)
+}
+
+// AvailableSitesVerbose calls AvailableSitesVerboseFunc
+func (m *SiteReader) AvailableSitesVerbose(ctx context.Context, opts ...unifi.RequestOption) (r0 *unifi.SitesVerboseResponse, err error) {
+	if m.AvailableSitesVerboseFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AvailableSitesVerboseFunc(ctx, opts...)
+}
+
+// SiteAdmins calls SiteAdminsFunc
+func (m *SiteReader) SiteAdmins(ctx context.Context, opts ...unifi.RequestOption) (r0 *unifi.SiteAdminsResponse, err error) {
+	if m.SiteAdminsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteAdminsFunc(ctx, opts...)
+}
+
+// SiteHealth calls SiteHealthFunc
+func (m *SiteReader) SiteHealth(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteHealthResponse, err error) {
+	if m.SiteHealthFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteHealthFunc(ctx, site, opts...)
+}
+
+// SiteSysInfo calls SiteSysInfoFunc
+func (m *SiteReader) SiteSysInfo(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteSysInfoResponse, err error) {
+	if m.SiteSysInfoFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteSysInfoFunc(ctx, site, opts...)
+}
+
+// SiteCountryCodes calls SiteCountryCodesFunc
+func (m *SiteReader) SiteCountryCodes(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteCountryCodesResponse, err error) {
+	if m.SiteCountryCodesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteCountryCodesFunc(ctx, site, opts...)
+}
+
+// SiteCurrentChannels calls SiteCurrentChannelsFunc
+func (m *SiteReader) SiteCurrentChannels(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteCurrentChannelsResponse, err error) {
+	if m.SiteCurrentChannelsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteCurrentChannelsFunc(ctx, site, opts...)
+}
+
+// SiteDetailedSettings calls SiteDetailedSettingsFunc
+func (m *SiteReader) SiteDetailedSettings(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteDetailedSettingsResponse, err error) {
+	if m.SiteDetailedSettingsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteDetailedSettingsFunc(ctx, site, opts...)
+}
+
+// SiteActiveRoutes calls SiteActiveRoutesFunc
+func (m *SiteReader) SiteActiveRoutes(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteActiveRoutesResponse, err error) {
+	if m.SiteActiveRoutesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteActiveRoutesFunc(ctx, site, opts...)
+}
+
+// SiteUserDefinedRoutes calls SiteUserDefinedRoutesFunc
+func (m *SiteReader) SiteUserDefinedRoutes(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteUserDefinedRoutesResponse, err error) {
+	if m.SiteUserDefinedRoutesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteUserDefinedRoutesFunc(ctx, site, opts...)
+}
+
+// SiteTaggedMACs calls SiteTaggedMACsFunc
+func (m *SiteReader) SiteTaggedMACs(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteTaggedMACResponse, err error) {
+	if m.SiteTaggedMACsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteTaggedMACsFunc(ctx, site, opts...)
+}
+
+// SiteWLANConfigs calls SiteWLANConfigsFunc
+func (m *SiteReader) SiteWLANConfigs(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteWLANConfigResponse, err error) {
+	if m.SiteWLANConfigsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteWLANConfigsFunc(ctx, site, opts...)
+}
+
+// SiteWLANGroups calls SiteWLANGroupsFunc
+func (m *SiteReader) SiteWLANGroups(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteWLANGroupResponse, err error) {
+	if m.SiteWLANGroupsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteWLANGroupsFunc(ctx, site, opts...)
+}
+
+// SiteRougeAccessPoints calls SiteRougeAccessPointsFunc
+func (m *SiteReader) SiteRougeAccessPoints(ctx context.Context, site string, seenWithinHours int, opts ...unifi.RequestOption) (r0 *unifi.SiteRougeAccessPointResponse, err error) {
+	if m.SiteRougeAccessPointsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteRougeAccessPointsFunc(ctx, site, seenWithinHours, opts...)
+}
+
+// SiteRougeKnownAccessPoints calls SiteRougeKnownAccessPointsFunc
+func (m *SiteReader) SiteRougeKnownAccessPoints(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteRougeAccessPointResponse, err error) {
+	if m.SiteRougeKnownAccessPointsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteRougeKnownAccessPointsFunc(ctx, site, opts...)
+}
+
+// ListDashboardMetrics calls ListDashboardMetricsFunc
+func (m *SiteReader) ListDashboardMetrics(ctx context.Context, site string, scale5Min bool, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListDashboardMetricsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListDashboardMetricsFunc(ctx, site, scale5Min, opts...)
+}
+
+// SiteManager is a mock of unifi.SiteManager
+type SiteManager struct {
+	AddSiteFunc                   func(ctx context.Context, site string, name string, description string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpdateSiteFunc                func(ctx context.Context, site string, description string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DeleteSiteFunc                func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteCountryFunc            func(ctx context.Context, site string, siteID string, configID string, country unifi.SiteCountryCode, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteTimezoneFunc           func(ctx context.Context, site string, siteID string, configID string, timezone string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteSNMPFunc               func(ctx context.Context, site string, siteID string, configID string, community string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteManagementConfigFunc   func(ctx context.Context, site string, siteID string, configID string, config unifi.SiteManagementConfig, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteGuestAccessConfigFunc  func(ctx context.Context, site string, siteID string, configID string, config unifi.SiteGuestAccessConfig, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteNTPConfigFunc          func(ctx context.Context, site string, siteID string, configID string, config unifi.SiteNTPConfig, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetSiteConnectivityConfigFunc func(ctx context.Context, site string, siteID string, configID string, uplinkType string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	GetSiteAdminsFunc             func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	InviteSiteAdminFunc           func(ctx context.Context, site string, name string, email string, disableSSO bool, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	AssignExistingSiteAdminFunc   func(ctx context.Context, site string, adminID string, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	RevokeSiteAdminFunc           func(ctx context.Context, site string, adminID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ResetDPICountersFunc          func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListBackupsFunc               func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	CreateBackupFunc              func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DeleteBackupFunc              func(ctx context.Context, site string, filename string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.SiteManager = (*SiteManager)(nil)
+
+// AddSite calls AddSiteFunc
+func (m *SiteManager) AddSite(ctx context.Context, site string, name string, description string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.AddSiteFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AddSiteFunc(ctx, site, name, description, opts...)
+}
+
+// UpdateSite calls UpdateSiteFunc
+func (m *SiteManager) UpdateSite(ctx context.Context, site string, description string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpdateSiteFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateSiteFunc(ctx, site, description, opts...)
+}
+
+// DeleteSite calls DeleteSiteFunc
+func (m *SiteManager) DeleteSite(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteSiteFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteSiteFunc(ctx, site, opts...)
+}
+
+// SetSiteCountry calls SetSiteCountryFunc
+func (m *SiteManager) SetSiteCountry(ctx context.Context, site string, siteID string, configID string, country unifi.SiteCountryCode, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteCountryFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteCountryFunc(ctx, site, siteID, configID, country, opts...)
+}
+
+// SetSiteTimezone calls SetSiteTimezoneFunc
+func (m *SiteManager) SetSiteTimezone(ctx context.Context, site string, siteID string, configID string, timezone string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteTimezoneFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteTimezoneFunc(ctx, site, siteID, configID, timezone, opts...)
+}
+
+// SetSiteSNMP calls SetSiteSNMPFunc
+func (m *SiteManager) SetSiteSNMP(ctx context.Context, site string, siteID string, configID string, community string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteSNMPFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteSNMPFunc(ctx, site, siteID, configID, community, opts...)
+}
+
+// SetSiteManagementConfig calls SetSiteManagementConfigFunc
+func (m *SiteManager) SetSiteManagementConfig(ctx context.Context, site string, siteID string, configID string, config unifi.SiteManagementConfig, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteManagementConfigFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteManagementConfigFunc(ctx, site, siteID, configID, config, opts...)
+}
+
+// SetSiteGuestAccessConfig calls SetSiteGuestAccessConfigFunc
+func (m *SiteManager) SetSiteGuestAccessConfig(ctx context.Context, site string, siteID string, configID string, config unifi.SiteGuestAccessConfig, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteGuestAccessConfigFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteGuestAccessConfigFunc(ctx, site, siteID, configID, config, opts...)
+}
+
+// SetSiteNTPConfig calls SetSiteNTPConfigFunc
+func (m *SiteManager) SetSiteNTPConfig(ctx context.Context, site string, siteID string, configID string, config unifi.SiteNTPConfig, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteNTPConfigFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteNTPConfigFunc(ctx, site, siteID, configID, config, opts...)
+}
+
+// SetSiteConnectivityConfig calls SetSiteConnectivityConfigFunc
+func (m *SiteManager) SetSiteConnectivityConfig(ctx context.Context, site string, siteID string, configID string, uplinkType string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetSiteConnectivityConfigFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetSiteConnectivityConfigFunc(ctx, site, siteID, configID, uplinkType, opts...)
+}
+
+// GetSiteAdmins calls GetSiteAdminsFunc
+func (m *SiteManager) GetSiteAdmins(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.GetSiteAdminsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetSiteAdminsFunc(ctx, site, opts...)
+}
+
+// InviteSiteAdmin calls InviteSiteAdminFunc
+func (m *SiteManager) InviteSiteAdmin(ctx context.Context, site string, name string, email string, disableSSO bool, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.InviteSiteAdminFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.InviteSiteAdminFunc(ctx, site, name, email, disableSSO, readOnly, deviceAdoptPermission, deviceRestartPermission, opts...)
+}
+
+// AssignExistingSiteAdmin calls AssignExistingSiteAdminFunc
+func (m *SiteManager) AssignExistingSiteAdmin(ctx context.Context, site string, adminID string, readOnly bool, deviceAdoptPermission bool, deviceRestartPermission bool, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.AssignExistingSiteAdminFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AssignExistingSiteAdminFunc(ctx, site, adminID, readOnly, deviceAdoptPermission, deviceRestartPermission, opts...)
+}
+
+// RevokeSiteAdmin calls RevokeSiteAdminFunc
+func (m *SiteManager) RevokeSiteAdmin(ctx context.Context, site string, adminID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.RevokeSiteAdminFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.RevokeSiteAdminFunc(ctx, site, adminID, opts...)
+}
+
+// ResetDPICounters calls ResetDPICountersFunc
+func (m *SiteManager) ResetDPICounters(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ResetDPICountersFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ResetDPICountersFunc(ctx, site, opts...)
+}
+
+// ListBackups calls ListBackupsFunc
+func (m *SiteManager) ListBackups(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListBackupsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListBackupsFunc(ctx, site, opts...)
+}
+
+// CreateBackup calls CreateBackupFunc
+func (m *SiteManager) CreateBackup(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateBackupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateBackupFunc(ctx, site, opts...)
+}
+
+// DeleteBackup calls DeleteBackupFunc
+func (m *SiteManager) DeleteBackup(ctx context.Context, site string, filename string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteBackupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteBackupFunc(ctx, site, filename, opts...)
+}
+
+// DeviceManager is a mock of unifi.DeviceManager
+type DeviceManager struct {
+	SiteDevicesBasicFunc               func(ctx context.Context, site string, typeFilter string, opts ...unifi.RequestOption) (*unifi.SiteDeviceBasicResponse, error)
+	SiteDevicesDetailedFunc            func(ctx context.Context, site string, filterMACs ...string) (*unifi.SiteDeviceDetailedResponse, error)
+	SiteDevicesDetailedWithOptionsFunc func(ctx context.Context, site string, filterMACs []string, opts ...unifi.RequestOption) (*unifi.SiteDeviceDetailedResponse, error)
+	AdoptDeviceFunc                    func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	RestartDeviceFunc                  func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ForceProvisionDeviceFunc           func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	PowerCycleDeviceFunc               func(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	StartSpeedTestFunc                 func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SpeedTestStatusFunc                func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetLocateDeviceFunc                func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UnsetLocateDeviceFunc              func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpgradeDeviceFunc                  func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpgradeExternalDeviceFunc          func(ctx context.Context, site string, mac string, firmwareURL string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SpectrumScanDeviceFunc             func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	MoveDeviceFunc                     func(ctx context.Context, site string, mac string, newSiteID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DeleteDeviceFunc                   func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.DeviceManager = (*DeviceManager)(nil)
+
+// SiteDevicesBasic calls SiteDevicesBasicFunc
+func (m *DeviceManager) SiteDevicesBasic(ctx context.Context, site string, typeFilter string, opts ...unifi.RequestOption) (r0 *unifi.SiteDeviceBasicResponse, err error) {
+	if m.SiteDevicesBasicFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteDevicesBasicFunc(ctx, site, typeFilter, opts...)
+}
+
+// SiteDevicesDetailed calls SiteDevicesDetailedFunc
+func (m *DeviceManager) SiteDevicesDetailed(ctx context.Context, site string, filterMACs ...string) (r0 *unifi.SiteDeviceDetailedResponse, err error) {
+	if m.SiteDevicesDetailedFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteDevicesDetailedFunc(ctx, site, filterMACs...)
+}
+
+// SiteDevicesDetailedWithOptions calls SiteDevicesDetailedWithOptionsFunc
+func (m *DeviceManager) SiteDevicesDetailedWithOptions(ctx context.Context, site string, filterMACs []string, opts ...unifi.RequestOption) (r0 *unifi.SiteDeviceDetailedResponse, err error) {
+	if m.SiteDevicesDetailedWithOptionsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteDevicesDetailedWithOptionsFunc(ctx, site, filterMACs, opts...)
+}
+
+// AdoptDevice calls AdoptDeviceFunc
+func (m *DeviceManager) AdoptDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.AdoptDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AdoptDeviceFunc(ctx, site, mac, opts...)
+}
+
+// RestartDevice calls RestartDeviceFunc
+func (m *DeviceManager) RestartDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.RestartDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.RestartDeviceFunc(ctx, site, mac, opts...)
+}
+
+// ForceProvisionDevice calls ForceProvisionDeviceFunc
+func (m *DeviceManager) ForceProvisionDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ForceProvisionDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ForceProvisionDeviceFunc(ctx, site, mac, opts...)
+}
+
+// PowerCycleDevice calls PowerCycleDeviceFunc
+func (m *DeviceManager) PowerCycleDevice(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.PowerCycleDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.PowerCycleDeviceFunc(ctx, site, mac, portIdx, opts...)
+}
+
+// StartSpeedTest calls StartSpeedTestFunc
+func (m *DeviceManager) StartSpeedTest(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.StartSpeedTestFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.StartSpeedTestFunc(ctx, site, opts...)
+}
+
+// SpeedTestStatus calls SpeedTestStatusFunc
+func (m *DeviceManager) SpeedTestStatus(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SpeedTestStatusFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SpeedTestStatusFunc(ctx, site, opts...)
+}
+
+// SetLocateDevice calls SetLocateDeviceFunc
+func (m *DeviceManager) SetLocateDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetLocateDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetLocateDeviceFunc(ctx, site, mac, opts...)
+}
+
+// UnsetLocateDevice calls UnsetLocateDeviceFunc
+func (m *DeviceManager) UnsetLocateDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UnsetLocateDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UnsetLocateDeviceFunc(ctx, site, mac, opts...)
+}
+
+// UpgradeDevice calls UpgradeDeviceFunc
+func (m *DeviceManager) UpgradeDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpgradeDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpgradeDeviceFunc(ctx, site, mac, opts...)
+}
+
+// UpgradeExternalDevice calls UpgradeExternalDeviceFunc
+func (m *DeviceManager) UpgradeExternalDevice(ctx context.Context, site string, mac string, firmwareURL string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpgradeExternalDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpgradeExternalDeviceFunc(ctx, site, mac, firmwareURL, opts...)
+}
+
+// SpectrumScanDevice calls SpectrumScanDeviceFunc
+func (m *DeviceManager) SpectrumScanDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SpectrumScanDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SpectrumScanDeviceFunc(ctx, site, mac, opts...)
+}
+
+// MoveDevice calls MoveDeviceFunc
+func (m *DeviceManager) MoveDevice(ctx context.Context, site string, mac string, newSiteID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.MoveDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.MoveDeviceFunc(ctx, site, mac, newSiteID, opts...)
+}
+
+// DeleteDevice calls DeleteDeviceFunc
+func (m *DeviceManager) DeleteDevice(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteDeviceFunc(ctx, site, mac, opts...)
+}
+
+// ClientManager is a mock of unifi.ClientManager
+type ClientManager struct {
+	SiteActiveClientsFunc         func(ctx context.Context, site string, filterMac string, opts ...unifi.RequestOption) (*unifi.SiteActiveClientsResponse, error)
+	ClientDetailsFunc             func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpdateClientFixedIPFunc       func(ctx context.Context, site string, clientID string, useFixedIP bool, networkID *string, fixedIP *string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	CreateNewUserClientDeviceFunc func(ctx context.Context, site string, mac string, userGroupID string, name string, note string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetUserClientDeviceNoteFunc   func(ctx context.Context, site string, userID string, note string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetUserClientDeviceNameFunc   func(ctx context.Context, site string, userID string, name string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	BlockSTAFunc                  func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UnblockSTAFunc                func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	KickSTAFunc                   func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ForgetSTAFunc                 func(ctx context.Context, site string, macs ...string) (*unifi.GenericResponse, error)
+	ForgetSTAWithOptionsFunc      func(ctx context.Context, site string, macs []string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListUserGroupsFunc            func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	CreateUserGroupFunc           func(ctx context.Context, site string, siteID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpdateUserGroupFunc           func(ctx context.Context, site string, siteID string, groupID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DeleteUserGroupFunc           func(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	AssignClientUserGroupFunc     func(ctx context.Context, site string, clientID string, groupID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListLoginSessionsFunc         func(ctx context.Context, site string, sessionType unifi.SessionType, startTime time.Time, endTime time.Time, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListLatestSessionsFunc        func(ctx context.Context, site string, mac string, order unifi.SiteSessionOrder, offset int, limit int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListAuthorizationsFunc        func(ctx context.Context, site string, startTime time.Time, endTime time.Time, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListAllUsersFunc              func(ctx context.Context, site string, withinHours int, offset int, limit int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.ClientManager = (*ClientManager)(nil)
+
+// SiteActiveClients calls SiteActiveClientsFunc
+func (m *ClientManager) SiteActiveClients(ctx context.Context, site string, filterMac string, opts ...unifi.RequestOption) (r0 *unifi.SiteActiveClientsResponse, err error) {
+	if m.SiteActiveClientsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteActiveClientsFunc(ctx, site, filterMac, opts...)
+}
+
+// ClientDetails calls ClientDetailsFunc
+func (m *ClientManager) ClientDetails(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ClientDetailsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ClientDetailsFunc(ctx, site, mac, opts...)
+}
+
+// UpdateClientFixedIP calls UpdateClientFixedIPFunc
+func (m *ClientManager) UpdateClientFixedIP(ctx context.Context, site string, clientID string, useFixedIP bool, networkID *string, fixedIP *string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpdateClientFixedIPFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateClientFixedIPFunc(ctx, site, clientID, useFixedIP, networkID, fixedIP, opts...)
+}
+
+// CreateNewUserClientDevice calls CreateNewUserClientDeviceFunc
+func (m *ClientManager) CreateNewUserClientDevice(ctx context.Context, site string, mac string, userGroupID string, name string, note string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateNewUserClientDeviceFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateNewUserClientDeviceFunc(ctx, site, mac, userGroupID, name, note, opts...)
+}
+
+// SetUserClientDeviceNote calls SetUserClientDeviceNoteFunc
+func (m *ClientManager) SetUserClientDeviceNote(ctx context.Context, site string, userID string, note string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetUserClientDeviceNoteFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetUserClientDeviceNoteFunc(ctx, site, userID, note, opts...)
+}
+
+// SetUserClientDeviceName calls SetUserClientDeviceNameFunc
+func (m *ClientManager) SetUserClientDeviceName(ctx context.Context, site string, userID string, name string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetUserClientDeviceNameFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetUserClientDeviceNameFunc(ctx, site, userID, name, opts...)
+}
+
+// BlockSTA calls BlockSTAFunc
+func (m *ClientManager) BlockSTA(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.BlockSTAFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.BlockSTAFunc(ctx, site, mac, opts...)
+}
+
+// UnblockSTA calls UnblockSTAFunc
+func (m *ClientManager) UnblockSTA(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UnblockSTAFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UnblockSTAFunc(ctx, site, mac, opts...)
+}
+
+// KickSTA calls KickSTAFunc
+func (m *ClientManager) KickSTA(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.KickSTAFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.KickSTAFunc(ctx, site, mac, opts...)
+}
+
+// ForgetSTA calls ForgetSTAFunc
+func (m *ClientManager) ForgetSTA(ctx context.Context, site string, macs ...string) (r0 *unifi.GenericResponse, err error) {
+	if m.ForgetSTAFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ForgetSTAFunc(ctx, site, macs...)
+}
+
+// ForgetSTAWithOptions calls ForgetSTAWithOptionsFunc
+func (m *ClientManager) ForgetSTAWithOptions(ctx context.Context, site string, macs []string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ForgetSTAWithOptionsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ForgetSTAWithOptionsFunc(ctx, site, macs, opts...)
+}
+
+// ListUserGroups calls ListUserGroupsFunc
+func (m *ClientManager) ListUserGroups(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListUserGroupsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListUserGroupsFunc(ctx, site, opts...)
+}
+
+// CreateUserGroup calls CreateUserGroupFunc
+func (m *ClientManager) CreateUserGroup(ctx context.Context, site string, siteID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateUserGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateUserGroupFunc(ctx, site, siteID, name, downloadBandwidth, uploadBandwidth, opts...)
+}
+
+// UpdateUserGroup calls UpdateUserGroupFunc
+func (m *ClientManager) UpdateUserGroup(ctx context.Context, site string, siteID string, groupID string, name string, downloadBandwidth int, uploadBandwidth int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpdateUserGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateUserGroupFunc(ctx, site, siteID, groupID, name, downloadBandwidth, uploadBandwidth, opts...)
+}
+
+// DeleteUserGroup calls DeleteUserGroupFunc
+func (m *ClientManager) DeleteUserGroup(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteUserGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteUserGroupFunc(ctx, site, groupID, opts...)
+}
+
+// AssignClientUserGroup calls AssignClientUserGroupFunc
+func (m *ClientManager) AssignClientUserGroup(ctx context.Context, site string, clientID string, groupID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.AssignClientUserGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AssignClientUserGroupFunc(ctx, site, clientID, groupID, opts...)
+}
+
+// ListLoginSessions calls ListLoginSessionsFunc
+func (m *ClientManager) ListLoginSessions(ctx context.Context, site string, sessionType unifi.SessionType, startTime time.Time, endTime time.Time, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListLoginSessionsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListLoginSessionsFunc(ctx, site, sessionType, startTime, endTime, mac, opts...)
+}
+
+// ListLatestSessions calls ListLatestSessionsFunc
+func (m *ClientManager) ListLatestSessions(ctx context.Context, site string, mac string, order unifi.SiteSessionOrder, offset int, limit int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListLatestSessionsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListLatestSessionsFunc(ctx, site, mac, order, offset, limit, opts...)
+}
+
+// ListAuthorizations calls ListAuthorizationsFunc
+func (m *ClientManager) ListAuthorizations(ctx context.Context, site string, startTime time.Time, endTime time.Time, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListAuthorizationsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListAuthorizationsFunc(ctx, site, startTime, endTime, opts...)
+}
+
+// ListAllUsers calls ListAllUsersFunc
+func (m *ClientManager) ListAllUsers(ctx context.Context, site string, withinHours int, offset int, limit int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListAllUsersFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListAllUsersFunc(ctx, site, withinHours, offset, limit, opts...)
+}
+
+// GuestManager is a mock of unifi.GuestManager
+type GuestManager struct {
+	AuthorizeWiFiGuestFunc      func(ctx context.Context, site string, mac string, duration time.Duration, wifiGuestConfig *unifi.WifiGuestConfig, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UnAuthorizeWiFiGuestFunc    func(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListWiFiGuestsFunc          func(ctx context.Context, site string, withinHours int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListWiFiGuestVouchersFunc   func(ctx context.Context, site string, createTime time.Time, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListWiFiGuestPaymentsFunc   func(ctx context.Context, site string, withinHours int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	CreateWifiGuestOperatorFunc func(ctx context.Context, site string, name string, password string, note string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ListWiFiGuestOperatorsFunc  func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	CreateWifiGuestVoucherFunc  func(ctx context.Context, site string, cfg unifi.VoucherConfig, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	RevokeWifiGuestVoucherFunc  func(ctx context.Context, site string, voucherID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ExtendWifiGuestValidityFunc func(ctx context.Context, site string, guestID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.GuestManager = (*GuestManager)(nil)
+
+// AuthorizeWiFiGuest calls AuthorizeWiFiGuestFunc
+func (m *GuestManager) AuthorizeWiFiGuest(ctx context.Context, site string, mac string, duration time.Duration, wifiGuestConfig *unifi.WifiGuestConfig, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.AuthorizeWiFiGuestFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.AuthorizeWiFiGuestFunc(ctx, site, mac, duration, wifiGuestConfig, opts...)
+}
+
+// UnAuthorizeWiFiGuest calls UnAuthorizeWiFiGuestFunc
+func (m *GuestManager) UnAuthorizeWiFiGuest(ctx context.Context, site string, mac string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UnAuthorizeWiFiGuestFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UnAuthorizeWiFiGuestFunc(ctx, site, mac, opts...)
+}
+
+// ListWiFiGuests calls ListWiFiGuestsFunc
+func (m *GuestManager) ListWiFiGuests(ctx context.Context, site string, withinHours int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListWiFiGuestsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListWiFiGuestsFunc(ctx, site, withinHours, opts...)
+}
+
+// ListWiFiGuestVouchers calls ListWiFiGuestVouchersFunc
+func (m *GuestManager) ListWiFiGuestVouchers(ctx context.Context, site string, createTime time.Time, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListWiFiGuestVouchersFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListWiFiGuestVouchersFunc(ctx, site, createTime, opts...)
+}
+
+// ListWiFiGuestPayments calls ListWiFiGuestPaymentsFunc
+func (m *GuestManager) ListWiFiGuestPayments(ctx context.Context, site string, withinHours int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListWiFiGuestPaymentsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListWiFiGuestPaymentsFunc(ctx, site, withinHours, opts...)
+}
+
+// CreateWifiGuestOperator calls CreateWifiGuestOperatorFunc
+func (m *GuestManager) CreateWifiGuestOperator(ctx context.Context, site string, name string, password string, note string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateWifiGuestOperatorFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateWifiGuestOperatorFunc(ctx, site, name, password, note, opts...)
+}
+
+// ListWiFiGuestOperators calls ListWiFiGuestOperatorsFunc
+func (m *GuestManager) ListWiFiGuestOperators(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ListWiFiGuestOperatorsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListWiFiGuestOperatorsFunc(ctx, site, opts...)
+}
+
+// CreateWifiGuestVoucher calls CreateWifiGuestVoucherFunc
+func (m *GuestManager) CreateWifiGuestVoucher(ctx context.Context, site string, cfg unifi.VoucherConfig, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateWifiGuestVoucherFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateWifiGuestVoucherFunc(ctx, site, cfg, opts...)
+}
+
+// RevokeWifiGuestVoucher calls RevokeWifiGuestVoucherFunc
+func (m *GuestManager) RevokeWifiGuestVoucher(ctx context.Context, site string, voucherID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.RevokeWifiGuestVoucherFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.RevokeWifiGuestVoucherFunc(ctx, site, voucherID, opts...)
+}
+
+// ExtendWifiGuestValidity calls ExtendWifiGuestValidityFunc
+func (m *GuestManager) ExtendWifiGuestValidity(ctx context.Context, site string, guestID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ExtendWifiGuestValidityFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ExtendWifiGuestValidityFunc(ctx, site, guestID, opts...)
+}
+
+// FirewallManager is a mock of unifi.FirewallManager
+type FirewallManager struct {
+	SiteFirewallRulesFunc    func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteFirewallRuleResponse, error)
+	ListFirewallRulesFunc    func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.FirewallRuleResponse, error)
+	GetFirewallRuleFunc      func(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (*unifi.FirewallRule, error)
+	CreateFirewallRuleFunc   func(ctx context.Context, site string, rule unifi.FirewallRule, opts ...unifi.RequestOption) (*unifi.FirewallRuleResponse, error)
+	UpdateFirewallRuleFunc   func(ctx context.Context, site string, rule unifi.FirewallRule, opts ...unifi.RequestOption) (*unifi.FirewallRuleResponse, error)
+	DeleteFirewallRuleFunc   func(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	EnableFirewallRuleFunc   func(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (*unifi.FirewallRuleResponse, error)
+	DisableFirewallRuleFunc  func(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (*unifi.FirewallRuleResponse, error)
+	ReorderFirewallRulesFunc func(ctx context.Context, site string, ruleset unifi.FirewallRuleset, ruleIDs []string, opts ...unifi.RequestOption) error
+	SiteFirewallGroupsFunc   func(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (*unifi.SiteFirewallGroupResponse, error)
+	CreateFirewallGroupFunc  func(ctx context.Context, site string, name string, groupType unifi.FirewallGroupType, groupMembers unifi.FirewallGroupMembers, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	UpdateFirewallGroupFunc  func(ctx context.Context, site string, siteID string, groupID string, name string, groupType unifi.FirewallGroupType, groupMembers unifi.FirewallGroupMembers, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DeleteFirewallGroupFunc  func(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.FirewallManager = (*FirewallManager)(nil)
+
+// SiteFirewallRules calls SiteFirewallRulesFunc
+func (m *FirewallManager) SiteFirewallRules(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.SiteFirewallRuleResponse, err error) {
+	if m.SiteFirewallRulesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteFirewallRulesFunc(ctx, site, opts...)
+}
+
+// ListFirewallRules calls ListFirewallRulesFunc
+func (m *FirewallManager) ListFirewallRules(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.FirewallRuleResponse, err error) {
+	if m.ListFirewallRulesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListFirewallRulesFunc(ctx, site, opts...)
+}
+
+// GetFirewallRule calls GetFirewallRuleFunc
+func (m *FirewallManager) GetFirewallRule(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (r0 *unifi.FirewallRule, err error) {
+	if m.GetFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetFirewallRuleFunc(ctx, site, ruleID, opts...)
+}
+
+// CreateFirewallRule calls CreateFirewallRuleFunc
+func (m *FirewallManager) CreateFirewallRule(ctx context.Context, site string, rule unifi.FirewallRule, opts ...unifi.RequestOption) (r0 *unifi.FirewallRuleResponse, err error) {
+	if m.CreateFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateFirewallRuleFunc(ctx, site, rule, opts...)
+}
+
+// UpdateFirewallRule calls UpdateFirewallRuleFunc
+func (m *FirewallManager) UpdateFirewallRule(ctx context.Context, site string, rule unifi.FirewallRule, opts ...unifi.RequestOption) (r0 *unifi.FirewallRuleResponse, err error) {
+	if m.UpdateFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateFirewallRuleFunc(ctx, site, rule, opts...)
+}
+
+// DeleteFirewallRule calls DeleteFirewallRuleFunc
+func (m *FirewallManager) DeleteFirewallRule(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteFirewallRuleFunc(ctx, site, ruleID, opts...)
+}
+
+// EnableFirewallRule calls EnableFirewallRuleFunc
+func (m *FirewallManager) EnableFirewallRule(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (r0 *unifi.FirewallRuleResponse, err error) {
+	if m.EnableFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.EnableFirewallRuleFunc(ctx, site, ruleID, opts...)
+}
+
+// DisableFirewallRule calls DisableFirewallRuleFunc
+func (m *FirewallManager) DisableFirewallRule(ctx context.Context, site string, ruleID string, opts ...unifi.RequestOption) (r0 *unifi.FirewallRuleResponse, err error) {
+	if m.DisableFirewallRuleFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DisableFirewallRuleFunc(ctx, site, ruleID, opts...)
+}
+
+// ReorderFirewallRules calls ReorderFirewallRulesFunc
+func (m *FirewallManager) ReorderFirewallRules(ctx context.Context, site string, ruleset unifi.FirewallRuleset, ruleIDs []string, opts ...unifi.RequestOption) (err error) {
+	if m.ReorderFirewallRulesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ReorderFirewallRulesFunc(ctx, site, ruleset, ruleIDs, opts...)
+}
+
+// SiteFirewallGroups calls SiteFirewallGroupsFunc
+func (m *FirewallManager) SiteFirewallGroups(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (r0 *unifi.SiteFirewallGroupResponse, err error) {
+	if m.SiteFirewallGroupsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteFirewallGroupsFunc(ctx, site, groupID, opts...)
+}
+
+// CreateFirewallGroup calls CreateFirewallGroupFunc
+func (m *FirewallManager) CreateFirewallGroup(ctx context.Context, site string, name string, groupType unifi.FirewallGroupType, groupMembers unifi.FirewallGroupMembers, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.CreateFirewallGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateFirewallGroupFunc(ctx, site, name, groupType, groupMembers, opts...)
+}
+
+// UpdateFirewallGroup calls UpdateFirewallGroupFunc
+func (m *FirewallManager) UpdateFirewallGroup(ctx context.Context, site string, siteID string, groupID string, name string, groupType unifi.FirewallGroupType, groupMembers unifi.FirewallGroupMembers, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.UpdateFirewallGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateFirewallGroupFunc(ctx, site, siteID, groupID, name, groupType, groupMembers, opts...)
+}
+
+// DeleteFirewallGroup calls DeleteFirewallGroupFunc
+func (m *FirewallManager) DeleteFirewallGroup(ctx context.Context, site string, groupID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteFirewallGroupFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteFirewallGroupFunc(ctx, site, groupID, opts...)
+}
+
+// NetworkManager is a mock of unifi.NetworkManager
+type NetworkManager struct {
+	ListNetworksFunc  func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.NetworkConfResponse, error)
+	GetNetworkFunc    func(ctx context.Context, site string, networkID string, opts ...unifi.RequestOption) (*unifi.NetworkConf, error)
+	CreateNetworkFunc func(ctx context.Context, site string, network unifi.NetworkConf, opts ...unifi.RequestOption) (*unifi.NetworkConfResponse, error)
+	UpdateNetworkFunc func(ctx context.Context, site string, network unifi.NetworkConf, opts ...unifi.RequestOption) (*unifi.NetworkConfResponse, error)
+	DeleteNetworkFunc func(ctx context.Context, site string, networkID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.NetworkManager = (*NetworkManager)(nil)
+
+// ListNetworks calls ListNetworksFunc
+func (m *NetworkManager) ListNetworks(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.NetworkConfResponse, err error) {
+	if m.ListNetworksFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListNetworksFunc(ctx, site, opts...)
+}
+
+// GetNetwork calls GetNetworkFunc
+func (m *NetworkManager) GetNetwork(ctx context.Context, site string, networkID string, opts ...unifi.RequestOption) (r0 *unifi.NetworkConf, err error) {
+	if m.GetNetworkFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetNetworkFunc(ctx, site, networkID, opts...)
+}
+
+// CreateNetwork calls CreateNetworkFunc
+func (m *NetworkManager) CreateNetwork(ctx context.Context, site string, network unifi.NetworkConf, opts ...unifi.RequestOption) (r0 *unifi.NetworkConfResponse, err error) {
+	if m.CreateNetworkFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateNetworkFunc(ctx, site, network, opts...)
+}
+
+// UpdateNetwork calls UpdateNetworkFunc
+func (m *NetworkManager) UpdateNetwork(ctx context.Context, site string, network unifi.NetworkConf, opts ...unifi.RequestOption) (r0 *unifi.NetworkConfResponse, err error) {
+	if m.UpdateNetworkFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateNetworkFunc(ctx, site, network, opts...)
+}
+
+// DeleteNetwork calls DeleteNetworkFunc
+func (m *NetworkManager) DeleteNetwork(ctx context.Context, site string, networkID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteNetworkFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteNetworkFunc(ctx, site, networkID, opts...)
+}
+
+// PortForwardManager is a mock of unifi.PortForwardManager
+type PortForwardManager struct {
+	ListPortForwardsFunc   func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.PortForwardResponse, error)
+	GetPortForwardFunc     func(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (*unifi.PortForward, error)
+	CreatePortForwardFunc  func(ctx context.Context, site string, forward unifi.PortForward, opts ...unifi.RequestOption) (*unifi.PortForwardResponse, error)
+	UpdatePortForwardFunc  func(ctx context.Context, site string, forward unifi.PortForward, opts ...unifi.RequestOption) (*unifi.PortForwardResponse, error)
+	DeletePortForwardFunc  func(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	EnablePortForwardFunc  func(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (*unifi.PortForwardResponse, error)
+	DisablePortForwardFunc func(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (*unifi.PortForwardResponse, error)
+}
+
+var _ unifi.PortForwardManager = (*PortForwardManager)(nil)
+
+// ListPortForwards calls ListPortForwardsFunc
+func (m *PortForwardManager) ListPortForwards(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.PortForwardResponse, err error) {
+	if m.ListPortForwardsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListPortForwardsFunc(ctx, site, opts...)
+}
+
+// GetPortForward calls GetPortForwardFunc
+func (m *PortForwardManager) GetPortForward(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (r0 *unifi.PortForward, err error) {
+	if m.GetPortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetPortForwardFunc(ctx, site, forwardID, opts...)
+}
+
+// CreatePortForward calls CreatePortForwardFunc
+func (m *PortForwardManager) CreatePortForward(ctx context.Context, site string, forward unifi.PortForward, opts ...unifi.RequestOption) (r0 *unifi.PortForwardResponse, err error) {
+	if m.CreatePortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreatePortForwardFunc(ctx, site, forward, opts...)
+}
+
+// UpdatePortForward calls UpdatePortForwardFunc
+func (m *PortForwardManager) UpdatePortForward(ctx context.Context, site string, forward unifi.PortForward, opts ...unifi.RequestOption) (r0 *unifi.PortForwardResponse, err error) {
+	if m.UpdatePortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdatePortForwardFunc(ctx, site, forward, opts...)
+}
+
+// DeletePortForward calls DeletePortForwardFunc
+func (m *PortForwardManager) DeletePortForward(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeletePortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeletePortForwardFunc(ctx, site, forwardID, opts...)
+}
+
+// EnablePortForward calls EnablePortForwardFunc
+func (m *PortForwardManager) EnablePortForward(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (r0 *unifi.PortForwardResponse, err error) {
+	if m.EnablePortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.EnablePortForwardFunc(ctx, site, forwardID, opts...)
+}
+
+// DisablePortForward calls DisablePortForwardFunc
+func (m *PortForwardManager) DisablePortForward(ctx context.Context, site string, forwardID string, opts ...unifi.RequestOption) (r0 *unifi.PortForwardResponse, err error) {
+	if m.DisablePortForwardFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DisablePortForwardFunc(ctx, site, forwardID, opts...)
+}
+
+// SwitchPortManager is a mock of unifi.SwitchPortManager
+type SwitchPortManager struct {
+	ListPortProfilesFunc  func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.PortProfileResponse, error)
+	GetPortProfileFunc    func(ctx context.Context, site string, profileID string, opts ...unifi.RequestOption) (*unifi.PortProfile, error)
+	CreatePortProfileFunc func(ctx context.Context, site string, profile unifi.PortProfile, opts ...unifi.RequestOption) (*unifi.PortProfileResponse, error)
+	UpdatePortProfileFunc func(ctx context.Context, site string, profile unifi.PortProfile, opts ...unifi.RequestOption) (*unifi.PortProfileResponse, error)
+	DeletePortProfileFunc func(ctx context.Context, site string, profileID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetPortOverrideFunc   func(ctx context.Context, site string, mac string, override unifi.PortOverride, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	ClearPortOverrideFunc func(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetPortProfileFunc    func(ctx context.Context, site string, mac string, portIdx int, profileID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	SetPortPoEModeFunc    func(ctx context.Context, site string, mac string, portIdx int, mode unifi.PoEMode, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	DisablePortFunc       func(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	EnablePortFunc        func(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+}
+
+var _ unifi.SwitchPortManager = (*SwitchPortManager)(nil)
+
+// ListPortProfiles calls ListPortProfilesFunc
+func (m *SwitchPortManager) ListPortProfiles(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.PortProfileResponse, err error) {
+	if m.ListPortProfilesFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListPortProfilesFunc(ctx, site, opts...)
+}
+
+// GetPortProfile calls GetPortProfileFunc
+func (m *SwitchPortManager) GetPortProfile(ctx context.Context, site string, profileID string, opts ...unifi.RequestOption) (r0 *unifi.PortProfile, err error) {
+	if m.GetPortProfileFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetPortProfileFunc(ctx, site, profileID, opts...)
+}
+
+// CreatePortProfile calls CreatePortProfileFunc
+func (m *SwitchPortManager) CreatePortProfile(ctx context.Context, site string, profile unifi.PortProfile, opts ...unifi.RequestOption) (r0 *unifi.PortProfileResponse, err error) {
+	if m.CreatePortProfileFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreatePortProfileFunc(ctx, site, profile, opts...)
+}
+
+// UpdatePortProfile calls UpdatePortProfileFunc
+func (m *SwitchPortManager) UpdatePortProfile(ctx context.Context, site string, profile unifi.PortProfile, opts ...unifi.RequestOption) (r0 *unifi.PortProfileResponse, err error) {
+	if m.UpdatePortProfileFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdatePortProfileFunc(ctx, site, profile, opts...)
+}
+
+// DeletePortProfile calls DeletePortProfileFunc
+func (m *SwitchPortManager) DeletePortProfile(ctx context.Context, site string, profileID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeletePortProfileFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeletePortProfileFunc(ctx, site, profileID, opts...)
+}
+
+// SetPortOverride calls SetPortOverrideFunc
+func (m *SwitchPortManager) SetPortOverride(ctx context.Context, site string, mac string, override unifi.PortOverride, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetPortOverrideFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetPortOverrideFunc(ctx, site, mac, override, opts...)
+}
+
+// ClearPortOverride calls ClearPortOverrideFunc
+func (m *SwitchPortManager) ClearPortOverride(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.ClearPortOverrideFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ClearPortOverrideFunc(ctx, site, mac, portIdx, opts...)
+}
+
+// SetPortProfile calls SetPortProfileFunc
+func (m *SwitchPortManager) SetPortProfile(ctx context.Context, site string, mac string, portIdx int, profileID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetPortProfileFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetPortProfileFunc(ctx, site, mac, portIdx, profileID, opts...)
+}
+
+// SetPortPoEMode calls SetPortPoEModeFunc
+func (m *SwitchPortManager) SetPortPoEMode(ctx context.Context, site string, mac string, portIdx int, mode unifi.PoEMode, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.SetPortPoEModeFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SetPortPoEModeFunc(ctx, site, mac, portIdx, mode, opts...)
+}
+
+// DisablePort calls DisablePortFunc
+func (m *SwitchPortManager) DisablePort(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DisablePortFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DisablePortFunc(ctx, site, mac, portIdx, opts...)
+}
+
+// EnablePort calls EnablePortFunc
+func (m *SwitchPortManager) EnablePort(ctx context.Context, site string, mac string, portIdx int, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.EnablePortFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.EnablePortFunc(ctx, site, mac, portIdx, opts...)
+}
+
+// WLANManager is a mock of unifi.WLANManager
+type WLANManager struct {
+	ListWLANsFunc   func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.WLANConfResponse, error)
+	GetWLANFunc     func(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (*unifi.WLANConf, error)
+	CreateWLANFunc  func(ctx context.Context, site string, wlan unifi.WLANConf, opts ...unifi.RequestOption) (*unifi.WLANConfResponse, error)
+	UpdateWLANFunc  func(ctx context.Context, site string, wlan unifi.WLANConf, opts ...unifi.RequestOption) (*unifi.WLANConfResponse, error)
+	DeleteWLANFunc  func(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (*unifi.GenericResponse, error)
+	EnableWLANFunc  func(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (*unifi.WLANConfResponse, error)
+	DisableWLANFunc func(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (*unifi.WLANConfResponse, error)
+}
+
+var _ unifi.WLANManager = (*WLANManager)(nil)
+
+// ListWLANs calls ListWLANsFunc
+func (m *WLANManager) ListWLANs(ctx context.Context, site string, opts ...unifi.RequestOption) (r0 *unifi.WLANConfResponse, err error) {
+	if m.ListWLANsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ListWLANsFunc(ctx, site, opts...)
+}
+
+// GetWLAN calls GetWLANFunc
+func (m *WLANManager) GetWLAN(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (r0 *unifi.WLANConf, err error) {
+	if m.GetWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.GetWLANFunc(ctx, site, wlanID, opts...)
+}
+
+// CreateWLAN calls CreateWLANFunc
+func (m *WLANManager) CreateWLAN(ctx context.Context, site string, wlan unifi.WLANConf, opts ...unifi.RequestOption) (r0 *unifi.WLANConfResponse, err error) {
+	if m.CreateWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.CreateWLANFunc(ctx, site, wlan, opts...)
+}
+
+// UpdateWLAN calls UpdateWLANFunc
+func (m *WLANManager) UpdateWLAN(ctx context.Context, site string, wlan unifi.WLANConf, opts ...unifi.RequestOption) (r0 *unifi.WLANConfResponse, err error) {
+	if m.UpdateWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.UpdateWLANFunc(ctx, site, wlan, opts...)
+}
+
+// DeleteWLAN calls DeleteWLANFunc
+func (m *WLANManager) DeleteWLAN(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (r0 *unifi.GenericResponse, err error) {
+	if m.DeleteWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DeleteWLANFunc(ctx, site, wlanID, opts...)
+}
+
+// EnableWLAN calls EnableWLANFunc
+func (m *WLANManager) EnableWLAN(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (r0 *unifi.WLANConfResponse, err error) {
+	if m.EnableWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.EnableWLANFunc(ctx, site, wlanID, opts...)
+}
+
+// DisableWLAN calls DisableWLANFunc
+func (m *WLANManager) DisableWLAN(ctx context.Context, site string, wlanID string, opts ...unifi.RequestOption) (r0 *unifi.WLANConfResponse, err error) {
+	if m.DisableWLANFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.DisableWLANFunc(ctx, site, wlanID, opts...)
+}
+
+// Reporter is a mock of unifi.Reporter
+type Reporter struct {
+	SiteEventsFunc            func(ctx context.Context, site string, historyHours int, offset int, limit int, order unifi.EventSortOrder, opts ...unifi.RequestOption) (*unifi.SiteEventsResponse, error)
+	SiteIPSEventsFunc         func(ctx context.Context, site string, startTime time.Time, endTime time.Time, offset int, limit int, order unifi.EventSortOrder, opts ...unifi.RequestOption) (*unifi.SiteEventsResponse, error)
+	SiteAlarmsFunc            func(ctx context.Context, site string, historyHours int, offset int, limit int, order unifi.EventSortOrder, archived bool, opts ...unifi.RequestOption) (*unifi.SiteAlarmsResponse, error)
+	SiteAlarmsCountFunc       func(ctx context.Context, site string, historyHours int, archived bool, opts ...unifi.RequestOption) (*unifi.SiteAlarmsCountResponse, error)
+	ArchiveAllAlarmsFunc      func(ctx context.Context, site string, opts ...unifi.RequestOption) error
+	SubscribeEventsFunc       func(ctx context.Context, site string) (<-chan unifi.StreamEvent, error)
+	SiteReportFunc            func(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval unifi.ReportInterval, reportType unifi.ReportType, attributes []unifi.ReportAttribute, filterMacs ...string) (*unifi.SiteReportsResponse, error)
+	SiteReportWithOptionsFunc func(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval unifi.ReportInterval, reportType unifi.ReportType, attributes []unifi.ReportAttribute, filterMacs []string, opts ...unifi.RequestOption) (*unifi.SiteReportsResponse, error)
+	EventsIteratorFunc        func(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder) *unifi.EventsIterator
+	IPSEventsIteratorFunc     func(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder) *unifi.EventsIterator
+	AlarmsIteratorFunc        func(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder, archived bool) *unifi.AlarmsIterator
+	SessionsIteratorFunc      func(ctx context.Context, site string, mac string, since time.Time, order unifi.SiteSessionOrder) *unifi.SessionsIterator
+}
+
+var _ unifi.Reporter = (*Reporter)(nil)
+
+// SiteEvents calls SiteEventsFunc
+func (m *Reporter) SiteEvents(ctx context.Context, site string, historyHours int, offset int, limit int, order unifi.EventSortOrder, opts ...unifi.RequestOption) (r0 *unifi.SiteEventsResponse, err error) {
+	if m.SiteEventsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteEventsFunc(ctx, site, historyHours, offset, limit, order, opts...)
+}
+
+// SiteIPSEvents calls SiteIPSEventsFunc
+func (m *Reporter) SiteIPSEvents(ctx context.Context, site string, startTime time.Time, endTime time.Time, offset int, limit int, order unifi.EventSortOrder, opts ...unifi.RequestOption) (r0 *unifi.SiteEventsResponse, err error) {
+	if m.SiteIPSEventsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteIPSEventsFunc(ctx, site, startTime, endTime, offset, limit, order, opts...)
+}
+
+// SiteAlarms calls SiteAlarmsFunc
+func (m *Reporter) SiteAlarms(ctx context.Context, site string, historyHours int, offset int, limit int, order unifi.EventSortOrder, archived bool, opts ...unifi.RequestOption) (r0 *unifi.SiteAlarmsResponse, err error) {
+	if m.SiteAlarmsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteAlarmsFunc(ctx, site, historyHours, offset, limit, order, archived, opts...)
+}
+
+// SiteAlarmsCount calls SiteAlarmsCountFunc
+func (m *Reporter) SiteAlarmsCount(ctx context.Context, site string, historyHours int, archived bool, opts ...unifi.RequestOption) (r0 *unifi.SiteAlarmsCountResponse, err error) {
+	if m.SiteAlarmsCountFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteAlarmsCountFunc(ctx, site, historyHours, archived, opts...)
+}
+
+// ArchiveAllAlarms calls ArchiveAllAlarmsFunc
+func (m *Reporter) ArchiveAllAlarms(ctx context.Context, site string, opts ...unifi.RequestOption) (err error) {
+	if m.ArchiveAllAlarmsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.ArchiveAllAlarmsFunc(ctx, site, opts...)
+}
+
+// SubscribeEvents calls SubscribeEventsFunc
+func (m *Reporter) SubscribeEvents(ctx context.Context, site string) (r0 <-chan unifi.StreamEvent, err error) {
+	if m.SubscribeEventsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SubscribeEventsFunc(ctx, site)
+}
+
+// SiteReport calls SiteReportFunc
+func (m *Reporter) SiteReport(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval unifi.ReportInterval, reportType unifi.ReportType, attributes []unifi.ReportAttribute, filterMacs ...string) (r0 *unifi.SiteReportsResponse, err error) {
+	if m.SiteReportFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteReportFunc(ctx, site, startTime, endTime, interval, reportType, attributes, filterMacs...)
+}
+
+// SiteReportWithOptions calls SiteReportWithOptionsFunc
+func (m *Reporter) SiteReportWithOptions(ctx context.Context, site string, startTime time.Time, endTime time.Time, interval unifi.ReportInterval, reportType unifi.ReportType, attributes []unifi.ReportAttribute, filterMacs []string, opts ...unifi.RequestOption) (r0 *unifi.SiteReportsResponse, err error) {
+	if m.SiteReportWithOptionsFunc == nil {
+		err = ErrNotMocked
+		return
+	}
+	return m.SiteReportWithOptionsFunc(ctx, site, startTime, endTime, interval, reportType, attributes, filterMacs, opts...)
+}
+
+// EventsIterator calls EventsIteratorFunc
+func (m *Reporter) EventsIterator(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder) (r0 *unifi.EventsIterator) {
+	if m.EventsIteratorFunc == nil {
+		return
+	}
+	return m.EventsIteratorFunc(ctx, site, since, order)
+}
+
+// IPSEventsIterator calls IPSEventsIteratorFunc
+func (m *Reporter) IPSEventsIterator(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder) (r0 *unifi.EventsIterator) {
+	if m.IPSEventsIteratorFunc == nil {
+		return
+	}
+	return m.IPSEventsIteratorFunc(ctx, site, since, order)
+}
+
+// AlarmsIterator calls AlarmsIteratorFunc
+func (m *Reporter) AlarmsIterator(ctx context.Context, site string, since time.Time, order unifi.EventSortOrder, archived bool) (r0 *unifi.AlarmsIterator) {
+	if m.AlarmsIteratorFunc == nil {
+		return
+	}
+	return m.AlarmsIteratorFunc(ctx, site, since, order, archived)
+}
+
+// SessionsIterator calls SessionsIteratorFunc
+func (m *Reporter) SessionsIterator(ctx context.Context, site string, mac string, since time.Time, order unifi.SiteSessionOrder) (r0 *unifi.SessionsIterator) {
+	if m.SessionsIteratorFunc == nil {
+		return
+	}
+	return m.SessionsIteratorFunc(ctx, site, mac, since, order)
+}
+
+// API is a mock of unifi.API
+type API struct {
+	Session
+	SiteReader
+	SiteManager
+	DeviceManager
+	ClientManager
+	GuestManager
+	FirewallManager
+	NetworkManager
+	PortForwardManager
+	SwitchPortManager
+	WLANManager
+	Reporter
+}
+
+var _ unifi.API = (*API)(nil)
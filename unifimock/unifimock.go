@@ -0,0 +1,19 @@
+// Package unifimock provides mocks of the unifi api interfaces, to test code built on the unifi client
+// without a controller.
+//
+// Every mock has a <Method>Func field per method, set the ones your code calls:
+//
+//	m := &unifimock.SiteReader{
+//		SiteHealthFunc: func(ctx context.Context, site string, opts ...unifi.RequestOption) (*unifi.SiteHealthResponse, error) {
+//			return &unifi.SiteHealthResponse{}, nil
+//		},
+//	}
+//
+// The mocks are generated from the unifi interfaces with `go generate` in the unifi package.
+package unifimock
+
+import "errors"
+
+// ErrNotMocked is returned by mock methods whose func is not set,
+// methods without an error result like the iterators return nil instead.
+var ErrNotMocked = errors.New("unifimock: method not mocked")
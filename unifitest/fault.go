@@ -0,0 +1,88 @@
+package unifitest
+
+import (
+	"net/http"
+	"path"
+	"time"
+)
+
+// Fault describes a failure injected into matching requests.
+// A fault with only Latency set delays the request and then serves it normally.
+type Fault struct {
+	Method     string        // http method to match, empty matches any method
+	Path       string        // path pattern to match with path.Match, like /api/s/*/stat/health, empty matches any path
+	StatusCode int           // http status code to answer with, defaults to 400 when Message is set
+	Message    string        // meta.msg to answer with, like api.err.NoPermission
+	Latency    time.Duration // delay before answering
+	Times      int           // number of requests the fault applies to, 0 for every request
+}
+
+// InjectFault adds a fault, faults are evaluated in the order they were added and the first match applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetLatency delays every request by d, it is a shortcut for a latency only fault
+func (s *Server) SetLatency(d time.Duration) {
+	s.InjectFault(Fault{Latency: d})
+}
+
+// matchFault returns the fault that applies to the request, if any, consuming one of its uses
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, r.URL.Path); !ok {
+				continue
+			}
+		}
+		match := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &match
+	}
+	return nil
+}
+
+// apply delays the request and writes the failure, it returns false if the request should be served normally
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return true
+		case <-timer.C:
+		}
+	}
+	if f.StatusCode == 0 && f.Message == "" {
+		return false
+	}
+	statusCode := f.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusBadRequest
+	}
+	if f.Message == "" {
+		w.WriteHeader(statusCode)
+		return true
+	}
+	writeError(w, statusCode, f.Message)
+	return true
+}
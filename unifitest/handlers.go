@@ -0,0 +1,582 @@
+package unifitest
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/platinummonkey/unifi"
+)
+
+// envelope is the response format of the classic controller api
+type envelope struct {
+	Meta meta        `json:"meta"`
+	Data interface{} `json:"data"`
+}
+
+type meta struct {
+	ResponseCode  string `json:"rc"`
+	Message       string `json:"msg,omitempty"`
+	ServerVersion string `json:"server_version,omitempty"`
+	Up            bool   `json:"up,omitempty"`
+}
+
+// writeData writes an ok response with the given data
+func writeData(w http.ResponseWriter, data interface{}) {
+	if objs, ok := data.([]Object); data == nil || (ok && objs == nil) {
+		data = []Object{}
+	}
+	writeJSON(w, http.StatusOK, envelope{Meta: meta{ResponseCode: "ok"}, Data: data})
+}
+
+// writeError writes an error response with the given meta.msg
+func writeError(w http.ResponseWriter, statusCode int, msg string) {
+	writeJSON(w, statusCode, envelope{Meta: meta{ResponseCode: "error", Message: msg}, Data: []Object{}})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// serveHTTP is the entry point of the fake controller
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Header: r.Header.Clone(), Body: body})
+	s.mu.Unlock()
+
+	if f := s.matchFault(r); f != nil && f.apply(w, r) {
+		return
+	}
+
+	if s.unifiOS {
+		s.serveUniFiOS(w, r, body)
+		return
+	}
+	switch r.URL.Path {
+	case "/":
+		// the classic controller redirects to its web ui, UniFi OS would answer with 200
+		http.Redirect(w, r, "/manage", http.StatusFound)
+	case "/api/login":
+		s.handleLogin(w, r, body)
+	default:
+		s.serveNetwork(w, r, r.URL.Path, body)
+	}
+}
+
+// serveUniFiOS serves the UniFi OS console endpoints and passes /proxy/network on to the network application
+func (s *Server) serveUniFiOS(w http.ResponseWriter, r *http.Request, body []byte) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+		return
+	case unifiOSLoginPath:
+		s.handleLogin(w, r, body)
+		return
+	case unifiOSLogoutPath:
+		if !s.authenticated(r) {
+			writeError(w, http.StatusUnauthorized, "api.err.LoginRequired")
+			return
+		}
+		if !s.checkCSRF(w, r) {
+			return
+		}
+		s.mu.Lock()
+		delete(s.sessions, s.session(r))
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, Object{})
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, unifiOSNetworkPrefix+"/") {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+	p := strings.TrimPrefix(r.URL.Path, unifiOSNetworkPrefix)
+	if strings.HasPrefix(p, integrationPrefix) {
+		s.handleIntegration(w, r, strings.TrimPrefix(p, integrationPrefix))
+		return
+	}
+	// requests authenticated with the api key do not need a CSRF token
+	if r.Method != http.MethodGet && !s.hasAPIKey(r) && !s.checkCSRF(w, r) {
+		return
+	}
+	s.serveNetwork(w, r, p, body)
+}
+
+// serveNetwork serves the network application api, p is the request path without the UniFi OS prefix
+func (s *Server) serveNetwork(w http.ResponseWriter, r *http.Request, p string, body []byte) {
+	if p == "/status" {
+		s.mu.Lock()
+		version := s.version
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, envelope{Meta: meta{ResponseCode: "ok", ServerVersion: version, Up: true}, Data: []Object{}})
+		return
+	}
+
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "api.err.LoginRequired")
+		return
+	}
+
+	if strings.HasPrefix(p, "/wss/s/") {
+		s.handleStream(w, r, p)
+		return
+	}
+
+	switch p {
+	case "/api/logout":
+		s.mu.Lock()
+		delete(s.sessions, s.session(r))
+		s.mu.Unlock()
+		writeData(w, nil)
+	case "/api/self", "/api/stat/admin":
+		s.mu.Lock()
+		username := s.username
+		s.mu.Unlock()
+		writeData(w, []Object{{"name": username, "is_super": true}})
+	case "/api/self/sites", "/api/stat/sites":
+		writeData(w, s.siteList())
+	default:
+		parts := strings.Split(strings.Trim(p, "/"), "/")
+		if len(parts) < 4 || parts[0] != "api" || parts[1] != "s" {
+			writeError(w, http.StatusNotFound, "api.err.NotFound")
+			return
+		}
+		s.handleSite(w, r, parts[2], parts[3:], body)
+	}
+}
+
+// handleLogin checks the credentials and starts a session.
+// UniFi OS answers with the user instead of the meta envelope and hands out the CSRF token of the session.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request, body []byte) {
+	var auth struct {
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		Token        string `json:"token"`          // UniFi OS
+		ClassicToken string `json:"ubic_2fa_token"` // classic controller
+	}
+	if r.Method != http.MethodPost || json.Unmarshal(body, &auth) != nil {
+		writeError(w, http.StatusBadRequest, "api.err.Invalid")
+		return
+	}
+
+	s.mu.Lock()
+	if auth.Username != s.username || auth.Password != s.password {
+		s.mu.Unlock()
+		if s.unifiOS {
+			writeJSON(w, http.StatusUnauthorized, Object{"code": "AUTHENTICATION_FAILED_INVALID_CREDENTIALS"})
+			return
+		}
+		writeError(w, http.StatusBadRequest, "api.err.Invalid")
+		return
+	}
+	seed := s.totpSeed
+	s.mu.Unlock()
+
+	if seed != "" {
+		code := auth.ClassicToken
+		if s.unifiOS {
+			code = auth.Token
+		}
+		if code == "" {
+			if s.unifiOS {
+				writeJSON(w, unifiOSTwoFactorStatusCode, Object{"code": "MFA_AUTH_REQUIRED"})
+				return
+			}
+			writeError(w, http.StatusBadRequest, unifi.APITwoFactorRequiredError)
+			return
+		}
+		if !validTOTP(seed, code) {
+			if s.unifiOS {
+				writeJSON(w, http.StatusUnauthorized, Object{"code": "AUTHENTICATION_FAILED_INVALID_TOKEN"})
+				return
+			}
+			writeError(w, http.StatusBadRequest, "api.err.Invalid2FAToken")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	token, csrfToken := s.newSession()
+	username := s.username
+	s.mu.Unlock()
+
+	if s.unifiOS {
+		http.SetCookie(w, &http.Cookie{Name: unifiOSSessionCookie, Value: token, Path: "/", HttpOnly: true})
+		w.Header().Set(csrfTokenHeader, csrfToken)
+		writeJSON(w, http.StatusOK, Object{"username": username, "isOwner": true})
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: token, Path: "/", HttpOnly: true})
+	writeData(w, nil)
+}
+
+// validTOTP returns true if the code is valid for the current or an adjacent period
+func validTOTP(seed string, code string) bool {
+	generator, err := unifi.NewTOTPGenerator(seed)
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	for _, t := range []time.Time{now.Add(-30 * time.Second), now, now.Add(30 * time.Second)} {
+		if generator.CodeAt(t) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// siteList returns the sites as listed by /api/self/sites
+func (s *Server) siteList() []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sites := make([]Object, 0, len(s.sites))
+	for _, st := range s.sites {
+		sites = append(sites, Object{"_id": st.id, "name": st.name, "desc": st.desc, "role": "admin"})
+	}
+	return sites
+}
+
+// handleSite serves the site scoped endpoints, parts is the path after /api/s/{site}
+func (s *Server) handleSite(w http.ResponseWriter, r *http.Request, siteName string, parts []string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.site(siteName)
+	if st == nil {
+		writeError(w, http.StatusBadRequest, "api.err.NoSiteContext")
+		return
+	}
+
+	var payload Object
+	if len(body) > 0 && json.Unmarshal(body, &payload) != nil {
+		writeError(w, http.StatusBadRequest, "api.err.InvalidPayload")
+		return
+	}
+
+	switch parts[0] {
+	case "rest":
+		s.handleRest(w, r, st, parts[1:], payload)
+	case "list":
+		if len(parts) < 2 {
+			writeError(w, http.StatusNotFound, "api.err.NotFound")
+			return
+		}
+		writeData(w, st.collections[parts[1]])
+	case "upd":
+		if len(parts) < 3 {
+			writeError(w, http.StatusBadRequest, "api.err.IdInvalid")
+			return
+		}
+		s.handleRest(w, &http.Request{Method: http.MethodPut}, st, parts[1:], payload)
+	case "group":
+		if len(parts) < 2 {
+			writeError(w, http.StatusNotFound, "api.err.NotFound")
+			return
+		}
+		var created []Object
+		objects, _ := payload["objects"].([]interface{})
+		for _, o := range objects {
+			data, _ := o.(map[string]interface{})["data"].(map[string]interface{})
+			created = append(created, st.insert(s, parts[1], Object(data)))
+		}
+		writeData(w, created)
+	case "stat":
+		s.handleStat(w, r, st, parts[1:], payload)
+	case "cmd":
+		s.handleCmd(w, st, payload)
+	default:
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+	}
+}
+
+// handleRest serves the generic rest/{collection}[/{id}] endpoints
+func (s *Server) handleRest(w http.ResponseWriter, r *http.Request, st *site, parts []string, payload Object) {
+	if len(parts) == 0 {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+	collection := parts[0]
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, st.collections[collection])
+		case http.MethodPost:
+			delete(payload, "_id")
+			writeData(w, []Object{st.insert(s, collection, payload)})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "api.err.InvalidMethod")
+		}
+		return
+	}
+
+	i := st.find(collection, parts[1])
+	if i < 0 {
+		writeError(w, http.StatusBadRequest, "api.err.IdInvalid")
+		return
+	}
+	obj := st.collections[collection][i]
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, []Object{obj})
+	case http.MethodPut, http.MethodPost:
+		updated := copyObject(obj)
+		for k, v := range payload {
+			if k != "_id" && k != "site_id" {
+				updated[k] = v
+			}
+		}
+		st.collections[collection][i] = updated
+		writeData(w, []Object{updated})
+	case http.MethodDelete:
+		st.remove(collection, i)
+		writeData(w, nil)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "api.err.InvalidMethod")
+	}
+}
+
+// handleStat serves the stat/* endpoints, unknown statistics are served from the collection of the same name
+func (s *Server) handleStat(w http.ResponseWriter, r *http.Request, st *site, parts []string, payload Object) {
+	if len(parts) == 0 {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+	switch parts[0] {
+	case "device", "device-basic":
+		devices := st.collections[CollectionDevices]
+		if len(parts) > 1 {
+			payload = Object{"macs": []interface{}{parts[1]}}
+		}
+		if macs, ok := payload["macs"].([]interface{}); ok && len(macs) > 0 {
+			devices = filterByMAC(devices, macs)
+		}
+		writeData(w, devices)
+	case "sta", "user", "alluser":
+		clients := st.collections[CollectionClients]
+		if len(parts) > 1 {
+			clients = filterByMAC(clients, []interface{}{parts[1]})
+		}
+		writeData(w, paginate(clients, payload))
+	case "alarm":
+		archived := r.URL.Query().Get("archived") == "true"
+		var alarms []Object
+		for _, alarm := range st.collections[CollectionAlarms] {
+			if isArchived, _ := alarm["archived"].(bool); isArchived == archived {
+				alarms = append(alarms, alarm)
+			}
+		}
+		if len(parts) > 1 && parts[1] == "cnt" {
+			writeData(w, []Object{{"count": len(alarms)}})
+			return
+		}
+		writeData(w, paginate(alarms, payload))
+	default:
+		writeData(w, paginate(st.collections[parts[0]], payload))
+	}
+}
+
+// handleCmd serves the cmd/* endpoints
+func (s *Server) handleCmd(w http.ResponseWriter, st *site, payload Object) {
+	cmd, _ := payload["cmd"].(string)
+	mac, _ := payload["mac"].(string)
+	switch cmd {
+	case "create-voucher":
+		n := intValue(payload["n"], 1)
+		createTime := len(st.collections[CollectionVouchers]) + 1
+		for i := 0; i < n; i++ {
+			st.insert(s, CollectionVouchers, Object{
+				"code":        voucherCode(),
+				"create_time": createTime,
+				"duration":    intValue(payload["expire"], 0),
+				"quota":       intValue(payload["quota"], 1),
+				"note":        payload["note"],
+			})
+		}
+		writeData(w, []Object{{"create_time": createTime}})
+	case "delete-voucher":
+		s.removeByID(w, st, CollectionVouchers, payload["_id"])
+	case "archive-all-alarms":
+		for _, alarm := range st.collections[CollectionAlarms] {
+			alarm["archived"] = true
+		}
+		writeData(w, nil)
+	case "block-sta", "unblock-sta", "authorize-guest", "unauthorize-guest", "kick-sta":
+		i := st.find(CollectionClients, mac)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "api.err.UnknownStation")
+			return
+		}
+		client := st.collections[CollectionClients][i]
+		switch cmd {
+		case "block-sta", "unblock-sta":
+			client["blocked"] = cmd == "block-sta"
+		case "authorize-guest", "unauthorize-guest":
+			client["authorized"] = cmd == "authorize-guest"
+		}
+		writeData(w, []Object{client})
+	case "forget-sta":
+		macs, _ := payload["mac"].([]interface{})
+		for _, m := range macs {
+			forget, ok := m.(string)
+			if !ok {
+				continue
+			}
+			if i := st.find(CollectionClients, forget); i >= 0 {
+				st.remove(CollectionClients, i)
+			}
+		}
+		writeData(w, nil)
+	case "adopt", "restart", "force-provision", "power-cycle", "set-locate", "unset-locate", "upgrade", "upgrade-external", "spectrum-scan":
+		i := st.find(CollectionDevices, mac)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "api.err.UnknownDevice")
+			return
+		}
+		device := st.collections[CollectionDevices][i]
+		switch cmd {
+		case "adopt":
+			device["adopted"] = true
+		case "set-locate", "unset-locate":
+			device["locating"] = cmd == "set-locate"
+		}
+		writeData(w, nil)
+	case "delete-device":
+		i := st.find(CollectionDevices, mac)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "api.err.UnknownDevice")
+			return
+		}
+		st.remove(CollectionDevices, i)
+		writeData(w, nil)
+	case "add-site":
+		name, _ := payload["name"].(string)
+		desc, _ := payload["desc"].(string)
+		if name == "" || s.site(name) != nil {
+			writeError(w, http.StatusBadRequest, "api.err.SiteNameAlreadyExists")
+			return
+		}
+		created := s.addSite(name, desc)
+		writeData(w, []Object{{"_id": created.id, "name": created.name, "desc": created.desc}})
+	case "update-site":
+		st.desc, _ = payload["desc"].(string)
+		writeData(w, nil)
+	case "delete-site":
+		for i, other := range s.sites {
+			if other.name == payload["name"] || other.id == payload["site"] {
+				s.sites = append(s.sites[:i:i], s.sites[i+1:]...)
+				writeData(w, nil)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, "api.err.NoSiteContext")
+	case "get-admins":
+		writeData(w, []Object{{"name": s.username, "role": "admin"}})
+	default:
+		// commands without state in the fake, like speed tests or backups, just succeed
+		writeData(w, nil)
+	}
+}
+
+// removeByID removes the object with the given id or answers with api.err.IdInvalid
+func (s *Server) removeByID(w http.ResponseWriter, st *site, collection string, id interface{}) {
+	idStr, _ := id.(string)
+	i := st.find(collection, idStr)
+	if idStr == "" || i < 0 {
+		writeError(w, http.StatusBadRequest, "api.err.IdInvalid")
+		return
+	}
+	st.remove(collection, i)
+	writeData(w, nil)
+}
+
+// paginate sorts and pages the objects according to the _sort, _start and _limit fields of the payload
+func paginate(objs []Object, payload Object) []Object {
+	if sortField, ok := payload["_sort"].(string); ok && len(sortField) > 1 {
+		descending := sortField[0] == '-'
+		field := strings.TrimLeft(sortField, "+-")
+		sorted := append([]Object(nil), objs...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := numberValue(sorted[i][field]), numberValue(sorted[j][field])
+			if descending {
+				return a > b
+			}
+			return a < b
+		})
+		objs = sorted
+	}
+
+	start := intValue(payload["_start"], 0)
+	if start > len(objs) {
+		start = len(objs)
+	}
+	objs = objs[start:]
+	if limit := intValue(payload["_limit"], 0); limit > 0 && limit < len(objs) {
+		objs = objs[:limit]
+	}
+	return objs
+}
+
+// filterByMAC returns the objects whose mac is one of macs
+func filterByMAC(objs []Object, macs []interface{}) []Object {
+	var filtered []Object
+	for _, obj := range objs {
+		mac, ok := obj["mac"].(string)
+		if !ok {
+			continue
+		}
+		for _, m := range macs {
+			if want, ok := m.(string); ok && strings.EqualFold(mac, want) {
+				filtered = append(filtered, obj)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// numberValue converts a numeric field, objects added by tests may hold any go number type
+func numberValue(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
+// intValue converts a decoded json number, falling back to def
+func intValue(v interface{}, def int) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		if i, err := strconv.Atoi(n); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// voucherCode returns a random 10 digit voucher code
+func voucherCode() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%010d", binary.BigEndian.Uint64(buf)%10000000000)
+}
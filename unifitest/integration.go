@@ -0,0 +1,163 @@
+package unifitest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// integrationPrefix is the path of the official integration API below the network application
+const integrationPrefix = "/integration/v1/"
+
+// apiKeyHeader authenticates requests with the api key instead of a session
+const apiKeyHeader = "X-API-KEY"
+
+// WithAPIKey accepts the api key for the integration API and, instead of a session, for the network api.
+// The integration API is only available on UniFi OS, so the option implies WithUniFiOS.
+func WithAPIKey(apiKey string) ServerOption {
+	return func(s *Server) {
+		s.unifiOS = true
+		s.apiKey = apiKey
+	}
+}
+
+// hasAPIKey returns true if the request carries the configured api key
+func (s *Server) hasAPIKey(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apiKey != "" && r.Header.Get(apiKeyHeader) == s.apiKey
+}
+
+// handleIntegration serves the sites, devices and clients of the integration API, p is the path after /integration/v1/
+func (s *Server) handleIntegration(w http.ResponseWriter, r *http.Request, p string) {
+	if !s.hasAPIKey(r) {
+		writeJSON(w, http.StatusUnauthorized, Object{"statusCode": http.StatusUnauthorized, "statusName": "UNAUTHORIZED"})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, Object{"statusCode": http.StatusMethodNotAllowed})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if len(parts) == 1 && parts[0] == "sites" {
+		sites := make([]Object, 0, len(s.sites))
+		for _, st := range s.sites {
+			sites = append(sites, Object{"id": st.id, "internalReference": st.name, "name": st.desc})
+		}
+		writeIntegrationPage(w, r, sites)
+		return
+	}
+	if len(parts) != 3 || parts[0] != "sites" {
+		writeJSON(w, http.StatusNotFound, Object{"statusCode": http.StatusNotFound, "statusName": "NOT_FOUND"})
+		return
+	}
+	var st *site
+	for _, candidate := range s.sites {
+		if candidate.id == parts[1] {
+			st = candidate
+		}
+	}
+	if st == nil {
+		writeJSON(w, http.StatusNotFound, Object{"statusCode": http.StatusNotFound, "statusName": "NOT_FOUND"})
+		return
+	}
+
+	var items []Object
+	switch parts[2] {
+	case "devices":
+		for _, device := range st.collections[CollectionDevices] {
+			items = append(items, integrationDevice(device))
+		}
+	case "clients":
+		for _, client := range st.collections[CollectionClients] {
+			items = append(items, integrationClient(client))
+		}
+	default:
+		writeJSON(w, http.StatusNotFound, Object{"statusCode": http.StatusNotFound, "statusName": "NOT_FOUND"})
+		return
+	}
+	writeIntegrationPage(w, r, items)
+}
+
+// writeIntegrationPage writes the page of items selected by the offset and limit query parameters
+func writeIntegrationPage(w http.ResponseWriter, r *http.Request, items []Object) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 25
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	page := items[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	if page == nil {
+		page = []Object{}
+	}
+	writeJSON(w, http.StatusOK, Object{
+		"offset":     offset,
+		"limit":      limit,
+		"count":      len(page),
+		"totalCount": len(items),
+		"data":       page,
+	})
+}
+
+// integrationDevice converts a device to the integration API format.
+// The features are derived from the type unless the device sets them, gateways are told apart by their model.
+func integrationDevice(device Object) Object {
+	state := "OFFLINE"
+	if intValue(device["state"], 0) == 1 {
+		state = "ONLINE"
+	}
+	if adopted, ok := device["adopted"].(bool); ok && !adopted {
+		state = "PENDING_ADOPTION"
+	}
+	features, ok := device["features"]
+	if !ok {
+		switch device["type"] {
+		case "uap":
+			features = []string{"accessPoint"}
+		case "usw":
+			features = []string{"switching"}
+		case "udm":
+			features = []string{"switching", "accessPoint"}
+		default:
+			features = []string{}
+		}
+	}
+	return Object{
+		"id":         device["_id"],
+		"name":       device["name"],
+		"model":      device["model"],
+		"macAddress": device["mac"],
+		"ipAddress":  device["ip"],
+		"state":      state,
+		"features":   features,
+	}
+}
+
+// integrationClient converts a client station to the integration API format
+func integrationClient(client Object) Object {
+	clientType := "WIRELESS"
+	if wired, _ := client["is_wired"].(bool); wired {
+		clientType = "WIRED"
+	}
+	obj := Object{
+		"id":         client["_id"],
+		"type":       clientType,
+		"name":       client["hostname"],
+		"ipAddress":  client["ip"],
+		"macAddress": client["mac"],
+	}
+	if assoc := int64(numberValue(client["assoc_time"])); assoc > 0 {
+		obj["connectedAt"] = time.Unix(assoc, 0).UTC().Format(time.RFC3339)
+	}
+	return obj
+}
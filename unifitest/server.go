@@ -0,0 +1,237 @@
+// Package unifitest provides an in-process fake UniFi controller for testing code built on the unifi client.
+//
+// The fake emulates the classic controller api: /status, /api/login, /api/logout, /api/self, /api/self/sites,
+// the site scoped /api/s/{site}/stat/*, rest/* and cmd/* endpoints, backed by in-memory state,
+// and the /wss/s/{site}/events websocket fed with Publish.
+// WithUniFiOS serves the same api the way a UniFi OS console does, behind /api/auth/login and /proxy/network.
+// WithAPIKey adds the sites, devices and clients of the integration API below /proxy/network/integration/v1.
+// Faults like errors, latency and expired sessions can be injected to exercise error handling.
+//
+//	srv := unifitest.NewServer()
+//	defer srv.Close()
+//	client, err := srv.Client(ctx)
+package unifitest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/platinummonkey/unifi"
+)
+
+// Default credentials accepted by the fake controller
+const (
+	DefaultUsername = "admin"
+	DefaultPassword = "unifitest"
+)
+
+// The cookies the controllers use for their session
+const (
+	sessionCookie        = "unifises"
+	unifiOSSessionCookie = "TOKEN"
+)
+
+// UniFi OS paths and headers
+const (
+	unifiOSNetworkPrefix   = "/proxy/network"
+	unifiOSLoginPath       = "/api/auth/login"
+	unifiOSLogoutPath      = "/api/auth/logout"
+	csrfTokenHeader        = "X-CSRF-Token"
+	updatedCSRFTokenHeader = "X-Updated-CSRF-Token"
+	// UniFi OS answers a login without the required MFA token with this non-standard status
+	unifiOSTwoFactorStatusCode = 499
+)
+
+// ServerOption configures the fake controller
+type ServerOption func(s *Server)
+
+// WithCredentials overrides the credentials accepted by /api/login
+func WithCredentials(username string, password string) ServerOption {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithUniFiOS emulates a UniFi OS console, like a UDM, instead of the classic controller.
+// The root path answers directly, logins go through /api/auth/login, the network api lives under /proxy/network
+// and every write must carry the CSRF token of the session, which is rotated with X-Updated-CSRF-Token.
+func WithUniFiOS() ServerOption {
+	return func(s *Server) {
+		s.unifiOS = true
+	}
+}
+
+// WithTOTPSeed requires a two-factor code generated from the base32 seed on every login.
+// Codes of the current and the adjacent 30 second periods are accepted.
+func WithTOTPSeed(seed string) ServerOption {
+	return func(s *Server) {
+		s.totpSeed = seed
+	}
+}
+
+// WithServerVersion sets the version reported by /status
+func WithServerVersion(version string) ServerOption {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// Server is a fake UniFi controller backed by an httptest.Server.
+// It is safe for concurrent use.
+type Server struct {
+	URL string // base url of the fake controller, pass it to unifi.NewClient
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	username string
+	password string
+	version  string
+	unifiOS  bool
+	apiKey   string
+	totpSeed string
+	sessions map[string]string // session token to its current CSRF token, empty on the classic controller
+	streams  map[*websocket.Conn]string
+	sites    []*site
+	faults   []*Fault
+	requests []RecordedRequest
+	nextID   uint64
+}
+
+// RecordedRequest is a request received by the fake controller
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer starts a fake controller with a single `default` site, call Close when done.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		version:  "6.0.45",
+		sessions: make(map[string]string),
+		streams:  make(map[*websocket.Conn]string),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.addSite("default", "Default")
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the fake controller
+func (s *Server) Close() {
+	s.DropStreams()
+	s.server.Close()
+}
+
+// Client returns a client logged in to the fake controller.
+// The client does not retry, so injected faults surface immediately, pass unifi.WithRetryPolicy to change that.
+func (s *Server) Client(ctx context.Context, opts ...unifi.ClientOption) (*unifi.Client, error) {
+	s.mu.Lock()
+	username, password := s.username, s.password
+	s.mu.Unlock()
+
+	opts = append([]unifi.ClientOption{unifi.WithRetryPolicy(unifi.NoRetryPolicy())}, opts...)
+	client, err := unifi.NewClient(s.URL, nil, 10*time.Second, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = client.Login(ctx, username, password, false)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ExpireSessions invalidates all sessions, the next request of every client fails with api.err.LoginRequired.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]string)
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// newToken returns a random token
+func newToken() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// newSession creates a new session token, the caller must hold the lock.
+// UniFi OS sessions get a CSRF token as well.
+func (s *Server) newSession() (string, string) {
+	token := newToken()
+	var csrfToken string
+	if s.unifiOS {
+		csrfToken = newToken()
+	}
+	s.sessions[token] = csrfToken
+	return token, csrfToken
+}
+
+// session returns the session token of the request, empty if it carries none
+func (s *Server) session(r *http.Request) string {
+	name := sessionCookie
+	if s.unifiOS {
+		name = unifiOSSessionCookie
+	}
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// authenticated returns true if the request carries a valid session cookie or the api key
+func (s *Server) authenticated(r *http.Request) bool {
+	if s.hasAPIKey(r) {
+		return true
+	}
+	token := s.session(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[token]
+	return ok
+}
+
+// checkCSRF verifies a request of an authenticated UniFi OS session carries the CSRF token and rotates it.
+// It answers the request and returns false if the token is missing or stale.
+func (s *Server) checkCSRF(w http.ResponseWriter, r *http.Request) bool {
+	token := s.session(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expected, ok := s.sessions[token]
+	if !ok {
+		// not authenticated, the endpoint rejects the request itself
+		return true
+	}
+	if r.Header.Get(csrfTokenHeader) != expected {
+		writeError(w, http.StatusForbidden, "api.err.InvalidCSRFToken")
+		return false
+	}
+	rotated := newToken()
+	s.sessions[token] = rotated
+	w.Header().Set(updatedCSRFTokenHeader, rotated)
+	return true
+}
@@ -0,0 +1,210 @@
+package unifitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+	"time"
+)
+
+// login returns an http client holding a session of the fake classic controller
+func login(t *testing.T, s *Server) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := &http.Client{Jar: jar}
+	resp := post(t, httpClient, s.URL+"/api/login", Object{"username": DefaultUsername, "password": DefaultPassword})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login failed with status %d", resp.StatusCode)
+	}
+	return httpClient
+}
+
+// post sends payload as json and decodes the classic response envelope
+func post(t *testing.T, httpClient *http.Client, url string, payload interface{}) *response {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	r := &response{StatusCode: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(&r.envelope); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+type response struct {
+	StatusCode int
+	envelope   struct {
+		Meta meta     `json:"meta"`
+		Data []Object `json:"data"`
+	}
+}
+
+func TestLoginRequired(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	resp := post(t, http.DefaultClient, s.URL+"/api/s/default/stat/sta", Object{})
+	if resp.StatusCode != http.StatusUnauthorized || resp.envelope.Meta.Message != "api.err.LoginRequired" {
+		t.Fatalf("expected api.err.LoginRequired, got %d %q", resp.StatusCode, resp.envelope.Meta.Message)
+	}
+
+	resp = post(t, http.DefaultClient, s.URL+"/api/login", Object{"username": DefaultUsername, "password": "wrong"})
+	if resp.StatusCode != http.StatusBadRequest || resp.envelope.Meta.Message != "api.err.Invalid" {
+		t.Fatalf("expected api.err.Invalid, got %d %q", resp.StatusCode, resp.envelope.Meta.Message)
+	}
+
+	httpClient := login(t, s)
+	resp = post(t, httpClient, s.URL+"/api/s/default/stat/sta", Object{})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the session to be accepted, got %d", resp.StatusCode)
+	}
+
+	s.ExpireSessions()
+	resp = post(t, httpClient, s.URL+"/api/s/default/stat/sta", Object{})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected the expired session to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestFilterByMAC(t *testing.T) {
+	objs := []Object{
+		{"mac": "aa:aa:aa:aa:aa:01"},
+		{"mac": "aa:aa:aa:aa:aa:02"},
+		{"name": "no mac"},
+		{"mac": 3},
+	}
+	tests := []struct {
+		name string
+		macs []interface{}
+		want int
+	}{
+		{name: "exact", macs: []interface{}{"aa:aa:aa:aa:aa:01"}, want: 1},
+		{name: "case insensitive", macs: []interface{}{"AA:AA:AA:AA:AA:02"}, want: 1},
+		{name: "several", macs: []interface{}{"aa:aa:aa:aa:aa:01", "aa:aa:aa:aa:aa:02"}, want: 2},
+		{name: "unknown", macs: []interface{}{"bb:bb:bb:bb:bb:bb"}, want: 0},
+		{name: "not a string", macs: []interface{}{1, nil, Object{}}, want: 0},
+		{name: "mixed", macs: []interface{}{1, "aa:aa:aa:aa:aa:02"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterByMAC(objs, tt.macs); len(got) != tt.want {
+				t.Fatalf("expected %d objects, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatDevicesIgnoresInvalidMACs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddDevice("default", Object{"mac": "aa:aa:aa:aa:aa:01", "type": "uap"})
+	s.AddDevice("default", Object{"mac": "aa:aa:aa:aa:aa:02", "type": "usw"})
+	httpClient := login(t, s)
+
+	resp := post(t, httpClient, s.URL+"/api/s/default/stat/device", Object{"macs": []interface{}{42, "AA:AA:AA:AA:AA:02"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if len(resp.envelope.Data) != 1 || resp.envelope.Data[0]["type"] != "usw" {
+		t.Fatalf("expected only the usw, got %v", resp.envelope.Data)
+	}
+}
+
+func TestForgetClientIgnoresInvalidMACs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddClient("default", Object{"mac": "aa:aa:aa:aa:aa:01"})
+	s.AddClient("default", Object{"mac": "aa:aa:aa:aa:aa:02"})
+	httpClient := login(t, s)
+
+	resp := post(t, httpClient, s.URL+"/api/s/default/cmd/stamgr", Object{
+		"cmd": "forget-sta",
+		"mac": []interface{}{true, "aa:aa:aa:aa:aa:01"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	clients := s.Objects("default", CollectionClients)
+	if len(clients) != 1 || clients[0]["mac"] != "aa:aa:aa:aa:aa:02" {
+		t.Fatalf("expected only the second client to be left, got %v", clients)
+	}
+}
+
+func TestObjectsAreCopies(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	obj := Object{"mac": "aa:aa:aa:aa:aa:01", "name": "original"}
+	id := s.AddClient("default", obj)
+	obj["name"] = "changed after add"
+	if id == "" {
+		t.Fatal("expected an _id to be assigned")
+	}
+
+	clients := s.Objects("default", CollectionClients)
+	clients[0]["name"] = "changed after read"
+	clients = s.Objects("default", CollectionClients)
+	if clients[0]["name"] != "original" || clients[0]["_id"] != id {
+		t.Fatalf("stored object was modified: %v", clients[0])
+	}
+
+	if objs := s.Objects("missing", CollectionClients); objs != nil {
+		t.Fatalf("expected no objects for an unknown site, got %v", objs)
+	}
+	s.AddClient("new", Object{"mac": "aa:aa:aa:aa:aa:02"})
+	if objs := s.Objects("new", CollectionClients); len(objs) != 1 {
+		t.Fatalf("expected the site to be created on add, got %v", objs)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	httpClient := login(t, s)
+
+	s.InjectFault(Fault{Method: http.MethodPost, Path: "/api/s/*/stat/health", Message: "api.err.NoPermission", StatusCode: http.StatusForbidden, Times: 2})
+	s.InjectFault(Fault{Path: "/api/s/*/stat/health", Latency: 20 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		resp := post(t, httpClient, s.URL+"/api/s/default/stat/health", Object{})
+		if resp.StatusCode != http.StatusForbidden || resp.envelope.Meta.Message != "api.err.NoPermission" {
+			t.Fatalf("attempt %d: expected the fault, got %d %q", i, resp.StatusCode, resp.envelope.Meta.Message)
+		}
+	}
+
+	// the first fault is used up, the latency only fault serves the request normally
+	start := time.Now()
+	resp := post(t, httpClient, s.URL+"/api/s/default/stat/health", Object{})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 once the fault is used up, got %d", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("expected the latency to apply, took %s", elapsed)
+	}
+
+	s.ClearFaults()
+	requests := s.Requests()
+	if len(requests) != 4 {
+		t.Fatalf("expected the login and 3 health requests to be recorded, got %d", len(requests))
+	}
+	last := requests[len(requests)-1]
+	if last.Method != http.MethodPost || last.Path != "/api/s/default/stat/health" || string(last.Body) != "{}" {
+		t.Fatalf("unexpected recorded request: %+v", last)
+	}
+	if last.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected the headers to be recorded, got %v", last.Header)
+	}
+}
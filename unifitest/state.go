@@ -0,0 +1,153 @@
+package unifitest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Object is a controller object as stored by the fake controller, like a device, a client or a firewall group
+type Object map[string]interface{}
+
+// Collections used by the fake controller for the stat and cmd endpoints, every other rest/* collection is generic
+const (
+	CollectionDevices        = "device"
+	CollectionClients        = "user"
+	CollectionFirewallGroups = "firewallgroup"
+	CollectionUserGroups     = "usergroup"
+	CollectionVouchers       = "voucher"
+	CollectionAlarms         = "alarm"
+	CollectionEvents         = "event"
+)
+
+// site is the in-memory state of a site
+type site struct {
+	id          string
+	name        string
+	desc        string
+	collections map[string][]Object
+}
+
+// newID returns a new 24 hex characters object id, like the ones generated by the controller
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+// addSite adds a site, the caller must hold the lock unless the server has not started yet
+func (s *Server) addSite(name string, desc string) *site {
+	st := &site{
+		id:          s.newID(),
+		name:        name,
+		desc:        desc,
+		collections: make(map[string][]Object),
+	}
+	s.sites = append(s.sites, st)
+	return st
+}
+
+// site returns the site with the given name, the caller must hold the lock
+func (s *Server) site(name string) *site {
+	for _, st := range s.sites {
+		if st.name == name {
+			return st
+		}
+	}
+	return nil
+}
+
+// AddSite adds a site and returns its id
+func (s *Server) AddSite(name string, desc string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addSite(name, desc).id
+}
+
+// Add stores a copy of obj in the collection of the site, assigning an _id if it has none, and returns the _id.
+// The site is created if it does not exist.
+func (s *Server) Add(siteName string, collection string, obj Object) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.site(siteName)
+	if st == nil {
+		st = s.addSite(siteName, siteName)
+	}
+	return st.insert(s, collection, obj)["_id"].(string)
+}
+
+// AddDevice adds a device, mac and type (uap, usw, ugw) are the fields most endpoints rely on
+func (s *Server) AddDevice(siteName string, device Object) string {
+	return s.Add(siteName, CollectionDevices, device)
+}
+
+// AddClient adds a client station, identified by its mac
+func (s *Server) AddClient(siteName string, client Object) string {
+	return s.Add(siteName, CollectionClients, client)
+}
+
+// AddAlarm adds an unarchived alarm
+func (s *Server) AddAlarm(siteName string, alarm Object) string {
+	if _, ok := alarm["archived"]; !ok {
+		alarm = copyObject(alarm)
+		alarm["archived"] = false
+	}
+	return s.Add(siteName, CollectionAlarms, alarm)
+}
+
+// AddEvent adds an event
+func (s *Server) AddEvent(siteName string, event Object) string {
+	return s.Add(siteName, CollectionEvents, event)
+}
+
+// Objects returns a copy of the objects stored in the collection of the site
+func (s *Server) Objects(siteName string, collection string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.site(siteName)
+	if st == nil {
+		return nil
+	}
+	objs := make([]Object, 0, len(st.collections[collection]))
+	for _, obj := range st.collections[collection] {
+		objs = append(objs, copyObject(obj))
+	}
+	return objs
+}
+
+// insert stores a copy of obj, the caller must hold the lock
+func (st *site) insert(s *Server, collection string, obj Object) Object {
+	stored := copyObject(obj)
+	if id, _ := stored["_id"].(string); id == "" {
+		stored["_id"] = s.newID()
+	}
+	stored["site_id"] = st.id
+	st.collections[collection] = append(st.collections[collection], stored)
+	return stored
+}
+
+// find returns the index of the object with the given _id, or mac for devices and clients, -1 if not found
+func (st *site) find(collection string, id string) int {
+	for i, obj := range st.collections[collection] {
+		if obj["_id"] == id {
+			return i
+		}
+		if mac, ok := obj["mac"].(string); ok && strings.EqualFold(mac, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove deletes the object at index i of the collection
+func (st *site) remove(collection string, i int) {
+	objs := st.collections[collection]
+	st.collections[collection] = append(objs[:i:i], objs[i+1:]...)
+}
+
+// copyObject returns a shallow copy of obj
+func copyObject(obj Object) Object {
+	cp := make(Object, len(obj))
+	for k, v := range obj {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,80 @@
+package unifitest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// handleStream serves the /wss/s/{site}/events websocket, p is the request path without the UniFi OS prefix
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, p string) {
+	site := strings.TrimSuffix(strings.TrimPrefix(p, "/wss/s/"), "/events")
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.streams[conn] = site
+	s.mu.Unlock()
+
+	// the controller does not expect messages, read until the connection drops
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.streams, conn)
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// Publish pushes a message of the given type, like events, alarm, device:sync or sta:sync,
+// to the clients subscribed to the events of the site.
+func (s *Server) Publish(siteName string, messageType string, data ...Object) {
+	if data == nil {
+		data = []Object{}
+	}
+	msg := map[string]interface{}{
+		"meta": map[string]interface{}{"rc": "ok", "message": messageType},
+		"data": data,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn, site := range s.streams {
+		if site == siteName {
+			_ = conn.WriteJSON(msg)
+		}
+	}
+}
+
+// Subscribers returns the number of clients subscribed to the events of the site
+func (s *Server) Subscribers(siteName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, site := range s.streams {
+		if site == siteName {
+			n++
+		}
+	}
+	return n
+}
+
+// DropStreams closes all event websockets, to exercise reconnects
+func (s *Server) DropStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.streams {
+		conn.Close()
+		delete(s.streams, conn)
+	}
+}